@@ -0,0 +1,82 @@
+package anansi
+
+import "github.com/jcorbin/anansi/ansi"
+
+// IsAlternate returns true if the alternate screen buffer is active, as set
+// by processing ModeAlternateScreen (or its older ModeAlternateBufferClear and
+// ModeAlternateBuffer variants).
+func (sc *Screen) IsAlternate() bool { return sc.alt }
+
+// processModes handles screen buffer and cursor modes, and then passes the
+// sequence on to Cursor for any modes that it implements. Returns false if any
+// mode is implemented by neither.
+func (sc *Screen) processModes(e ansi.Escape, a []byte) bool {
+	var tmp [8]ansi.Mode
+	modes, err := ansi.DecodeModes(a, tmp[:0]) // applied up to any invalid parameter
+	set := e == ansi.SM
+	handled := err == nil
+	for _, mode := range modes {
+		switch mode {
+		case ansi.ModeAlternateBuffer: // 47: switch buffers
+			sc.setAlternate(set)
+
+		case ansi.ModeAlternateBufferClear: // 1047: switch, clearing when leaving
+			if !set && sc.alt {
+				sc.clearGrid()
+			}
+			sc.setAlternate(set)
+
+		case ansi.ModeSaveCursor: // 1048: save or restore cursor
+			sc.saveCursor(set)
+
+		case ansi.ModeAlternateScreen: // 1049: save cursor, switch, clear alternate
+			if set {
+				if !sc.alt {
+					sc.saveCursor(true)
+					sc.setAlternate(true)
+					sc.clearGrid()
+				}
+			} else if sc.alt {
+				sc.setAlternate(false)
+				sc.saveCursor(false)
+			}
+
+		case ansi.ModeAutoWrap: // DECAWM
+			sc.noAutoWrap = !set
+
+		case ansi.ModeOrigin: // DECOM; also homes the cursor
+			sc.origin = set
+			sc.home()
+
+		case ansi.ShowCursor: // handled by Cursor
+
+		default:
+			handled = false
+		}
+	}
+	sc.Cursor.processEscape(e, a, sc.clamp)
+	return handled
+}
+
+// setAlternate switches to the alternate or primary buffer, allocating the
+// alternate buffer when first needed; each buffer has its own saved cursor.
+func (sc *Screen) setAlternate(alt bool) {
+	if sc.alt == alt {
+		return
+	}
+	full := sc.Grid.Full()
+	if size := full.Bounds().Size(); sc.other.Bounds().Size() != size {
+		sc.other.Resize(size)
+		sc.otherWrapped = resizeWrapped(sc.otherWrapped, size.Y)
+	}
+	sc.Grid, sc.other = sc.other, full
+	sc.wrapped, sc.otherWrapped = sc.otherWrapped, sc.wrapped
+	sc.saved, sc.otherSaved = sc.otherSaved, sc.saved
+	sc.alt = alt
+}
+
+// clearGrid erases the entire active buffer.
+func (sc *Screen) clearGrid() {
+	sc.clearRegion(0, len(sc.Rune))
+	clearWrapped(sc.wrapped)
+}
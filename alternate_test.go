@@ -0,0 +1,76 @@
+package anansi_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jcorbin/anansi"
+	"github.com/jcorbin/anansi/ansi"
+)
+
+func TestScreen_alternate(t *testing.T) {
+	var vs anansi.VirtualScreen
+	vs.Scrollback = &anansi.Scrollback{Limit: 10}
+	vs.Resize(image.Pt(4, 2))
+	vs.WriteString("ab\r\ncd")
+
+	// 1049 saves the cursor, and switches to a cleared alternate screen
+	vs.WriteString("\x1b[?1049h")
+	assert.True(t, vs.IsAlternate())
+	assert.Equal(t, "        ", gridString(vs.Grid))
+	vs.WriteString("\x1b[Hvim\r\nvim\r\nvim")
+	assert.Equal(t, "vim vim ", gridString(vs.Grid))
+	assert.Equal(t, 0, vs.Scrollback.Len(), "alternate screen shouldn't scroll into history")
+
+	// ...and restores both when leaving
+	vs.WriteString("\x1b[?1049l")
+	assert.False(t, vs.IsAlternate())
+	assert.Equal(t, "ab  cd  ", gridString(vs.Grid))
+	assert.Equal(t, ansi.Pt(3, 2), vs.Cursor.Point)
+
+	// 47 switches without clearing or saving the cursor
+	vs.WriteString("\x1b[?47h")
+	assert.True(t, vs.IsAlternate())
+	assert.Equal(t, "vim vim ", gridString(vs.Grid))
+	vs.WriteString("\x1b[1;1H")
+	vs.WriteString("\x1b[?47l")
+	assert.False(t, vs.IsAlternate())
+	assert.Equal(t, ansi.Pt(1, 1), vs.Cursor.Point)
+
+	// 1047 clears the alternate screen when leaving it
+	vs.WriteString("\x1b[?1047h")
+	assert.Equal(t, "vim vim ", gridString(vs.Grid))
+	vs.WriteString("\x1b[?1047l\x1b[?47h")
+	assert.Equal(t, "        ", gridString(vs.Grid))
+	vs.WriteString("\x1b[?47l")
+
+	// resizing resizes both buffers
+	vs.Resize(image.Pt(3, 3))
+	vs.WriteString("\x1b[?1049h")
+	assert.Equal(t, image.Pt(3, 3), vs.Bounds().Size())
+	vs.WriteString("\x1b[?1049l")
+	assert.Equal(t, image.Pt(3, 3), vs.Bounds().Size())
+
+	// modes before an invalid parameter still apply
+	assert.False(t, vs.Emulate(ansi.SM, []byte("?1049;bogus")))
+	assert.True(t, vs.IsAlternate())
+	assert.False(t, vs.Emulate(ansi.RM, []byte("?25;1049;bogus")))
+	assert.False(t, vs.Cursor.Visible)
+	assert.False(t, vs.IsAlternate())
+}
+
+func TestScreen_alternate_savedCursor(t *testing.T) {
+	var vs anansi.VirtualScreen
+	vs.Resize(image.Pt(10, 4))
+
+	// each buffer has its own saved cursor, so a DECSC on the alternate
+	// screen doesn't clobber the one saved by 1049
+	vs.WriteString("\x1b[3;5Hab\x1b[?1049h\x1b[1;1H\x1b7x\x1b[?1049l")
+	assert.Equal(t, ansi.Pt(7, 3), vs.Cursor.Point)
+
+	// ...while the alternate screen's is kept for its next DECRC
+	vs.WriteString("\x1b[?47h\x1b8")
+	assert.Equal(t, ansi.Pt(1, 1), vs.Cursor.Point)
+}
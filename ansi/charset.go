@@ -0,0 +1,67 @@
+package ansi
+
+// Charset identifies an ISO-2022 character set by the final byte used to
+// designate it into one of the G0-G3 slots, e.g. "ESC ( 0" designates
+// CharsetDECSpecial into G0.
+type Charset byte
+
+// Charset constants; the zero Charset is treated as CharsetASCII.
+const (
+	CharsetUK         Charset = 'A' // United Kingdom
+	CharsetASCII      Charset = 'B' // United States (USASCII)
+	CharsetDECSpecial Charset = '0' // DEC Special Character and Line Drawing Set
+)
+
+// Locking shift escapes that invoke G2 or G3 into GL; SI and SO invoke G0 and
+// G1, while SS2 and SS3 invoke G2 or G3 for only the next character.
+var (
+	LS2 = ESC('n')
+	LS3 = ESC('o')
+)
+
+var decSpecialGraphics = [...]rune{
+	' ',                                    // _ blank
+	'◆', '▒', '␉', '␌', '␍', '␊', '°', '±', // ` a b c d e f g
+	'␤', '␋', '┘', '┐', '┌', '└', '┼', '⎺', // h i j k l m n o
+	'⎻', '─', '⎼', '⎽', '├', '┤', '┴', '┬', // p q r s t u v w
+	'│', '≤', '≥', 'π', '≠', '£', '·', // x y z { | } ~
+}
+
+// Translate maps a rune received while the charset is invoked to its Unicode
+// equivalent; runes outside of the charset's 94 graphic characters are
+// returned unchanged.
+func (cs Charset) Translate(r rune) rune {
+	switch cs {
+	case CharsetUK:
+		if r == '#' {
+			return '£'
+		}
+	case CharsetDECSpecial:
+		if i := int(r) - '_'; i >= 0 && i < len(decSpecialGraphics) {
+			return decSpecialGraphics[i]
+		}
+	}
+	return r
+}
+
+// DecodeCharsetDesignation decodes an SCS (Select Character Set) escape
+// sequence, such as "ESC ( 0", returning the designated slot (0-3 for G0-G3)
+// and charset. Returns false if the escape is not a designation.
+func DecodeCharsetDesignation(id Escape, a []byte) (g int, cs Charset, ok bool) {
+	if len(a) != 1 {
+		return 0, 0, false
+	}
+	switch id {
+	case ESC('('):
+		g = 0
+	case ESC(')'), ESC('-'):
+		g = 1
+	case ESC('*'), ESC('.'):
+		g = 2
+	case ESC('+'), ESC('/'):
+		g = 3
+	default:
+		return 0, 0, false
+	}
+	return g, Charset(a[0]), true
+}
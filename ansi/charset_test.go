@@ -0,0 +1,49 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jcorbin/anansi/ansi"
+)
+
+func TestCharset_Translate(t *testing.T) {
+	for _, tc := range []struct {
+		cs  ansi.Charset
+		in  string
+		out string
+	}{
+		{ansi.CharsetASCII, "lqk#", "lqk#"},
+		{0, "lqk#", "lqk#"},
+		{ansi.CharsetUK, "lqk#", "lqk£"},
+		{ansi.CharsetDECSpecial, "lqkx mjA_~", "┌─┐│ └┘A ·"},
+	} {
+		var out []rune
+		for _, r := range tc.in {
+			out = append(out, tc.cs.Translate(r))
+		}
+		assert.Equal(t, tc.out, string(out), "%q", tc.cs)
+	}
+}
+
+func TestDecodeCharsetDesignation(t *testing.T) {
+	for _, tc := range []struct {
+		in string
+		g  int
+		cs ansi.Charset
+		ok bool
+	}{
+		{"\x1b(0", 0, ansi.CharsetDECSpecial, true},
+		{"\x1b)B", 1, ansi.CharsetASCII, true},
+		{"\x1b*A", 2, ansi.CharsetUK, true},
+		{"\x1b+0", 3, ansi.CharsetDECSpecial, true},
+		{"\x1b#8", 0, 0, false},
+	} {
+		e, a, _ := ansi.DecodeEscape([]byte(tc.in))
+		g, cs, ok := ansi.DecodeCharsetDesignation(e, a)
+		assert.Equal(t, tc.ok, ok, "%q", tc.in)
+		assert.Equal(t, tc.g, g, "%q", tc.in)
+		assert.Equal(t, tc.cs, cs, "%q", tc.in)
+	}
+}
@@ -11,8 +11,9 @@ const (
 )
 
 // Seq represents an escape sequence, led either by ESC or CSI control
-// sequence for writing to some output. May only be constructed by any of the
-// Escape.With family of methods.
+// sequence for writing to some output; string control sequences (e.g. OSC) are
+// also supported, and are terminated by ST when written. May only be
+// constructed by any of the Escape.With family of methods.
 type Seq struct {
 	id Escape
 
@@ -29,7 +30,8 @@ type Seq struct {
 func (id Escape) seq() Seq {
 	switch {
 	case 0x0000 < id && id < 0x001F,
-		0xEF00 < id && id < 0xEFFF:
+		0xEF00 < id && id < 0xEFFF,
+		id.IsString():
 		return Seq{id: id}
 	}
 	panic(fmt.Sprintf("not an Control or Escape rune: %U", id))
@@ -170,6 +172,10 @@ func (seq Seq) AppendTo(p []byte) []byte {
 		p = append(p, '\x1b')
 		p = seq.appendArgBytes(p)
 		p = append(p, byte(id&0x7F))
+	case id.IsString(): // DCS, SOS, OSC, PM, APC
+		p = append(p, '\x1b', byte(0x40|id&0x1F))
+		p = seq.appendArgBytes(p)
+		p = append(p, "\x1b\\"...)
 	case 0xEF20 < id && id < 0xEF2F: // ESC character set control
 		// NOTE character set selection sequences are special, in that they're
 		// always a 3 byte sequence, and identified by the first
@@ -0,0 +1,123 @@
+package ansi
+
+import (
+	"bytes"
+	"encoding/hex"
+)
+
+// QuerySetting returns a DECRQSS (Request Selection or Setting) control
+// string that asks the terminal to report the current state of the control
+// function named by the given intermediate and final bytes, e.g. "m" for SGR,
+// "r" for DECSTBM, or " q" for DECSCUSR; see DecodeSettingReply.
+func QuerySetting(setting string) Seq {
+	return DCS.With(append([]byte("$q"), setting...)...)
+}
+
+// IsSettingReply returns true if the given escape and argument look like a
+// DECRQSS reply, suitable for use as a Term.Query match function.
+func IsSettingReply(e Escape, a []byte) bool {
+	return e == DCS && len(a) >= 3 &&
+		(a[0] == '0' || a[0] == '1') &&
+		a[1] == '$' && a[2] == 'r'
+}
+
+// DecodeSettingReply decodes a DECRQSS reply argument. If the terminal
+// reported the request as valid, the setting is returned as the control
+// sequence that would restore it, e.g. a reply of "1$r0;1m" returns SGR with
+// argument "0;1" (which may then be decoded with DecodeSGR).
+func DecodeSettingReply(a []byte) (valid bool, e Escape, arg []byte, err error) {
+	if !IsSettingReply(DCS, a) {
+		return false, 0, nil, errSyntax
+	}
+	valid, a = a[0] == '1', a[3:]
+	if !valid {
+		return false, 0, nil, nil
+	}
+	if len(a) == 0 {
+		return false, 0, nil, errSyntax
+	}
+	final := a[len(a)-1]
+	if final < 0x40 || final > 0x7E {
+		return false, 0, nil, errSyntax
+	}
+	if arg = a[:len(a)-1]; len(arg) == 0 {
+		arg = nil
+	}
+	return true, CSI(final), arg, nil
+}
+
+// QueryTermcap returns an XTGETTCAP control string that asks the terminal to
+// report the value of one or more of its terminfo (or termcap) capabilities;
+// see DecodeTermcapReply. Since terminals differ in how they reply about
+// multiple capabilities (some stop at the first unknown one), callers wanting
+// robust results should send a separate query for each name.
+func QueryTermcap(names ...string) Seq {
+	p := []byte("+q")
+	for i, name := range names {
+		if i > 0 {
+			p = append(p, ';')
+		}
+		p = appendHex(p, name)
+	}
+	return DCS.With(p...)
+}
+
+// IsTermcapReply returns true if the given escape and argument look like an
+// XTGETTCAP reply, suitable for use as a Term.Query match function.
+func IsTermcapReply(e Escape, a []byte) bool {
+	return e == DCS && len(a) >= 3 &&
+		(a[0] == '0' || a[0] == '1') &&
+		a[1] == '+' && a[2] == 'r'
+}
+
+// DecodeTermcapReply decodes an XTGETTCAP reply argument, returning whether
+// the terminal recognized the requested capabilities, and each reported
+// capability name and value. Boolean capabilities have an empty value, as do
+// any names reported as invalid.
+func DecodeTermcapReply(a []byte) (valid bool, names, values []string, err error) {
+	if !IsTermcapReply(DCS, a) {
+		return false, nil, nil, errSyntax
+	}
+	valid, a = a[0] == '1', a[3:]
+	for len(a) > 0 {
+		var field []byte
+		if i := bytes.IndexByte(a, ';'); i >= 0 {
+			field, a = a[:i], a[i+1:]
+		} else {
+			field, a = a, nil
+		}
+		var name, value []byte
+		if i := bytes.IndexByte(field, '='); i >= 0 {
+			name, value = field[:i], field[i+1:]
+		} else {
+			name = field
+		}
+		n, err := decodeHex(name)
+		if err != nil {
+			return false, nil, nil, err
+		}
+		v, err := decodeHex(value)
+		if err != nil {
+			return false, nil, nil, err
+		}
+		names = append(names, n)
+		values = append(values, v)
+	}
+	return valid, names, values, nil
+}
+
+func appendHex(p []byte, s string) []byte {
+	const digits = "0123456789ABCDEF"
+	for i := 0; i < len(s); i++ {
+		p = append(p, digits[s[i]>>4], digits[s[i]&0xf])
+	}
+	return p
+}
+
+func decodeHex(p []byte) (string, error) {
+	b := make([]byte, hex.DecodedLen(len(p)))
+	if _, err := hex.Decode(b, p); err != nil {
+		return "", errSyntax
+	}
+	return string(b), nil
+}
@@ -0,0 +1,69 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi/ansi"
+)
+
+func TestDCS(t *testing.T) {
+	assert.Equal(t, "\x1bP$qm\x1b\\", string(ansi.QuerySetting("m").AppendTo(nil)))
+	assert.Equal(t, "\x1bP$q q\x1b\\", string(ansi.QuerySetting(" q").AppendTo(nil)))
+	assert.Equal(t, "\x1bP+q544E\x1b\\", string(ansi.QueryTermcap("TN").AppendTo(nil)))
+	assert.Equal(t, "\x1bP+q636F6C6F7273;524742\x1b\\", string(ansi.QueryTermcap("colors", "RGB").AppendTo(nil)))
+}
+
+func TestDecodeSettingReply(t *testing.T) {
+	for _, tc := range []struct {
+		in    string
+		valid bool
+		e     ansi.Escape
+		arg   string
+		err   bool
+	}{
+		{in: "1$r0;1m", valid: true, e: ansi.SGR, arg: "0;1"},
+		{in: "1$r1;24r", valid: true, e: ansi.DECSTBM, arg: "1;24"},
+		{in: "1$r2 q", valid: true, e: ansi.CSI('q'), arg: "2 "},
+		{in: "1$rm", valid: true, e: ansi.SGR},
+		{in: "0$r"},
+		{in: "1$r", err: true},
+		{in: "1$r0;", err: true},
+		{in: "1+r", err: true},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			valid, e, arg, err := ansi.DecodeSettingReply([]byte(tc.in))
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.valid, valid)
+			assert.Equal(t, tc.e, e)
+			assert.Equal(t, tc.arg, string(arg))
+		})
+	}
+}
+
+func TestDecodeTermcapReply(t *testing.T) {
+	valid, names, values, err := ansi.DecodeTermcapReply([]byte("1+r544E=787465726D;524742"))
+	require.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, []string{"TN", "RGB"}, names)
+	assert.Equal(t, []string{"xterm", ""}, values)
+
+	valid, names, _, err = ansi.DecodeTermcapReply([]byte("0+r6E6F7065"))
+	require.NoError(t, err)
+	assert.False(t, valid)
+	assert.Equal(t, []string{"nope"}, names)
+
+	_, _, _, err = ansi.DecodeTermcapReply([]byte("1+r5Z"))
+	assert.Error(t, err, "expected invalid hex error")
+
+	assert.True(t, ansi.IsTermcapReply(ansi.DCS, []byte("0+r")))
+	assert.False(t, ansi.IsTermcapReply(ansi.DCS, []byte("1$r0m")))
+	assert.True(t, ansi.IsSettingReply(ansi.DCS, []byte("1$r0m")))
+	assert.False(t, ansi.IsSettingReply(ansi.OSC, []byte("1$r0m")))
+}
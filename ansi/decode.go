@@ -2,7 +2,6 @@ package ansi
 
 import (
 	"errors"
-	"fmt"
 	"image"
 	"unicode/utf8"
 )
@@ -39,180 +38,69 @@ func DecodeRune(p []byte) (rune, int) {
 // UTF-8 rune from p[n:]; if this rune turns out to be ESCape (U+001B), the
 // caller MAY decide either to process it immediately, or whether to wait for
 // additional input bytes which may complete an ESCape sequence.
+//
+// DecodeEscape is a stateless wrapper around Decoder, which it runs from
+// scratch over p. Since it can only return one thing at a time, it handles the
+// following cases by rearranging or rewriting bytes within p:
+//   - a control or rune interleaved within a sequence is moved ahead of it,
+//     so that the caller decodes it first, and then the sequence;
+//   - an ESC+0x40 through 0x5F that doesn't start a sequence is rewritten into
+//     the UTF-8 encoding of the corresponding C1 control.
+//
+// A sequence abandoned for another (e.g. "ESC ESC [ A") is left in place for
+// the caller to decode its first rune literally, while one cancelled by CAN or
+// SUB is consumed along with the cancelling control. A malformed sequence
+// that is ignored (e.g. "CSI 3 ? 1 m") is likewise consumed, returning a zero
+// Escape.
 func DecodeEscape(p []byte) (e Escape, arg []byte, n int) {
 	r, m := DecodeRune(p)
-	if r == 0x1B {
-		return decodeESC(p)
-	}
 	switch r {
-	case 0x9B: // CSI
-		if cse, csa, csn := decodeCSI(p[m:]); cse != 0 {
-			return cse, csa, m + csn
-		}
-	case 0x90: // DCS
-		// TODO stricter DCS state machine per vt100.net
-		if sa, sn := decodeString(p[m:]); sn > 0 {
-			return Escape(r), sa, m + sn
-		}
-	case 0x9D: // OSC
-		if sa, sn := decodeString(p[m:]); sn > 0 {
-			return Escape(r), sa, m + sn
-		}
-		// TODO linux compat handling for OSC
-	case 0x9E, 0x9F: // PM, APC
-		if sa, sn := decodeString(p[m:]); sn > 0 {
-			return Escape(r), sa, m + sn
+	case 0x1B, 0x90, 0x98, 0x9B, 0x9D, 0x9E, 0x9F: // ESC, DCS, SOS, CSI, OSC, PM, APC
+	default:
+		if p[0] == 0x1B {
+			// Encode translated C1 control character so that caller can act on it.
+			utf8.EncodeRune(p[:m], r)
 		}
-	}
-	if p[0] == 0x1B {
-		// Encode translated C1 control character so that caller can act on it.
-		utf8.EncodeRune(p[:m], r)
-	}
-	return 0, nil, 0
-}
-
-func decodeESC(p []byte) (e Escape, a []byte, n int) {
-	// NOTE caller ensures p[0] == 0x1B
-	n++ // count the escape byte as consumed
-	ei, ai, ni := 0, 0, 1
-
-	// shuffle bogus bytes out of an escape sequence so that the user can
-	// process them (i.e. a control character or high rune)
-	rshift := func(m int) {
-		var tmp [4]byte
-		copy(tmp[:], p[ni:ni+m])
-		copy(p[ei+m:ni+m], p[ei:])
-		copy(p[ei:], tmp[:m])
+		return 0, nil, 0
 	}
 
-	for {
-		// ei : index of escape byte
-		// ai : index of first arg byte; if 0, no arg
-		// ni : index of next byte to process
-
-		// TODO consider recognizing raw C1 control bytes, rather than letting
-		// them result in a RuneError through utf8.DecodeRune
-
-		// decode and process the next rune
-		r, m := utf8.DecodeRune(p[ni:])
-		switch {
-		case r == utf8.RuneError: // may be fixed by more bytes, caller can choose
-			return 0, nil, ei
-		case r > 0xFF: // higher codepoint not part of an escape sequence
-			rshift(m)
-			return 0, nil, ei
-		case 0x80 <= r && r <= 0xFF: // C1 and G1: Treat the same as their 7-bit counterparts
-			r &= 0x7F
-		}
-		if m > 2 {
-			panic(fmt.Sprintf("inconceivable: can't decode an ascii rune from %v bytes", m))
+	var dec Decoder
+	for i := 0; ; {
+		r, m, ok := dec.next(p[i:])
+		if !ok {
+			return 0, nil, 0
 		}
-		n += m
-
-		// dispatch the rune, now known <= utf8.RuneSelf
-		switch {
-		case 0x30 <= r && r <= 0x7E: // End of an escape sequence.
-			if ai != 0 {
-				if ni-ai == 1 && 0x20 <= p[ai] && p[ai] <= 0x2F {
-					// name the character selection block after its
-					// intermediate byte, rather than its parameter
-					return ESC(p[ai]), p[ni : ni+m], n
-				}
-				a = p[ai:ni]
+		e, a, act := dec.advance(r)
+		switch act {
+		case decodeNone:
+			if !dec.Pending() {
+				// an ignored sequence ended without dispatching
+				return 0, nil, i + m
 			}
-			return ESC(byte(r)), a, n
-
-		case 0x20 <= r && r <= 0x2F: // Intermediate: Expect zero or more intermediates...
-			if ai == 0 {
-				ai = ni
+		case decodeDispatch:
+			return e, a, i + m
+		case decodeTerminate:
+			return e, a, i
+		case decodeRestart:
+			return 0, nil, 0
+		case decodeEmit:
+			// shuffle interleaved bytes out of the sequence, so that the caller
+			// can process them (i.e. a control character or high rune)
+			var tmp [utf8.UTFMax]byte
+			copy(tmp[:], p[i:i+m])
+			copy(p[m:i+m], p[:i])
+			copy(p, tmp[:m])
+			return 0, nil, 0
+		case decodeAbort:
+			if r == 0x18 || r == 0x1A {
+				return 0, nil, i + m
 			}
-			ni += m
-			continue
-
-		case r == 0x7F: // Delete: Ignore it, and continue interpreting the ESCape sequence
-			r = 0
-			rshift(m)
-			ei += m
-			ni += m
-			if ai != 0 {
-				ai += m
+			if p[i] == 0x1B {
+				utf8.EncodeRune(p[i:i+m], r)
 			}
-			continue
-
-		case r == 0x18: // CANcel the current ESCape sequence
-			// TODO could provide visibility
-			return 0, nil, ni + m
-
-		case r <= 0x1f: // C0 control: Interpret it first, then resume processing ESCape sequence.
-			rshift(m)
-			return 0, nil, ei // return normalized control character to user
-
-		default:
-			panic("inconceivable: exhaustive 7-bit switch wasn't")
-		}
-	}
-}
-
-func decodeCSI(p []byte) (e Escape, a []byte, n int) {
-	// 1. It starts with `CSI`, the Control Sequence Introducer.
-
-	// TODO this could be stricter per the vt100.net state diagram
-	// TODO compat CSI M CbCxCy
-
-	ni, ai := 0, -1
-
-	for ; ni < len(p); ni++ {
-		switch c := p[ni]; {
-		case 0x30 <= c && c <= 0x3F:
-			// 2. It contains any number of parameter characters: `0123456789:;<=>?`.
-			if ai == -1 {
-				ai = ni
-			}
-		case 0x40 <= c && c <= 0x7E:
-			// 3. It terminates with an alphabetic character.
-			goto term
-
-		case 0x20 <= c && c <= 0x2F:
-			// 4. Intermediate characters (if any) immediately precede the terminator.
-			if ai == -1 {
-				ai = ni
-			}
-			ni++
-			goto intermed
+			return 0, nil, i
 		}
-	}
-	return 0, nil, 0
-
-intermed:
-	for ; ni < len(p); ni++ {
-		switch c := p[ni]; {
-		case 0x20 <= c && c <= 0x2F:
-			// 4. Intermediate characters (if any) immediately precede the terminator.
-		case 0x40 <= c && c <= 0x7E:
-			// 3. It terminates with an alphabetic character.
-			goto term
-		}
-	}
-	return 0, nil, 0
-
-term:
-	if ai >= 0 {
-		a = p[ai:ni]
-	}
-	return CSI(p[ni]), a, ni + 1
-}
-
-func decodeString(p []byte) (a []byte, n int) {
-	r, m := DecodeRune(p)
-	for {
-		switch r {
-		case utf8.RuneError:
-			return nil, 0
-		case 0x9C:
-			return p[:n], n + m
-		}
-		n += m
-		r, m = DecodeRune(p[n:])
+		i += m
 	}
 }
 
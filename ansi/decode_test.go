@@ -117,6 +117,24 @@ func TestDecodeEscape(t *testing.T) {
 			{anRead{}, utRead{'d', 1}},
 		}},
 
+		{"\x1b[3\r1mx", []ev{
+			{anRead{}, utRead{'\r', 1}},
+			{anRead{ansi.Escape(0xEFED), []byte("31"), 5}, utRead{}},
+			{anRead{}, utRead{'x', 1}},
+		}},
+
+		{"\x1b[3?1mx", []ev{
+			{anRead{0, nil, 6}, utRead{'x', 1}},
+		}},
+		{"\x1b[1<2mX", []ev{
+			{anRead{0, nil, 6}, utRead{'X', 1}},
+		}},
+
+		{"\x1b]0;hi\a!", []ev{
+			{anRead{ansi.Escape(0x9D), []byte("0;hi"), 7}, utRead{}},
+			{anRead{}, utRead{'!', 1}},
+		}},
+
 		{"(\x1bPdemo\x1b\\)", []ev{
 			{anRead{}, utRead{'(', 1}},
 			{anRead{ansi.Escape(0x90), []byte("demo"), 8}, utRead{}},
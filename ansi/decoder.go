@@ -0,0 +1,362 @@
+package ansi
+
+import "unicode/utf8"
+
+// Decoder implements the DEC ANSI parser state machine described at
+// https://vt100.net/emu/dec_ansi_parser as a resumable streaming decoder.
+//
+// Unlike DecodeEscape, a Decoder retains the state of any partially decoded
+// sequence between calls, so that callers may discard consumed input bytes
+// without needing to re-scan them once more bytes arrive.
+//
+// Input is decoded as UTF-8, with the following mapping onto the byte-oriented
+// state machine:
+//   - ESC followed by any byte from 0x40 through 0x5F is treated as the
+//     corresponding C1 control, as are the runes U+0080 through U+009F.
+//   - Runes U+00A0 through U+00FF are treated as their 7-bit counterparts
+//     within escape and control sequence headers.
+//   - Runes above U+00FF (and invalid bytes) within escape and control
+//     sequences are decoded as if they preceded the sequence, which then
+//     continues.
+//
+// The following deviations from the DEC parser are deliberate, to match
+// current terminal practice:
+//   - DEL is decoded as a control in the ground state, rather than ignored.
+//   - ':' is accepted as a parameter byte, to support sub-parameters.
+//   - OSC strings may be terminated by BEL, as in xterm.
+//   - SOS, PM, and APC strings are collected, rather than ignored.
+//   - CAN and SUB abort any control string, rather than dispatching it.
+//   - A control string interrupted by ESC, or by any C1 control other than
+//     ST, is dispatched as if it had been terminated.
+type Decoder struct {
+	state decodeState
+	id    Escape
+	arg   []byte
+}
+
+type decodeState uint8
+
+const (
+	decodeGround decodeState = iota
+	decodeEscape
+	decodeEscapeIntermediate
+	decodeCSIEntry
+	decodeCSIParam
+	decodeCSIIntermediate
+	decodeCSIIgnore
+	decodeDCSEntry
+	decodeDCSParam
+	decodeDCSIntermediate
+	decodeDCSPassthrough
+	decodeDCSIgnore
+	decodeOSCString
+	decodeSOSPMAPCString
+)
+
+var decodeStateNames = [...]string{
+	"ground",
+	"escape",
+	"escape_intermediate",
+	"csi_entry",
+	"csi_param",
+	"csi_intermediate",
+	"csi_ignore",
+	"dcs_entry",
+	"dcs_param",
+	"dcs_intermediate",
+	"dcs_passthrough",
+	"dcs_ignore",
+	"osc_string",
+	"sos_pm_apc_string",
+}
+
+func (st decodeState) String() string { return decodeStateNames[st] }
+
+func (st decodeState) isString() bool {
+	return st == decodeDCSPassthrough || st == decodeOSCString || st == decodeSOSPMAPCString
+}
+
+// decodeAction is the result of advancing the Decoder by one rune.
+type decodeAction uint8
+
+const (
+	// decodeNone means the rune was absorbed into (or ignored by) the
+	// current sequence.
+	decodeNone decodeAction = iota
+
+	// decodeEmit means the rune should be passed through to the caller; if
+	// the decoder isn't in the ground state, then it's interleaved within a
+	// sequence that continues.
+	decodeEmit
+
+	// decodeDispatch means that a complete sequence has been decoded.
+	decodeDispatch
+
+	// decodeTerminate means that a control string was terminated by the
+	// rune, which has not been consumed, and should be decoded next.
+	decodeTerminate
+
+	// decodeAbort means that a pending sequence was aborted by the rune,
+	// which should be passed through to the caller.
+	decodeAbort
+
+	// decodeRestart means that a pending sequence was abandoned, since the
+	// rune introduced a new one.
+	decodeRestart
+)
+
+// Reset discards any partially decoded sequence, returning the decoder to its
+// ground state.
+func (dec *Decoder) Reset() {
+	dec.state = decodeGround
+	dec.id = 0
+	dec.arg = dec.arg[:0]
+}
+
+// Pending returns true if the decoder holds a partially decoded sequence.
+func (dec *Decoder) Pending() bool {
+	return dec.state != decodeGround
+}
+
+// Decode decodes the next rune, control, escape sequence, control sequence, or
+// control string from p, returning the number of bytes consumed. The ok return
+// value is true only if something was decoded; otherwise all n bytes have
+// been consumed into a pending sequence, and decoding should resume with
+// additional input.
+//
+// The caller should call e.IsEscape() (or e.IsString()) to tell the
+// difference between sequences and normal runes or controls; see
+// Input.Decode. Any returned argument slice is only valid until the next call
+// to Decode.
+//
+// An ESC byte at the end of p is not consumed, since it may be the first byte
+// of a two byte C1 control; callers that wish to treat it as literal (e.g. as
+// an escape key press) may decode it themselves.
+func (dec *Decoder) Decode(p []byte) (e Escape, a []byte, n int, ok bool) {
+	for n < len(p) {
+		r, m, ok := dec.next(p[n:])
+		if !ok {
+			break
+		}
+		var act decodeAction
+		e, a, act = dec.advance(r)
+		switch act {
+		case decodeTerminate:
+			return e, a, n, true
+		case decodeEmit, decodeDispatch, decodeAbort:
+			return e, a, n + m, true
+		}
+		n += m
+	}
+	return 0, nil, n, false
+}
+
+// next decodes the next input rune from p, normalizing ESC+0x40 through 0x5F
+// into C1 controls; returns false if p doesn't hold a complete rune.
+func (dec *Decoder) next(p []byte) (r rune, m int, ok bool) {
+	if len(p) == 0 {
+		return 0, 0, false
+	}
+	if p[0] == 0x1B {
+		if len(p) < 2 {
+			return 0, 0, false
+		}
+		if c := p[1]; 0x40 <= c && c <= 0x5F {
+			return 0x80 | rune(c&0x1F), 2, true
+		}
+		return 0x1B, 1, true
+	}
+	r, m = utf8.DecodeRune(p)
+	if r == utf8.RuneError && m <= 1 && !utf8.FullRune(p) {
+		return 0, 0, false
+	}
+	return r, m, true
+}
+
+// advance the state machine by one input rune.
+func (dec *Decoder) advance(r rune) (e Escape, a []byte, act decodeAction) {
+	st := dec.state
+
+	// transitions from anywhere
+	switch {
+	case r == 0x18, r == 0x1A: // CAN, SUB
+		dec.Reset()
+		if st == decodeGround {
+			return Escape(r), nil, decodeEmit
+		}
+		return Escape(r), nil, decodeAbort
+
+	case r == 0x1B, 0x80 <= r && r <= 0x9F:
+		if st.isString() && r != 0x9C {
+			return dec.dispatch(0, decodeTerminate)
+		}
+		switch r {
+		case 0x1B:
+			dec.enter(decodeEscape, 0)
+		case 0x90: // DCS
+			dec.enter(decodeDCSEntry, Escape(r))
+		case 0x9B: // CSI
+			dec.enter(decodeCSIEntry, 0)
+		case 0x9D: // OSC
+			dec.enter(decodeOSCString, Escape(r))
+		case 0x98, 0x9E, 0x9F: // SOS, PM, APC
+			dec.enter(decodeSOSPMAPCString, Escape(r))
+		case 0x9C: // ST
+			if st.isString() {
+				return dec.dispatch(0, decodeDispatch)
+			}
+			if st == decodeDCSIgnore {
+				dec.Reset()
+				return 0, nil, decodeNone
+			}
+			fallthrough
+		default:
+			dec.Reset()
+			if st == decodeGround {
+				return Escape(r), nil, decodeEmit
+			}
+			return Escape(r), nil, decodeAbort
+		}
+		if st != decodeGround {
+			return 0, nil, decodeRestart
+		}
+		return 0, nil, decodeNone
+	}
+
+	switch st {
+	case decodeGround:
+		return Escape(r), nil, decodeEmit
+
+	case decodeOSCString:
+		switch {
+		case r == 0x07: // BEL
+			return dec.dispatch(0, decodeDispatch)
+		case r < 0x20, r == 0x7F:
+		default:
+			dec.arg = appendRune(dec.arg, r)
+		}
+		return 0, nil, decodeNone
+
+	case decodeSOSPMAPCString:
+		if r >= 0x20 && r != 0x7F {
+			dec.arg = appendRune(dec.arg, r)
+		}
+		return 0, nil, decodeNone
+
+	case decodeDCSPassthrough:
+		if r != 0x7F {
+			dec.arg = appendRune(dec.arg, r)
+		}
+		return 0, nil, decodeNone
+
+	case decodeDCSIgnore:
+		return 0, nil, decodeNone
+	}
+
+	// within escape and control sequence headers
+	if 0xA0 <= r && r <= 0xFF {
+		r -= 0x80
+	}
+	switch {
+	case r > 0x7F:
+		return Escape(r), nil, decodeEmit
+	case r == 0x7F:
+		return 0, nil, decodeNone
+	case r < 0x20:
+		switch st {
+		case decodeDCSEntry, decodeDCSParam, decodeDCSIntermediate:
+			return 0, nil, decodeNone
+		}
+		return Escape(r), nil, decodeEmit
+	}
+	b := byte(r)
+
+	switch st {
+	case decodeEscape, decodeEscapeIntermediate:
+		if b <= 0x2F {
+			dec.state = decodeEscapeIntermediate
+			dec.arg = append(dec.arg, b)
+			return 0, nil, decodeNone
+		}
+		if len(dec.arg) == 1 {
+			// name the character selection block after its intermediate
+			// byte, rather than its final byte
+			dec.arg[0], b = b, dec.arg[0]
+		}
+		return dec.dispatch(ESC(b), decodeDispatch)
+
+	case decodeCSIEntry, decodeCSIParam, decodeCSIIntermediate, decodeCSIIgnore:
+		if 0x40 <= b {
+			if st == decodeCSIIgnore {
+				dec.Reset()
+				return 0, nil, decodeNone
+			}
+			return dec.dispatch(CSI(b), decodeDispatch)
+		}
+		dec.arg = append(dec.arg, b)
+		dec.state = nextHeaderState(st, b,
+			decodeCSIEntry, decodeCSIParam, decodeCSIIntermediate, decodeCSIIgnore)
+		return 0, nil, decodeNone
+
+	case decodeDCSEntry, decodeDCSParam, decodeDCSIntermediate:
+		dec.arg = append(dec.arg, b)
+		if 0x40 <= b {
+			dec.state = decodeDCSPassthrough
+		} else {
+			dec.state = nextHeaderState(st, b,
+				decodeDCSEntry, decodeDCSParam, decodeDCSIntermediate, decodeDCSIgnore)
+		}
+		return 0, nil, decodeNone
+	}
+
+	panic("inconceivable: exhaustive decoder state switch wasn't")
+}
+
+// nextHeaderState implements the common parameter and intermediate byte
+// transitions of control sequence and device control string headers, given a
+// byte from 0x20 through 0x3F.
+func nextHeaderState(st decodeState, b byte, entry, param, intermed, ignore decodeState) decodeState {
+	switch {
+	case st == ignore:
+		return ignore
+	case b <= 0x2F: // intermediate
+		return intermed
+	case st == intermed:
+		return ignore
+	case b <= 0x3B: // digits, ':', and ';'
+		return param
+	case st == entry: // private marker
+		return param
+	}
+	return ignore
+}
+
+func (dec *Decoder) enter(st decodeState, id Escape) {
+	dec.state = st
+	dec.id = id
+	dec.arg = dec.arg[:0]
+}
+
+// dispatch returns the current sequence, or control string if id is 0, and
+// resets the decoder back to ground.
+func (dec *Decoder) dispatch(id Escape, act decodeAction) (Escape, []byte, decodeAction) {
+	if id == 0 {
+		id = dec.id
+	}
+	a := dec.arg
+	if len(a) == 0 {
+		a = nil
+	}
+	dec.state = decodeGround
+	dec.id = 0
+	dec.arg = dec.arg[:0]
+	return id, a, act
+}
+
+func appendRune(p []byte, r rune) []byte {
+	if r < utf8.RuneSelf {
+		return append(p, byte(r))
+	}
+	var tmp [utf8.UTFMax]byte
+	return append(p, tmp[:utf8.EncodeRune(tmp[:], r)]...)
+}
@@ -0,0 +1,128 @@
+package ansi_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jcorbin/anansi/ansi"
+)
+
+func TestDecoder(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+		out  []string
+	}{
+		{"text", "hi\x7f", []string{"'h'", "'i'", "^?"}},
+		{"controls", "a\r\n\u0085", []string{"'a'", "^M", "^J", "<NEL>"}},
+		{"7-bit C1", "\x1bD", []string{"<IND>"}},
+
+		{"esc", "\x1b=\x1b7", []string{"ESC+=", "ESC+7"}},
+		{"esc charset", "\x1b(B", []string{`ESC+( "B"`}},
+		{"esc intermediates", "\x1b #8\x1b%@", []string{`ESC+8 " #"`, `ESC+% "@"`}},
+		{"esc high", "\x1bø", []string{"ESC+x"}},
+		{"esc del", "\x1b\x7f=", []string{"ESC+="}},
+		{"esc interleaved", "\x1b(\x0e“B", []string{"^N", "'“'", `ESC+( "B"`}},
+		{"esc esc", "\x1b\x1b[A", []string{"CSI+A"}},
+		{"esc can", "\x1b(\x18B", []string{"^X", "'B'"}},
+
+		{"csi", "\x1b[31mx", []string{`CSI+m "31"`, "'x'"}},
+		{"csi 8-bit", "\u009b1;2H", []string{`CSI+H "1;2"`}},
+		{"csi private", "\x1b[?25l", []string{`CSI+l "?25"`}},
+		{"csi intermediate", "\x1b[?1$p", []string{`CSI+p "?1$"`}},
+		{"csi subparams", "\x1b[38:2:1:2:3m", []string{`CSI+m "38:2:1:2:3"`}},
+		{"csi interleaved", "\x1b[3\r1m", []string{"^M", `CSI+m "31"`}},
+		{"csi bad private", "\x1b[3?1mx", []string{"'x'"}},
+		{"csi bad intermediate", "\x1b[ 1mx", []string{"'x'"}},
+		{"csi sub", "\x1b[3\x1a1m", []string{"^Z", "'1'", "'m'"}},
+		{"csi restart", "\x1b[3\x1b[1m", []string{`CSI+m "1"`}},
+		{"csi aborted by C1", "\x1b[3\x1bEx", []string{"<NEL>", "'x'"}},
+
+		{"osc st", "\x1b]2;hi\x1b\\x", []string{`<OSC> "2;hi"`, "'x'"}},
+		{"osc bel", "\x1b]2;hi\ax", []string{`<OSC> "2;hi"`, "'x'"}},
+		{"osc 8-bit", "\u009d0;✓\u009c", []string{`<OSC> "0;✓"`}},
+		{"osc controls", "\x1b]2;h\ri\x1b\\", []string{`<OSC> "2;hi"`}},
+		{"osc esc", "\x1b]2;hi\x1b[Ax", []string{`<OSC> "2;hi"`, "CSI+A", "'x'"}},
+		{"osc can", "\x1b]2;hi\x18x", []string{"^X", "'x'"}},
+
+		{"dcs", "\x1bP1$r0m\x1b\\", []string{`<DCS> "1$r0m"`}},
+		{"dcs data controls", "\x1bPq#0\r\n\x1b\\", []string{"<DCS> \"q#0\\r\\n\""}},
+		{"dcs header controls", "\x1bP1\r$qm\x1b\\", []string{`<DCS> "1$qm"`}},
+		{"dcs ignore", "\x1bP1 2q\x1b\\x", []string{"'x'"}},
+		{"dcs 8-bit", "\u0090+q544e\u009c", []string{`<DCS> "+q544e"`}},
+
+		{"apc", "\x1b_Gi=1;\x1b\\", []string{`<APC> "Gi=1;"`}},
+		{"pm", "\x1b^hi\x1b\\", []string{`<PM> "hi"`}},
+		{"sos", "\x1bXhi\x1b\\", []string{`<RESX> "hi"`}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := []byte(tc.in)
+
+			// all at once
+			var dec ansi.Decoder
+			var out []string
+			for len(p) > 0 {
+				e, a, n, ok := dec.Decode(p)
+				p = p[n:]
+				if !ok {
+					break
+				}
+				out = append(out, fmtDecoded(e, a))
+			}
+			assert.Equal(t, tc.out, out, "expected decoded output")
+			assert.Equal(t, 0, len(p), "expected all input consumed")
+			assert.False(t, dec.Pending(), "expected nothing pending")
+
+			// one byte at a time, as if from a slow stream
+			dec.Reset()
+			out = out[:0]
+			var buf []byte
+			for i := 0; i < len(tc.in); i++ {
+				buf = append(buf, tc.in[i])
+				for len(buf) > 0 {
+					e, a, n, ok := dec.Decode(buf)
+					buf = buf[n:]
+					if !ok {
+						break
+					}
+					out = append(out, fmtDecoded(e, a))
+				}
+			}
+			assert.Equal(t, tc.out, out, "expected same output when streamed")
+		})
+	}
+}
+
+func TestDecoder_pending(t *testing.T) {
+	var dec ansi.Decoder
+
+	_, _, n, ok := dec.Decode([]byte("\x1b"))
+	assert.False(t, ok)
+	assert.Equal(t, 0, n, "expected trailing ESC to remain")
+	assert.False(t, dec.Pending())
+
+	_, _, n, ok = dec.Decode([]byte("\x1b[12;3"))
+	assert.False(t, ok)
+	assert.Equal(t, 6, n, "expected partial sequence to be consumed")
+	assert.True(t, dec.Pending())
+
+	e, a, n, ok := dec.Decode([]byte("4H"))
+	assert.True(t, ok)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, ansi.CUP, e)
+	assert.Equal(t, "12;34", string(a))
+
+	_, _, _, _ = dec.Decode([]byte("\x1b]2;"))
+	assert.True(t, dec.Pending())
+	dec.Reset()
+	assert.False(t, dec.Pending())
+}
+
+func fmtDecoded(e ansi.Escape, a []byte) string {
+	if len(a) > 0 {
+		return fmt.Sprintf("%v %q", e, a)
+	}
+	return e.String()
+}
@@ -0,0 +1,98 @@
+package ansi
+
+import "bytes"
+
+// XTVERSION asks the terminal to report its name and version; it shares its
+// final byte with DECSCUSR and DECLL, distinguished by a '>' prefix.
+var XTVERSION = CSI('q')
+
+// QueryPrimaryDA returns a DA1 (Primary Device Attributes) control sequence,
+// which nearly every terminal answers with its conformance level and features;
+// see DecodeDeviceAttributes.
+func QueryPrimaryDA() Seq { return DA.With() }
+
+// QuerySecondaryDA returns a DA2 (Secondary Device Attributes) control
+// sequence, which asks the terminal to report its type, firmware version, and
+// keyboard (or emulator specific) options; see DecodeDeviceAttributes.
+func QuerySecondaryDA() Seq { return DA.With('>') }
+
+// IsPrimaryDAReply returns true if the given escape and argument look like a
+// DA1 reply, suitable for use as a Term.Query match function.
+func IsPrimaryDAReply(e Escape, a []byte) bool {
+	return e == DA && len(a) > 0 && a[0] == '?'
+}
+
+// IsSecondaryDAReply returns true if the given escape and argument look like
+// a DA2 reply, suitable for use as a Term.Query match function.
+func IsSecondaryDAReply(e Escape, a []byte) bool {
+	return e == DA && len(a) > 0 && a[0] == '>'
+}
+
+// DecodeDeviceAttributes decodes a DA1 or DA2 reply argument, returning its
+// numeric parameters; e.g. "?62;22c" decodes to [62 22].
+func DecodeDeviceAttributes(a []byte) (params []int, err error) {
+	if len(a) == 0 || (a[0] != '?' && a[0] != '>') {
+		return nil, errSyntax
+	}
+	for a = a[1:]; len(a) > 0; {
+		p, n, err := DecodeNumber(a)
+		if err != nil {
+			return params, err
+		}
+		params = append(params, p)
+		a = a[n:]
+	}
+	return params, nil
+}
+
+// QueryVersion returns an XTVERSION control sequence; see DecodeVersionReply.
+func QueryVersion() Seq { return XTVERSION.With('>', '0') }
+
+// IsVersionReply returns true if the given escape and argument look like an
+// XTVERSION reply, suitable for use as a Term.Query match function.
+func IsVersionReply(e Escape, a []byte) bool {
+	return e == DCS && bytes.HasPrefix(a, []byte(">|"))
+}
+
+// DecodeVersionReply decodes an XTVERSION reply argument, returning the
+// terminal's self reported name and version, e.g. "XTerm(388)".
+func DecodeVersionReply(a []byte) (string, error) {
+	if !IsVersionReply(DCS, a) {
+		return "", errSyntax
+	}
+	return string(a[2:]), nil
+}
+
+// QueryCursorPosition returns a DSR control sequence that asks the terminal
+// to report the cursor position; see DecodeCursorPosition.
+func QueryCursorPosition() Seq { return DSR.WithInts(6) }
+
+// IsCursorPositionReply returns true if the given escape and argument look
+// like a CPR reply, suitable for use as a Term.Query match function.
+//
+// NOTE modified F3 key presses are encoded identically (e.g. "CSI 1 ; 2 R"
+// for shift-F3), so any such reply in row 1 is ambiguous.
+func IsCursorPositionReply(e Escape, a []byte) bool {
+	if e != CPR {
+		return false
+	}
+	_, err := DecodeCursorPosition(a)
+	return err == nil
+}
+
+// DecodeCursorPosition decodes a CPR argument, e.g. "24;80" decodes to column
+// 80 of row 24.
+func DecodeCursorPosition(a []byte) (Point, error) {
+	row, n, err := DecodeNumber(a)
+	if err != nil {
+		return ZP, err
+	}
+	col, m, err := DecodeNumber(a[n:])
+	if err != nil {
+		return ZP, err
+	}
+	if n+m != len(a) || a[n] != ';' || row < 1 || col < 1 {
+		return ZP, errSyntax
+	}
+	return Pt(col, row), nil
+}
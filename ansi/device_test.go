@@ -0,0 +1,73 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi/ansi"
+)
+
+func TestDeviceQueries(t *testing.T) {
+	assert.Equal(t, "\x1b[c", string(ansi.QueryPrimaryDA().AppendTo(nil)))
+	assert.Equal(t, "\x1b[>c", string(ansi.QuerySecondaryDA().AppendTo(nil)))
+	assert.Equal(t, "\x1b[>0q", string(ansi.QueryVersion().AppendTo(nil)))
+	assert.Equal(t, "\x1b[6n", string(ansi.QueryCursorPosition().AppendTo(nil)))
+}
+
+func TestDecodeDeviceReplies(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+		e    ansi.Escape
+		a    string
+		test func(t *testing.T, e ansi.Escape, a []byte)
+	}{
+		{"da1", "\x1b[?62;22c", ansi.DA, "?62;22", func(t *testing.T, e ansi.Escape, a []byte) {
+			assert.True(t, ansi.IsPrimaryDAReply(e, a))
+			assert.False(t, ansi.IsSecondaryDAReply(e, a))
+			params, err := ansi.DecodeDeviceAttributes(a)
+			require.NoError(t, err)
+			assert.Equal(t, []int{62, 22}, params)
+		}},
+		{"da2", "\x1b[>41;388;0c", ansi.DA, ">41;388;0", func(t *testing.T, e ansi.Escape, a []byte) {
+			assert.False(t, ansi.IsPrimaryDAReply(e, a))
+			assert.True(t, ansi.IsSecondaryDAReply(e, a))
+			params, err := ansi.DecodeDeviceAttributes(a)
+			require.NoError(t, err)
+			assert.Equal(t, []int{41, 388, 0}, params)
+		}},
+		{"xtversion", "\x1bP>|XTerm(388)\x1b\\", ansi.DCS, ">|XTerm(388)", func(t *testing.T, e ansi.Escape, a []byte) {
+			assert.True(t, ansi.IsVersionReply(e, a))
+			assert.False(t, ansi.IsTermcapReply(e, a))
+			version, err := ansi.DecodeVersionReply(a)
+			require.NoError(t, err)
+			assert.Equal(t, "XTerm(388)", version)
+		}},
+		{"cpr", "\x1b[24;80R", ansi.CPR, "24;80", func(t *testing.T, e ansi.Escape, a []byte) {
+			assert.True(t, ansi.IsCursorPositionReply(e, a))
+			pt, err := ansi.DecodeCursorPosition(a)
+			require.NoError(t, err)
+			assert.Equal(t, ansi.Pt(80, 24), pt)
+		}},
+		{"cpr invalid", "\x1b[24R", ansi.CPR, "24", func(t *testing.T, e ansi.Escape, a []byte) {
+			assert.False(t, ansi.IsCursorPositionReply(e, a))
+			_, err := ansi.DecodeCursorPosition(a)
+			assert.Error(t, err)
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			e, a, n := ansi.DecodeEscape([]byte(tc.in))
+			require.Equal(t, len(tc.in), n)
+			assert.Equal(t, tc.e, e)
+			assert.Equal(t, tc.a, string(a))
+			tc.test(t, e, a)
+		})
+	}
+
+	_, err := ansi.DecodeDeviceAttributes([]byte("62;22"))
+	assert.Error(t, err)
+	_, err = ansi.DecodeVersionReply([]byte("1+r"))
+	assert.Error(t, err)
+}
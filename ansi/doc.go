@@ -4,7 +4,9 @@ escape and control sequences.
 Primary focus is on bridging the ANSI world into the Unicode/UTF-8 world.
 
 ISO-2022 is currently supported minimally and assumed to be in UTF-8 mode at
-all times via the "ESC % G" sequence.
+all times via the "ESC % G" sequence; the only charsets supported for G0-G3
+designation are those still commonly used by programs, such as DEC line
+drawing (see Charset).
 
 */
 package ansi
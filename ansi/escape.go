@@ -70,6 +70,17 @@ func (id Escape) CSI() (byte, bool) {
 	return 0, false
 }
 
+// IsString returns true if the escape value is a C1 control that introduces a
+// control string: DCS, SOS, OSC, PM, or APC. Such control strings carry an
+// argument that is terminated by ST.
+func (id Escape) IsString() bool {
+	switch id {
+	case 0x90, 0x98, 0x9D, 0x9E, 0x9F:
+		return true
+	}
+	return false
+}
+
 // C1Names provides representation names for the C1 extended-ASCII control
 // block.
 var C1Names = []string{
@@ -136,7 +147,8 @@ func (id Escape) String() string {
 
 // IsCharacterSetControl returns true if the escape identifier is a character
 // control rune, or an character set control escape sequence. Such controls can
-// be ignored in a modern UTF-8 terminal.
+// be ignored in a modern UTF-8 terminal, unless emulating one for programs
+// that still use them for line drawing; see Charset.
 func (id Escape) IsCharacterSetControl() bool {
 	switch id {
 	case
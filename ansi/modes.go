@@ -1,5 +1,11 @@
 package ansi
 
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
 // Mode is an ANSI terminal mode constant.
 type Mode uint64
 
@@ -24,10 +30,84 @@ func (mode Mode) Reset() Seq {
 	return RMprivate.WithInts(int(mode & ^ModePrivate))
 }
 
-// private mode constants
-// TODO more coverage
+// IsPrivate returns true if the mode is a DEC private mode, set and reset
+// with a '?' prefix.
+func (mode Mode) IsPrivate() bool { return mode&ModePrivate != 0 }
+
+// Number returns the mode's parameter number, without any private flag.
+func (mode Mode) Number() int { return int(mode & ^ModePrivate) }
+
+// Name returns the mnemonic name of a known mode, or the empty string for an
+// unknown mode.
+func (mode Mode) Name() string { return modeNames[mode] }
+
+// String returns the mnemonic name of a known mode, or its parameter number
+// otherwise (prefixed by '?' if private).
+func (mode Mode) String() string {
+	if name, known := modeNames[mode]; known {
+		return name
+	}
+	if mode.IsPrivate() {
+		return "?" + strconv.Itoa(mode.Number())
+	}
+	return strconv.Itoa(mode.Number())
+}
+
+// ParseMode parses a mode from either its mnemonic name (case-insensitively)
+// or its parameter number (prefixed by '?' if private), as returned by
+// Mode.String.
+func ParseMode(s string) (Mode, error) {
+	if mode, known := modesByName[strings.ToUpper(s)]; known {
+		return mode, nil
+	}
+	var mode Mode
+	if strings.HasPrefix(s, "?") {
+		mode, s = ModePrivate, s[1:]
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, errModeInvalid
+	}
+	return mode | Mode(n), nil
+}
+
+// KnownModes returns all modes with registered names, ANSI modes first, each
+// in parameter number order.
+func KnownModes() []Mode {
+	modes := make([]Mode, 0, len(modeNames))
+	for mode := range modeNames {
+		modes = append(modes, mode)
+	}
+	sort.Slice(modes, func(i, j int) bool { return modes[i] < modes[j] })
+	return modes
+}
+
+// ANSI mode constants.
 const (
-	ModeMouseX10 Mode = 9
+	ModeKeyboardAction Mode = 2  // KAM
+	ModeInsert         Mode = 4  // IRM
+	ModeSendReceive    Mode = 12 // SRM
+	ModeNewline        Mode = 20 // LNM
+)
+
+// DEC private mode constants.
+const (
+	ModeCursorKeys      = ModePrivate | 1  // DECCKM
+	ModeANSI            = ModePrivate | 2  // DECANM
+	ModeColumn          = ModePrivate | 3  // DECCOLM
+	ModeSmoothScroll    = ModePrivate | 4  // DECSCLM
+	ModeReverseVideo    = ModePrivate | 5  // DECSCNM
+	ModeOrigin          = ModePrivate | 6  // DECOM
+	ModeAutoWrap        = ModePrivate | 7  // DECAWM
+	ModeAutoRepeat      = ModePrivate | 8  // DECARM
+	ModeMouseX10        = ModePrivate | 9  // X10 mouse reporting
+	ModeBlinkCursor     = ModePrivate | 12 // att610
+	ModePrintFormFeed   = ModePrivate | 18 // DECPFF
+	ModePrintExtent     = ModePrivate | 19 // DECPEXT
+	ModeReverseWrap     = ModePrivate | 45
+	ModeAlternateBuffer = ModePrivate | 47
+	ModeBackarrowKey    = ModePrivate | 67 // DECBKM
+	ModeLeftRightMargin = ModePrivate | 69 // DECLRMM
 )
 
 // xterm mode constants; see http://invisible-island.net/xterm/ctlseqs/ctlseqs.html.
@@ -43,14 +123,181 @@ const (
 	ModeMouseSgrExt   = ModePrivate | 1006
 	ModeMouseUrxvtExt = ModePrivate | 1015
 
-	ModeAlternateScroll = ModePrivate | 1007
-	ModeMetaReporting   = ModePrivate | 1036
-	ModeAlternateScreen = ModePrivate | 1049
+	ModeAlternateScroll      = ModePrivate | 1007
+	ModeMetaReporting        = ModePrivate | 1036
+	ModeAlternateBufferClear = ModePrivate | 1047
+	ModeSaveCursor           = ModePrivate | 1048
+	ModeAlternateScreen      = ModePrivate | 1049
 
-	ModeBracketedPaste = ModePrivate | 2004
+	ModeBracketedPaste     = ModePrivate | 2004
+	ModeSynchronizedOutput = ModePrivate | 2026
 )
 
 // TODO http://www.disinterest.org/resource/MUD-Dev/1997q1/000244.html and others
 const (
 	ShowCursor = ModePrivate | 25
 )
+
+var modeNames = map[Mode]string{
+	ModeKeyboardAction: "KAM",
+	ModeInsert:         "IRM",
+	ModeSendReceive:    "SRM",
+	ModeNewline:        "LNM",
+
+	ModeCursorKeys:      "DECCKM",
+	ModeANSI:            "DECANM",
+	ModeColumn:          "DECCOLM",
+	ModeSmoothScroll:    "DECSCLM",
+	ModeReverseVideo:    "DECSCNM",
+	ModeOrigin:          "DECOM",
+	ModeAutoWrap:        "DECAWM",
+	ModeAutoRepeat:      "DECARM",
+	ModeMouseX10:        "MouseX10",
+	ModeBlinkCursor:     "BlinkCursor",
+	ModePrintFormFeed:   "DECPFF",
+	ModePrintExtent:     "DECPEXT",
+	ShowCursor:          "DECTCEM",
+	ModeReverseWrap:     "ReverseWrap",
+	ModeAlternateBuffer: "AlternateBuffer",
+	ModeBackarrowKey:    "DECBKM",
+	ModeLeftRightMargin: "DECLRMM",
+
+	ModeMouseVt200:           "MouseVt200",
+	ModeMouseVt200Highlight:  "MouseVt200Highlight",
+	ModeMouseBtnEvent:        "MouseBtnEvent",
+	ModeMouseAnyEvent:        "MouseAnyEvent",
+	ModeMouseFocusEvent:      "MouseFocusEvent",
+	ModeMouseExt:             "MouseExt",
+	ModeMouseSgrExt:          "MouseSgrExt",
+	ModeAlternateScroll:      "AlternateScroll",
+	ModeMouseUrxvtExt:        "MouseUrxvtExt",
+	ModeMetaReporting:        "MetaReporting",
+	ModeAlternateBufferClear: "AlternateBufferClear",
+	ModeSaveCursor:           "SaveCursor",
+	ModeAlternateScreen:      "AlternateScreen",
+	ModeBracketedPaste:       "BracketedPaste",
+	ModeSynchronizedOutput:   "SynchronizedOutput",
+}
+
+var modesByName = make(map[string]Mode, len(modeNames))
+
+func init() {
+	for mode, name := range modeNames {
+		modesByName[strings.ToUpper(name)] = mode
+	}
+}
+
+// Mode state request and report control functions; both carry a '$'
+// intermediate byte, distinguishing them from DECSTR and DECTST.
+//
+// NOTE since an Escape identifier doesn't include intermediate bytes,
+// DECRQM == DECSTR and DECRPM == DECTST; e.g. a switch case for one matches
+// the other. Callers must check for the trailing '$' in the argument, as
+// IsModeReport and DecodeModeReport do.
+var (
+	// DECRQM Request Mode; see Mode.Request
+	DECRQM = CSI('p')
+
+	// DECRPM Report Mode; see DecodeModeReport
+	DECRPM = CSI('y')
+)
+
+// ModeState is the state of a mode as reported by a terminal in reply to a
+// DECRQM request.
+type ModeState uint8
+
+// ModeState constants, as reported in DECRPM.
+const (
+	ModeUnknown          ModeState = iota // mode not recognized
+	ModeSet                               // mode is set
+	ModeReset                             // mode is reset
+	ModePermanentlySet                    // mode is permanently set
+	ModePermanentlyReset                  // mode is permanently reset
+)
+
+var modeStateNames = [...]string{
+	"unknown",
+	"set",
+	"reset",
+	"permanently set",
+	"permanently reset",
+}
+
+func (st ModeState) String() string {
+	if int(st) < len(modeStateNames) {
+		return modeStateNames[st]
+	}
+	return "ModeState(" + strconv.Itoa(int(st)) + ")"
+}
+
+// IsSet returns true if the mode is set, permanently or not.
+func (st ModeState) IsSet() bool { return st == ModeSet || st == ModePermanentlySet }
+
+// IsSupported returns true if the terminal recognized the mode, and it may be
+// changed (i.e. is not permanently set or reset).
+func (st ModeState) IsSupported() bool { return st == ModeSet || st == ModeReset }
+
+// Request returns a DECRQM control sequence that asks the terminal to report
+// the mode's state; see DecodeModeReport.
+func (mode Mode) Request() Seq {
+	var tmp [24]byte
+	p := tmp[:0]
+	if mode.IsPrivate() {
+		p = append(p, '?')
+	}
+	p = strconv.AppendInt(p, int64(mode.Number()), 10)
+	return DECRQM.With(append(p, '$')...)
+}
+
+// IsModeReport returns true if the given escape and argument look like a
+// DECRPM report, suitable for use as a Term.Query match function.
+func IsModeReport(e Escape, a []byte) bool {
+	return e == DECRPM && len(a) > 0 && a[len(a)-1] == '$'
+}
+
+// DecodeModeReport decodes a DECRPM report argument, returning the reported
+// mode and its state.
+func DecodeModeReport(a []byte) (mode Mode, state ModeState, err error) {
+	if len(a) == 0 || a[len(a)-1] != '$' {
+		return 0, 0, errSyntax
+	}
+	a = a[:len(a)-1]
+	private := len(a) > 0 && a[0] == '?'
+	if private {
+		a = a[1:]
+	}
+	mode, n, err := DecodeMode(private, a)
+	if err != nil {
+		return 0, 0, err
+	}
+	st, m, err := DecodeNumber(a[n:])
+	if err != nil {
+		return 0, 0, err
+	}
+	if n+m != len(a) || st < 0 || st > int(ModePermanentlyReset) {
+		return 0, 0, errSyntax
+	}
+	return mode, ModeState(st), nil
+}
+
+// DecodeModes decodes all mode parameters from a SM or RM argument, appending
+// them to the given slice, e.g. "?1049;25" decodes to ModeAlternateScreen and
+// ShowCursor.
+func DecodeModes(a []byte, modes []Mode) ([]Mode, error) {
+	private := len(a) > 0 && a[0] == '?'
+	if private {
+		a = a[1:]
+	}
+	for len(a) > 0 {
+		mode, n, err := DecodeMode(private, a)
+		if err == nil && n < len(a) && a[n] != ';' {
+			err = errModeInvalid
+		}
+		if err != nil {
+			return modes, err
+		}
+		modes = append(modes, mode)
+		a = a[n:]
+	}
+	return modes, nil
+}
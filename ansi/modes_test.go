@@ -0,0 +1,99 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi/ansi"
+)
+
+func TestMode_String(t *testing.T) {
+	for _, tc := range []struct {
+		mode ansi.Mode
+		str  string
+	}{
+		{ansi.ModeInsert, "IRM"},
+		{ansi.ModeAutoWrap, "DECAWM"},
+		{ansi.ShowCursor, "DECTCEM"},
+		{ansi.ModeAlternateScreen, "AlternateScreen"},
+		{ansi.ModePrivate | 1234, "?1234"},
+		{ansi.Mode(99), "99"},
+	} {
+		t.Run(tc.str, func(t *testing.T) {
+			assert.Equal(t, tc.str, tc.mode.String())
+			mode, err := ansi.ParseMode(tc.str)
+			require.NoError(t, err)
+			assert.Equal(t, tc.mode, mode, "expected to parse back")
+		})
+	}
+
+	mode, err := ansi.ParseMode("decawm")
+	require.NoError(t, err)
+	assert.Equal(t, ansi.ModeAutoWrap, mode)
+	_, err = ansi.ParseMode("bogus")
+	assert.Error(t, err)
+
+	known := ansi.KnownModes()
+	assert.Equal(t, ansi.ModeKeyboardAction, known[0])
+	for _, mode := range known {
+		assert.NotEmpty(t, mode.Name(), "expected %v to be named", mode)
+	}
+}
+
+func TestMode_Request(t *testing.T) {
+	assert.Equal(t, "\x1b[?1049$p", string(ansi.ModeAlternateScreen.Request().AppendTo(nil)))
+	assert.Equal(t, "\x1b[4$p", string(ansi.ModeInsert.Request().AppendTo(nil)))
+	assert.Equal(t, "\x1b[?9h", string(ansi.ModeMouseX10.Set().AppendTo(nil)))
+}
+
+func TestDecodeModeReport(t *testing.T) {
+	for _, tc := range []struct {
+		in    string
+		mode  ansi.Mode
+		state ansi.ModeState
+		err   bool
+	}{
+		{in: "?1049;2$", mode: ansi.ModeAlternateScreen, state: ansi.ModeReset},
+		{in: "?25;1$", mode: ansi.ShowCursor, state: ansi.ModeSet},
+		{in: "4;0$", mode: ansi.ModeInsert, state: ansi.ModeUnknown},
+		{in: "?2026;4$", mode: ansi.ModeSynchronizedOutput, state: ansi.ModePermanentlyReset},
+		{in: "?7;5$", err: true},
+		{in: "?7;1", err: true},
+		{in: "?7$", err: true},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			assert.Equal(t, tc.in[len(tc.in)-1] == '$', ansi.IsModeReport(ansi.DECRPM, []byte(tc.in)))
+			mode, state, err := ansi.DecodeModeReport([]byte(tc.in))
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.mode, mode)
+			assert.Equal(t, tc.state, state)
+		})
+	}
+	assert.True(t, ansi.ModePermanentlySet.IsSet())
+	assert.False(t, ansi.ModePermanentlySet.IsSupported())
+	assert.Equal(t, "permanently reset", ansi.ModePermanentlyReset.String())
+}
+
+func TestDecodeModes(t *testing.T) {
+	modes, err := ansi.DecodeModes([]byte("?1049;25"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []ansi.Mode{ansi.ModeAlternateScreen, ansi.ShowCursor}, modes)
+
+	modes, err = ansi.DecodeModes([]byte("4;20"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []ansi.Mode{ansi.ModeInsert, ansi.ModeNewline}, modes)
+
+	// modes before an invalid parameter are still returned
+	modes, err = ansi.DecodeModes([]byte("?25;bogus;1"), nil)
+	assert.Error(t, err)
+	assert.Equal(t, []ansi.Mode{ansi.ShowCursor}, modes)
+	modes, err = ansi.DecodeModes([]byte("x"), nil)
+	assert.Error(t, err)
+	assert.Empty(t, modes)
+}
@@ -0,0 +1,284 @@
+package ansi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strconv"
+)
+
+// C1 controls that introduce control strings; any Seq built from one of these
+// has its argument bytes terminated by ST when written.
+var (
+	DCS = Escape(0x90) // Device Control String
+	SOS = Escape(0x98) // Start Of String
+	ST  = Escape(0x9C) // String Terminator
+	OSC = Escape(0x9D) // Operating System Command
+	PM  = Escape(0x9E) // Privacy Message
+	APC = Escape(0x9F) // Application Program Command
+)
+
+// OSCCommand returns an OSC control sequence carrying the given numeric
+// command and string parameters, e.g. OSCCommand(2, "title") builds
+// "\x1b]2;title\x1b\\".
+func OSCCommand(ps int, pt ...string) Seq {
+	var tmp [64]byte
+	p := strconv.AppendInt(tmp[:0], int64(ps), 10)
+	for _, s := range pt {
+		p = append(p, ';')
+		p = append(p, s...)
+	}
+	return OSC.With(p...)
+}
+
+// DecodeOSC decodes the leading numeric command from an OSC argument, returning
+// it and the remaining string parameter bytes (after any ';' separator).
+func DecodeOSC(a []byte) (ps int, pt []byte, err error) {
+	ps, n, err := DecodeNumber(a)
+	if err != nil {
+		return 0, nil, err
+	}
+	pt = a[n:]
+	if len(pt) > 0 {
+		if pt[0] != ';' {
+			return 0, nil, errSyntax
+		}
+		pt = pt[1:]
+	}
+	return ps, pt, nil
+}
+
+// Selection identifies an xterm selection buffer, as used by the OSC 52
+// clipboard control.
+type Selection byte
+
+// Selection constants for OSC 52.
+const (
+	SelectClipboard Selection = 'c'
+	SelectPrimary   Selection = 'p'
+	SelectSecondary Selection = 'q'
+	SelectSelect    Selection = 's'
+
+	// SelectCut0 is the first of 8 cut buffers, 0 through 7.
+	SelectCut0 Selection = '0'
+)
+
+// OSCClipboard is the OSC command number for manipulating selection data.
+const OSCClipboard = 52
+
+// AppendClipboardPrefix appends the leading part of an OSC 52 argument to p:
+// the command number and selection targets, and the ';' that precedes data.
+// If no selections are given, the terminal uses its default (usually "s0").
+func AppendClipboardPrefix(p []byte, sels ...Selection) []byte {
+	p = strconv.AppendInt(p, OSCClipboard, 10)
+	p = append(p, ';')
+	for _, sel := range sels {
+		p = append(p, byte(sel))
+	}
+	return append(p, ';')
+}
+
+// SetClipboard returns an OSC 52 control sequence that sets the given
+// selection(s) to the given data.
+func SetClipboard(data []byte, sels ...Selection) Seq {
+	p := AppendClipboardPrefix(nil, sels...)
+	n := len(p)
+	p = append(p, make([]byte, base64.StdEncoding.EncodedLen(len(data)))...)
+	base64.StdEncoding.Encode(p[n:], data)
+	return OSC.With(p...)
+}
+
+// QueryClipboard returns an OSC 52 control sequence that asks the terminal to
+// reply with the content of the given selection(s); see DecodeClipboard.
+func QueryClipboard(sels ...Selection) Seq {
+	return OSC.With(append(AppendClipboardPrefix(nil, sels...), '?')...)
+}
+
+// DecodeClipboard decodes an OSC 52 argument, e.g. from a terminal's reply
+// to QueryClipboard, returning the named selection(s) and decoded data.
+func DecodeClipboard(a []byte) (sels []Selection, data []byte, err error) {
+	ps, pt, err := DecodeOSC(a)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ps != OSCClipboard {
+		return nil, nil, errSyntax
+	}
+	i := bytes.IndexByte(pt, ';')
+	if i < 0 {
+		return nil, nil, errSyntax
+	}
+	for _, b := range pt[:i] {
+		sels = append(sels, Selection(b))
+	}
+	pt = pt[i+1:]
+	data = make([]byte, base64.StdEncoding.DecodedLen(len(pt)))
+	n, err := base64.StdEncoding.Decode(data, pt)
+	return sels, data[:n], err
+}
+
+// IsClipboardReply returns true if the given escape and argument look like an
+// OSC 52 clipboard reply, suitable for use as a Term.Query match function.
+func IsClipboardReply(e Escape, a []byte) bool {
+	if e != OSC {
+		return false
+	}
+	ps, _, err := DecodeOSC(a)
+	return err == nil && ps == OSCClipboard
+}
+
+// OSC command numbers for querying and manipulating terminal colors.
+const (
+	OSCPaletteColor      = 4
+	OSCForeground        = 10
+	OSCBackground        = 11
+	OSCResetPaletteColor = 104
+	OSCResetForeground   = 110
+	OSCResetBackground   = 111
+)
+
+// QueryPaletteColor returns an OSC 4 control sequence that asks the terminal
+// to report its definition of the given palette color; see DecodeColorReply.
+func QueryPaletteColor(i int) Seq {
+	return OSCCommand(OSCPaletteColor, strconv.Itoa(i), "?")
+}
+
+// SetPaletteColor returns an OSC 4 control sequence that changes the
+// terminal's definition of the given palette color.
+func SetPaletteColor(i int, c SGRColor) Seq {
+	return OSCCommand(OSCPaletteColor, strconv.Itoa(i), string(AppendXColor(nil, c)))
+}
+
+// ResetPaletteColor returns an OSC 104 control sequence that restores the
+// terminal's default definition of the given palette colors, or of all
+// palette colors if none are given.
+func ResetPaletteColor(is ...int) Seq {
+	pt := make([]string, len(is))
+	for j, i := range is {
+		pt[j] = strconv.Itoa(i)
+	}
+	return OSCCommand(OSCResetPaletteColor, pt...)
+}
+
+// QueryForeground returns an OSC 10 control sequence that asks the terminal to
+// report its default foreground color; see DecodeColorReply.
+func QueryForeground() Seq { return OSCCommand(OSCForeground, "?") }
+
+// QueryBackground returns an OSC 11 control sequence that asks the terminal to
+// report its default background color; see DecodeColorReply.
+func QueryBackground() Seq { return OSCCommand(OSCBackground, "?") }
+
+// SetForeground returns an OSC 10 control sequence that changes the
+// terminal's default foreground color.
+func SetForeground(c SGRColor) Seq {
+	return OSCCommand(OSCForeground, string(AppendXColor(nil, c)))
+}
+
+// SetBackground returns an OSC 11 control sequence that changes the
+// terminal's default background color.
+func SetBackground(c SGRColor) Seq {
+	return OSCCommand(OSCBackground, string(AppendXColor(nil, c)))
+}
+
+// ResetForeground returns an OSC 110 control sequence that restores the
+// terminal's original default foreground color.
+func ResetForeground() Seq { return OSCCommand(OSCResetForeground) }
+
+// ResetBackground returns an OSC 111 control sequence that restores the
+// terminal's original default background color.
+func ResetBackground() Seq { return OSCCommand(OSCResetBackground) }
+
+// IsColorReply returns true if the given escape and argument look like an OSC
+// 4, 10, or 11 color report, suitable for use as a Term.Query match function.
+func IsColorReply(e Escape, a []byte) bool {
+	if e != OSC {
+		return false
+	}
+	switch ps, _, err := DecodeOSC(a); {
+	case err != nil:
+		return false
+	case ps == OSCPaletteColor, ps == OSCForeground, ps == OSCBackground:
+		return true
+	}
+	return false
+}
+
+// DecodeColorReply decodes an OSC 4, 10, or 11 color report, returning its
+// command number, palette index (only meaningful for OSC 4), and color.
+func DecodeColorReply(a []byte) (ps, i int, c SGRColor, err error) {
+	ps, pt, err := DecodeOSC(a)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	switch ps {
+	case OSCPaletteColor:
+		var n int
+		i, n, err = DecodeNumber(pt)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		if pt = pt[n:]; len(pt) == 0 || pt[0] != ';' {
+			return 0, 0, 0, errSyntax
+		}
+		pt = pt[1:]
+	case OSCForeground, OSCBackground:
+	default:
+		return 0, 0, 0, errSyntax
+	}
+	c, err = DecodeXColor(pt)
+	return ps, i, c, err
+}
+
+// AppendXColor appends an X11 color specification of the given color to p, in
+// the "rgb:RR/GG/BB" form understood by xterm-like terminals.
+func AppendXColor(p []byte, c SGRColor) []byte {
+	const hex = "0123456789abcdef"
+	r, g, b := c.RGB()
+	return append(p, 'r', 'g', 'b', ':',
+		hex[r>>4], hex[r&0xf], '/',
+		hex[g>>4], hex[g&0xf], '/',
+		hex[b>>4], hex[b&0xf])
+}
+
+// DecodeXColor decodes an X11 color specification, as reported by xterm-like
+// terminals: either "rgb:R/G/B" with 1 to 4 hex digits per component, or
+// "#RGB" with 1 to 4 hex digits per component.
+func DecodeXColor(b []byte) (SGRColor, error) {
+	var comps [3][]byte
+	switch {
+	case bytes.HasPrefix(b, []byte("rgb:")):
+		parts := bytes.Split(b[4:], []byte("/"))
+		if len(parts) != 3 {
+			return 0, errSyntax
+		}
+		copy(comps[:], parts)
+	case len(b) > 1 && b[0] == '#' && (len(b)-1)%3 == 0:
+		b = b[1:]
+		n := len(b) / 3
+		comps = [3][]byte{b[:n], b[n : 2*n], b[2*n:]}
+	default:
+		return 0, errSyntax
+	}
+	var rgb [3]uint8
+	for i, comp := range comps {
+		v, err := decodeXColorComponent(comp)
+		if err != nil {
+			return 0, err
+		}
+		rgb[i] = v
+	}
+	return RGB(rgb[0], rgb[1], rgb[2]), nil
+}
+
+// decodeXColorComponent decodes 1 to 4 hex digits, scaling the value to 8
+// bits.
+func decodeXColorComponent(b []byte) (uint8, error) {
+	if len(b) == 0 || len(b) > 4 {
+		return 0, errSyntax
+	}
+	v, err := strconv.ParseUint(string(b), 16, 16)
+	if err != nil {
+		return 0, errSyntax
+	}
+	max := uint64(1)<<(4*uint(len(b))) - 1
+	return uint8((v*0xff + max/2) / max), nil
+}
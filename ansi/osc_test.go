@@ -0,0 +1,112 @@
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi/ansi"
+)
+
+func TestOSC(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		seq      ansi.Seq
+		expected string
+	}{
+		{"title", ansi.OSCCommand(2, "hello"), "\x1b]2;hello\x1b\\"},
+		{"set clipboard", ansi.SetClipboard([]byte("hello"), ansi.SelectClipboard), "\x1b]52;c;aGVsbG8=\x1b\\"},
+		{"set default", ansi.SetClipboard([]byte("hi")), "\x1b]52;;aGk=\x1b\\"},
+		{"query clipboard", ansi.QueryClipboard(ansi.SelectClipboard, ansi.SelectPrimary), "\x1b]52;cp;?\x1b\\"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := tc.seq.AppendTo(nil)
+			assert.Equal(t, tc.expected, string(p), "expected code string")
+			assert.True(t, len(p) <= tc.seq.Size(), "expected size bound")
+
+			e, a, n := ansi.DecodeEscape(p)
+			assert.Equal(t, len(p), n, "expected to decode all bytes")
+			assert.Equal(t, ansi.OSC, e, "expected OSC")
+			assert.Equal(t, tc.expected[2:len(tc.expected)-2], string(a), "expected argument")
+		})
+	}
+}
+
+func TestDecodeClipboard(t *testing.T) {
+	sels, data, err := ansi.DecodeClipboard([]byte("52;c;aGVsbG8gd29ybGQ="))
+	require.NoError(t, err)
+	assert.Equal(t, []ansi.Selection{ansi.SelectClipboard}, sels)
+	assert.Equal(t, "hello world", string(data))
+
+	_, _, err = ansi.DecodeClipboard([]byte("2;title"))
+	assert.Error(t, err, "expected error for non-clipboard command")
+
+	_, _, err = ansi.DecodeClipboard([]byte("52;c"))
+	assert.Error(t, err, "expected error for missing data")
+
+	assert.True(t, ansi.IsClipboardReply(ansi.OSC, []byte("52;c;")))
+	assert.False(t, ansi.IsClipboardReply(ansi.OSC, []byte("4;1;rgb:0000/0000/0000")))
+	assert.False(t, ansi.IsClipboardReply(ansi.APC, []byte("52;c;")))
+}
+
+func TestColorSeqs(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		seq      ansi.Seq
+		expected string
+	}{
+		{"query fg", ansi.QueryForeground(), "\x1b]10;?\x1b\\"},
+		{"query bg", ansi.QueryBackground(), "\x1b]11;?\x1b\\"},
+		{"query palette", ansi.QueryPaletteColor(3), "\x1b]4;3;?\x1b\\"},
+		{"set fg", ansi.SetForeground(ansi.RGB(0xff, 0x80, 0x00)), "\x1b]10;rgb:ff/80/00\x1b\\"},
+		{"set bg", ansi.SetBackground(ansi.RGB(0x01, 0x02, 0x03)), "\x1b]11;rgb:01/02/03\x1b\\"},
+		{"set palette", ansi.SetPaletteColor(1, ansi.RGB(0xaa, 0, 0)), "\x1b]4;1;rgb:aa/00/00\x1b\\"},
+		{"reset fg", ansi.ResetForeground(), "\x1b]110\x1b\\"},
+		{"reset bg", ansi.ResetBackground(), "\x1b]111\x1b\\"},
+		{"reset palette", ansi.ResetPaletteColor(1, 2), "\x1b]104;1;2\x1b\\"},
+		{"reset all palette", ansi.ResetPaletteColor(), "\x1b]104\x1b\\"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, string(tc.seq.AppendTo(nil)))
+		})
+	}
+}
+
+func TestDecodeColorReply(t *testing.T) {
+	for _, tc := range []struct {
+		in    string
+		ps, i int
+		c     ansi.SGRColor
+		err   bool
+	}{
+		{in: "10;rgb:ffff/8080/0000", ps: 10, c: ansi.RGB(0xff, 0x80, 0x00)},
+		{in: "11;rgb:0/f/8", ps: 11, c: ansi.RGB(0x00, 0xff, 0x88)},
+		{in: "4;12;rgb:1212/3434/5656", ps: 4, i: 12, c: ansi.RGB(0x12, 0x34, 0x56)},
+		{in: "11;#123456", ps: 11, c: ansi.RGB(0x12, 0x34, 0x56)},
+		{in: "11;#fff", ps: 11, c: ansi.RGB(0xff, 0xff, 0xff)},
+		{in: "4;1", err: true},
+		{in: "11;rgb:00/00", err: true},
+		{in: "11;rgb:00000/0/0", err: true},
+		{in: "52;c;", err: true},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			ps, i, c, err := ansi.DecodeColorReply([]byte(tc.in))
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.ps, ps)
+			assert.Equal(t, tc.i, i)
+			assert.Equal(t, tc.c, c)
+		})
+	}
+}
+
+func TestIsColorReply(t *testing.T) {
+	assert.True(t, ansi.IsColorReply(ansi.OSC, []byte("10;rgb:0/0/0")))
+	assert.True(t, ansi.IsColorReply(ansi.OSC, []byte("4;1;rgb:0/0/0")))
+	assert.False(t, ansi.IsColorReply(ansi.OSC, []byte("52;c;")))
+	assert.False(t, ansi.IsColorReply(ansi.DCS, []byte("10;rgb:0/0/0")))
+}
@@ -0,0 +1,107 @@
+package ansi
+
+import (
+	"image"
+	"strings"
+	"unicode"
+)
+
+// XTWINOPS is xterm's window manipulation control, which shares its final
+// byte with DECSLPP; its first parameter selects an operation, any following
+// parameters are operands.
+var XTWINOPS = CSI('t')
+
+// XTWINOPS operations.
+const (
+	WinOpReportTextAreaPixels = 14
+	WinOpReportCellPixels     = 16
+	WinOpReportTextAreaChars  = 18
+	WinOpPushTitle            = 22
+	WinOpPopTitle             = 23
+)
+
+// XTWINOPS size report operations; a terminal replies to each size query with
+// one of these, followed by height and width.
+const (
+	WinOpTextAreaPixels = 4
+	WinOpCellPixels     = 6
+	WinOpTextAreaChars  = 8
+)
+
+// OSC command numbers for setting window titles.
+const (
+	OSCIconAndWindowTitle = 0
+	OSCIconTitle          = 1
+	OSCWindowTitle        = 2
+)
+
+// PushTitle returns an XTWINOPS control sequence that saves both the icon and
+// window titles on the terminal's title stack.
+func PushTitle() Seq { return XTWINOPS.WithInts(WinOpPushTitle, 0) }
+
+// PopTitle returns an XTWINOPS control sequence that restores both the icon
+// and window titles from the terminal's title stack.
+func PopTitle() Seq { return XTWINOPS.WithInts(WinOpPopTitle, 0) }
+
+// SetWindowTitle returns an OSC 2 control sequence that sets the terminal's
+// window title. Any C0 or C1 controls (e.g. BEL, ESC, or ST) are dropped from
+// title, since they would terminate the sequence early.
+func SetWindowTitle(title string) Seq {
+	return OSCCommand(OSCWindowTitle, strings.Map(dropControl, title))
+}
+
+func dropControl(r rune) rune {
+	if unicode.IsControl(r) {
+		return -1
+	}
+	return r
+}
+
+// QueryTextAreaPixels returns an XTWINOPS control sequence that asks the
+// terminal to report the size of its text area in pixels; see DecodeWinOpsSize.
+func QueryTextAreaPixels() Seq { return XTWINOPS.WithInts(WinOpReportTextAreaPixels) }
+
+// QueryCellPixels returns an XTWINOPS control sequence that asks the terminal
+// to report the size of a character cell in pixels; see DecodeWinOpsSize.
+func QueryCellPixels() Seq { return XTWINOPS.WithInts(WinOpReportCellPixels) }
+
+// QueryTextAreaChars returns an XTWINOPS control sequence that asks the
+// terminal to report the size of its text area in character cells; see
+// DecodeWinOpsSize.
+func QueryTextAreaChars() Seq { return XTWINOPS.WithInts(WinOpReportTextAreaChars) }
+
+// IsWinOpsSizeReply returns true if the given escape and argument look like an
+// XTWINOPS size report, suitable for use as a Term.Query match function.
+func IsWinOpsSizeReply(e Escape, a []byte) bool {
+	if e != XTWINOPS {
+		return false
+	}
+	switch op, _, err := DecodeNumber(a); {
+	case err != nil:
+		return false
+	case op == WinOpTextAreaPixels, op == WinOpCellPixels, op == WinOpTextAreaChars:
+		return true
+	}
+	return false
+}
+
+// DecodeWinOpsSize decodes an XTWINOPS size report argument, returning its
+// report operation (one of WinOpTextAreaPixels, WinOpCellPixels, or
+// WinOpTextAreaChars), and the reported size as a width,height point.
+func DecodeWinOpsSize(a []byte) (op int, size image.Point, err error) {
+	op, n, err := DecodeNumber(a)
+	if err == nil {
+		var m int
+		size.Y, m, err = DecodeNumber(a[n:])
+		n += m
+	}
+	if err == nil {
+		var m int
+		size.X, m, err = DecodeNumber(a[n:])
+		n += m
+	}
+	if err == nil && n != len(a) {
+		err = errSyntax
+	}
+	return op, size, err
+}
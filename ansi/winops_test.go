@@ -0,0 +1,60 @@
+package ansi_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi/ansi"
+)
+
+func TestWinOps(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		seq      ansi.Seq
+		expected string
+	}{
+		{"push title", ansi.PushTitle(), "\x1b[22;0t"},
+		{"pop title", ansi.PopTitle(), "\x1b[23;0t"},
+		{"set title", ansi.SetWindowTitle("hello"), "\x1b]2;hello\x1b\\"},
+		{"set title controls", ansi.SetWindowTitle("a\ab\x1b\\c\u009cd\r\ne"), "\x1b]2;ab\\cde\x1b\\"},
+		{"query text pixels", ansi.QueryTextAreaPixels(), "\x1b[14t"},
+		{"query cell pixels", ansi.QueryCellPixels(), "\x1b[16t"},
+		{"query text chars", ansi.QueryTextAreaChars(), "\x1b[18t"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, string(tc.seq.AppendTo(nil)))
+		})
+	}
+}
+
+func TestDecodeWinOpsSize(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		op   int
+		size image.Point
+		err  bool
+	}{
+		{in: "8;24;80", op: ansi.WinOpTextAreaChars, size: image.Pt(80, 24)},
+		{in: "4;480;1280", op: ansi.WinOpTextAreaPixels, size: image.Pt(1280, 480)},
+		{in: "6;20;10", op: ansi.WinOpCellPixels, size: image.Pt(10, 20)},
+		{in: "8;24", err: true},
+		{in: "8;24;80x", err: true},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			op, size, err := ansi.DecodeWinOpsSize([]byte(tc.in))
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.op, op)
+			assert.Equal(t, tc.size, size)
+			assert.True(t, ansi.IsWinOpsSizeReply(ansi.XTWINOPS, []byte(tc.in)))
+		})
+	}
+	assert.False(t, ansi.IsWinOpsSizeReply(ansi.XTWINOPS, []byte("22;0")))
+	assert.False(t, ansi.IsWinOpsSizeReply(ansi.CUP, []byte("8;24;80")))
+}
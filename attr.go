@@ -44,7 +44,16 @@ func (at Attr) IsTerminal() bool {
 
 // Size reads and returns the current terminal size.
 func (at Attr) Size() (size image.Point, err error) {
-	return at.getSize()
+	size, _, err = at.getSize()
+	return size, err
+}
+
+// WindowSize reads and returns the current terminal size, including its pixel
+// dimensions if the terminal reports them; see also Term.QuerySize.
+func (at Attr) WindowSize() (ts TermSize, err error) {
+	ts.Cells, ts.Pixels, err = at.getSize()
+	ts.complete()
+	return ts, err
 }
 
 // SetRaw controls whether the terminal should be in raw mode.
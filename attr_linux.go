@@ -8,7 +8,7 @@ import (
 	"unsafe"
 )
 
-func (at Attr) getSize() (size image.Point, err error) {
+func (at Attr) getSize() (size, pixels image.Point, err error) {
 	var dim struct {
 		rows    uint16
 		cols    uint16
@@ -19,8 +19,10 @@ func (at Attr) getSize() (size image.Point, err error) {
 	if err == nil {
 		size.X = int(dim.cols)
 		size.Y = int(dim.rows)
+		pixels.X = int(dim.xpixels)
+		pixels.Y = int(dim.ypixels)
 	}
-	return size, err
+	return size, pixels, err
 }
 
 func (at Attr) getAttr() (attr syscall.Termios, err error) {
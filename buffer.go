@@ -3,7 +3,6 @@ package anansi
 import (
 	"bytes"
 	"io"
-	"unicode/utf8"
 
 	"github.com/jcorbin/anansi/ansi"
 )
@@ -14,6 +13,7 @@ import (
 type Buffer struct {
 	buf bytes.Buffer
 	off int
+	dec ansi.Decoder
 }
 
 // Len returns the number of unwritten bytes in the buffer.
@@ -36,6 +36,7 @@ func (b *Buffer) Bytes() []byte {
 func (b *Buffer) Reset() {
 	b.buf.Reset()
 	b.off = 0
+	b.dec.Reset()
 }
 
 // WriteTo writes all bytes from the internal buffer to the given io.Writer.
@@ -141,28 +142,41 @@ func (b *Buffer) Discard() {
 }
 
 // Process bytes written to the internal buffer, decoding runes and escape
-// sequences, and passing them to the given processor.
+// sequences, and passing them to the given processor. Any incomplete escape
+// sequence is held by the buffer's decoder, and completed by a later Process.
 func (b *Buffer) Process(proc Processor) {
-	b.off += Process(proc, b.buf.Bytes()[b.off:])
+	n, _ := process(&b.dec, proc, b.buf.Bytes()[b.off:])
+	b.off += n
 }
 
 // Process decodes ansi escapes and utf8 runes from p, passing them to proc.
-func Process(proc Processor, p []byte) (n int) {
+// Returns the number of bytes processed, stopping short of any incomplete
+// escape sequence at the end of p.
+func Process(proc Processor, p []byte) int {
+	var dec ansi.Decoder
+	_, n := process(&dec, proc, p)
+	return n
+}
+
+// process decodes from p with the given decoder, returning the number of bytes
+// consumed, and the offset of the first byte of any pending sequence (len(p)
+// if none).
+func process(dec *ansi.Decoder, proc Processor, p []byte) (n, mark int) {
 	for n < len(p) {
-		e, a, m := ansi.DecodeEscape(p[n:])
+		if !dec.Pending() {
+			mark = n
+		}
+		e, a, m, ok := dec.Decode(p[n:])
 		n += m
-		if e == 0 {
-			switch r, m := utf8.DecodeRune(p[n:]); r {
-			case '\x1b':
-				return n
-			default:
-				n += m
-				e = ansi.Escape(r)
-			}
+		if !ok {
+			break
 		}
 		proc.ProcessANSI(e, a)
 	}
-	return n
+	if !dec.Pending() {
+		mark = n
+	}
+	return n, mark
 }
 
 // Processor receives decoded ANSI escape sequences and Unicode runes from
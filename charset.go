@@ -0,0 +1,47 @@
+package anansi
+
+import "github.com/jcorbin/anansi/ansi"
+
+// charsets tracks ISO-2022 charset designation and invocation state for
+// Screen emulation.
+type charsets struct {
+	g      [4]ansi.Charset // designated G0-G3 charsets
+	gl     uint8           // locking shift invoked into GL
+	single uint8           // single shift for the next graphic rune, if non-zero
+}
+
+// process handles charset designation and shift controls, returning false if
+// e is not one.
+func (cs *charsets) process(e ansi.Escape, a []byte) bool {
+	switch e {
+	case '\x0E': // SO
+		cs.gl = 1
+	case '\x0F': // SI
+		cs.gl = 0
+	case ansi.LS2:
+		cs.gl = 2
+	case ansi.LS3:
+		cs.gl = 3
+	case '\x8E': // SS2
+		cs.single = 2
+	case '\x8F': // SS3
+		cs.single = 3
+	default:
+		g, c, ok := ansi.DecodeCharsetDesignation(e, a)
+		if !ok {
+			return false
+		}
+		cs.g[g] = c
+	}
+	return true
+}
+
+// translate maps a graphic rune through the invoked charset, consuming any
+// single shift.
+func (cs *charsets) translate(r rune) rune {
+	g := cs.gl
+	if cs.single != 0 {
+		g, cs.single = cs.single, 0
+	}
+	return cs.g[g].Translate(r)
+}
@@ -0,0 +1,31 @@
+package anansi_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jcorbin/anansi"
+)
+
+func TestScreen_charsets(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{"G0 line drawing", "\x1b(0lqk\x1b(Bx", "┌─┐x  "},
+		{"SO/SI G1", "\x1b)0q\x0eq\x0fq", "q─q   "},
+		{"UK", "\x1b(A#1\x1b(B#", "£1#   "},
+		{"single shift", "\x1b*0\x1bNqq", "─q    "},
+		{"LS3", "\x1b+0\x1boxx\x0fx", "││x   "},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var vs anansi.VirtualScreen
+			vs.Resize(image.Pt(6, 1))
+			vs.WriteString(tc.in)
+			assert.Equal(t, tc.out, gridString(vs.Grid))
+		})
+	}
+}
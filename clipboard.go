@@ -0,0 +1,102 @@
+package anansi
+
+import (
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/jcorbin/anansi/ansi"
+)
+
+const (
+	clipboardChunkSize      = 3 * 1024 // encodes to 4KiB of base64
+	defaultClipboardTimeout = time.Second
+)
+
+var errClipboardInactive = errors.New("anansi.Clipboard: not active under a Term")
+
+// Clipboard implements Context-ual access to the terminal's selection buffers
+// using OSC 52 control sequences. Since the terminal itself owns the
+// clipboard, this works even when running remotely (e.g. over ssh); however
+// many terminals disable clipboard reading by default, and some writing too.
+type Clipboard struct {
+	// Selections to target, defaults to just ansi.SelectClipboard.
+	Selections []ansi.Selection
+
+	// Timeout bounds how long Get waits for a reply, defaults to 1 second.
+	Timeout time.Duration
+
+	term *Term
+	buf  Buffer
+}
+
+// Enter binds the clipboard to the terminal.
+func (cb *Clipboard) Enter(term *Term) error {
+	cb.term = term
+	return nil
+}
+
+// Exit unbinds the clipboard from the terminal.
+func (cb *Clipboard) Exit(term *Term) error {
+	cb.term = nil
+	cb.buf.Reset()
+	return nil
+}
+
+// Set writes the given data into the targeted selection(s). The OSC 52 control
+// sequence is written in chunks, so that large data need not be base64 encoded
+// into memory all at once.
+func (cb *Clipboard) Set(data []byte) error {
+	if cb.term == nil {
+		return errClipboardInactive
+	}
+
+	var tmp [64]byte
+	cb.buf.Reset()
+	_, _ = cb.buf.Write(ansi.AppendClipboardPrefix(ansi.OSC.AppendTo(tmp[:0]), cb.selections()...))
+
+	enc := base64.NewEncoder(base64.StdEncoding, &cb.buf)
+	for len(data) > 0 {
+		chunk := data
+		if len(chunk) > clipboardChunkSize {
+			chunk = chunk[:clipboardChunkSize]
+		}
+		data = data[len(chunk):]
+		_, _ = enc.Write(chunk)
+		if err := cb.term.Flush(&cb.buf); err != nil {
+			return err
+		}
+	}
+	_ = enc.Close()
+
+	cb.buf.WriteESC(ansi.ST)
+	return cb.term.Flush(&cb.buf)
+}
+
+// Get queries the terminal for the content of the targeted selection(s),
+// waiting up to Timeout for its reply; an error satisfying IsNoReply is
+// returned if the terminal ignores the query.
+func (cb *Clipboard) Get() ([]byte, error) {
+	if cb.term == nil {
+		return nil, errClipboardInactive
+	}
+	timeout := cb.Timeout
+	if timeout == 0 {
+		timeout = defaultClipboardTimeout
+	}
+	_, a, err := cb.term.Query(ansi.QueryClipboard(cb.selections()...), timeout, ansi.IsClipboardReply)
+	if err != nil {
+		return nil, err
+	}
+	_, data, err := ansi.DecodeClipboard(a)
+	return data, err
+}
+
+func (cb *Clipboard) selections() []ansi.Selection {
+	if len(cb.Selections) == 0 {
+		return []ansi.Selection{ansi.SelectClipboard}
+	}
+	return cb.Selections
+}
+
+var _ Context = &Clipboard{}
@@ -0,0 +1,86 @@
+package anansi_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi"
+	"github.com/jcorbin/anansi/ansi"
+)
+
+func TestClipboard(t *testing.T) {
+	inR, inW, err := blockingPipe()
+	require.NoError(t, err)
+	defer inR.Close()
+	defer inW.Close()
+	outR, outW, err := blockingPipe()
+	require.NoError(t, err)
+	defer outR.Close()
+
+	term := anansi.NewTerm(inR, outW)
+	var cb anansi.Clipboard
+	require.NoError(t, cb.Enter(term))
+
+	var (
+		wg  sync.WaitGroup
+		out bytes.Buffer
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var buf [4096]byte
+		for {
+			n, err := outR.Read(buf[:])
+			out.Write(buf[:n])
+			if bytes.HasSuffix(out.Bytes(), []byte("?\x1b\\")) {
+				// reply to the query with some key input on either side
+				_, _ = io.WriteString(inW, "a\x1b]52;c;d29ybGQ=\x1b\\b")
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	big := strings.Repeat("0123456789abcdef", 1024)
+	require.NoError(t, cb.Set([]byte(big)))
+	data, err := cb.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "world", string(data))
+
+	outW.Close()
+	wg.Wait()
+
+	var expected []byte
+	expected = ansi.SetClipboard([]byte(big), ansi.SelectClipboard).AppendTo(expected)
+	expected = ansi.QueryClipboard(ansi.SelectClipboard).AppendTo(expected)
+	assert.Equal(t, string(expected), out.String())
+
+	var rs []rune
+	for e, _, ok := term.Decode(); ok; e, _, ok = term.Decode() {
+		rs = append(rs, rune(e))
+	}
+	assert.Equal(t, "ab", string(rs), "expected other input to remain")
+
+	require.NoError(t, cb.Exit(term))
+	assert.Error(t, cb.Set(nil), "expected inactive error")
+}
+
+// blockingPipe is like os.Pipe, but returns files that aren't managed by the
+// runtime poller, so that anansi.Input's non-blocking reads work like they do
+// on a terminal.
+func blockingPipe() (r, w *os.File, err error) {
+	var fds [2]int
+	if err := syscall.Pipe(fds[:]); err != nil {
+		return nil, nil, err
+	}
+	return os.NewFile(uintptr(fds[0]), "|0"), os.NewFile(uintptr(fds[1]), "|1"), nil
+}
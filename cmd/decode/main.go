@@ -42,9 +42,8 @@ func run(term *anansi.Term) error {
 	}
 
 	if *altMode {
-		term.AddMode(
-			ansi.ModeAlternateScreen,
-		)
+		_ = term.LoadTerminfo("") // falls back to ANSI if unavailable
+		term.AddAlternateScreen()
 	}
 
 	if err := term.SetEcho(!*rawMode); err != nil {
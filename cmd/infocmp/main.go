@@ -0,0 +1,291 @@
+// Command infocmp prints terminfo entries as anansi loads them, to help
+// diagnose misbehaving terminals; it is modeled after ncurses infocmp(1).
+//
+// Usage:
+//
+//	infocmp [-b | -src FILE] [NAME]
+//	infocmp -d [-b | -src FILE] NAME [OTHER]
+//
+// By default, the named entry (or $TERM) is loaded from the terminfo database,
+// falling back to anansi's builtins, and printed in source form, preceded by
+// a comment describing where it came from: a file, a builtin, or a builtin
+// matched by compat partial name. Entries read from source files are also
+// preceded by comments listing any capabilities inherited by use=, and where
+// each was found.
+//
+// With -d, the capabilities that differ between NAME and OTHER are printed
+// instead, followed by where any that were inherited came from; if OTHER is
+// omitted, NAME is compared against the builtin that would be used for it.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/jcorbin/anansi/terminfo"
+)
+
+var (
+	builtinFlag = flag.Bool("b", false, "only load entries from anansi's builtins")
+	srcFlag     = flag.String("src", "", "load entries from a terminfo source file")
+	diffFlag    = flag.Bool("d", false, "print differences between two entries")
+)
+
+func main() {
+	flag.Parse()
+	out := bufio.NewWriter(os.Stdout)
+	err := run(out, flag.Args())
+	if ferr := out.Flush(); err == nil {
+		err = ferr
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func run(w io.Writer, args []string) error {
+	if len(args) == 0 {
+		args = []string{os.Getenv("TERM")}
+	}
+	if args[0] == "" {
+		return errors.New("no terminal name given, and TERM not set")
+	}
+
+	a, aOrigin, err := load(args[0])
+	if err != nil {
+		return err
+	}
+
+	if !*diffFlag {
+		if len(args) > 1 {
+			return errors.New("too many arguments, did you mean -d?")
+		}
+		fmt.Fprintf(w, "#\tReading terminfo entry for %v from %v\n", args[0], aOrigin)
+		writeOrigins(w, a.Name, aOrigin, nil)
+		_, err := a.WriteSource(w)
+		return err
+	}
+
+	var (
+		b       *terminfo.Terminfo
+		bOrigin terminfo.Origin
+	)
+	switch len(args) {
+	case 1:
+		b, bOrigin, err = terminfo.LocateBuiltin(args[0])
+	case 2:
+		b, bOrigin, err = load(args[1])
+	default:
+		return errors.New("too many arguments")
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "comparing %v (%v) to %v (%v).\n", a.Name, aOrigin, b.Name, bOrigin)
+	diff := writeDiff(w, a, b)
+	writeOrigins(w, a.Name, aOrigin, diff)
+	writeOrigins(w, b.Name, bOrigin, diff)
+	return nil
+}
+
+func load(name string) (*terminfo.Terminfo, terminfo.Origin, error) {
+	switch {
+	case *srcFlag != "":
+		return terminfo.LocateSource(*srcFlag, name)
+	case *builtinFlag:
+		return terminfo.LocateBuiltin(name)
+	}
+	return terminfo.Locate(name)
+}
+
+// writeDiff writes any capabilities that differ between a and b, in the same
+// format as infocmp -d, returning their names.
+func writeDiff(w io.Writer, a, b *terminfo.Terminfo) map[string]bool {
+	var ext []string
+	diff := make(map[string]bool)
+
+	fmt.Fprintf(w, "    comparing booleans.\n")
+	ext = ext[:0]
+	for name := range a.ExtBools {
+		ext = append(ext, name)
+	}
+	for name := range b.ExtBools {
+		ext = append(ext, name)
+	}
+	for _, name := range capNames(len(a.Bools), len(b.Bools), func(i int) string {
+		return terminfo.BoolCap(i).Name()
+	}, ext) {
+		if av, bv := boolValue(a, name), boolValue(b, name); av != bv {
+			fmt.Fprintf(w, "\t%v: %v:%v.\n", name, av, bv)
+			diff[name] = true
+		}
+	}
+
+	fmt.Fprintf(w, "    comparing numbers.\n")
+	ext = ext[:0]
+	for name := range a.ExtNumbers {
+		ext = append(ext, name)
+	}
+	for name := range b.ExtNumbers {
+		ext = append(ext, name)
+	}
+	for _, name := range capNames(len(a.Numbers), len(b.Numbers), func(i int) string {
+		return terminfo.NumCap(i).Name()
+	}, ext) {
+		if av, bv := numValue(a, name), numValue(b, name); av != bv {
+			fmt.Fprintf(w, "\t%v: %v, %v.\n", name, av, bv)
+			diff[name] = true
+		}
+	}
+
+	fmt.Fprintf(w, "    comparing strings.\n")
+	ext = ext[:0]
+	for name := range a.ExtStrings {
+		ext = append(ext, name)
+	}
+	for name := range b.ExtStrings {
+		ext = append(ext, name)
+	}
+	ext = append(ext, cancelledExt(a)...)
+	ext = append(ext, cancelledExt(b)...)
+	for _, name := range capNames(len(a.Strings), len(b.Strings), func(i int) string {
+		return terminfo.StrCap(i).Name()
+	}, ext) {
+		if av, bv := strValue(a, name), strValue(b, name); av != bv {
+			fmt.Fprintf(w, "\t%v: %v, %v.\n", name, av, bv)
+			diff[name] = true
+		}
+	}
+	return diff
+}
+
+// writeOrigins writes comments listing the capabilities that the named entry
+// inherited by use=, grouped by where they were found; if only is non-nil,
+// other capabilities are omitted.
+func writeOrigins(w io.Writer, name string, origin terminfo.Origin, only map[string]bool) {
+	groups := make(map[string][]string)
+	for c, o := range origin.Caps {
+		if only == nil || only[c] {
+			s := o.String()
+			groups[s] = append(groups[s], c)
+		}
+	}
+	froms := make([]string, 0, len(groups))
+	for from := range groups {
+		froms = append(froms, from)
+	}
+	sort.Strings(froms)
+	for _, from := range froms {
+		caps := groups[from]
+		sort.Strings(caps)
+		fmt.Fprintf(w, "#\t%v inherits from %v:\n", name, from)
+		line := ""
+		for _, c := range caps {
+			if line != "" && len(line)+len(c)+2 > maxCommentWidth {
+				fmt.Fprintf(w, "#\t\t%v\n", line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += c + ","
+		}
+		fmt.Fprintf(w, "#\t\t%v\n", line)
+	}
+}
+
+// maxCommentWidth is the width at which writeOrigins wraps capability lists,
+// not counting the leading comment and tabs.
+const maxCommentWidth = 60
+
+// cancelledExt returns the names of any cancelled extended capabilities, which
+// are compared as strings, like infocmp does for unknown cancelled names.
+func cancelledExt(ti *terminfo.Terminfo) (names []string) {
+	for name := range ti.Cancelled {
+		if !isStandardCap(ti, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func isStandardCap(ti *terminfo.Terminfo, name string) bool {
+	for i := range ti.Bools {
+		if terminfo.BoolCap(i).Name() == name {
+			return true
+		}
+	}
+	for i := range ti.Numbers {
+		if terminfo.NumCap(i).Name() == name {
+			return true
+		}
+	}
+	for i := range ti.Strings {
+		if terminfo.StrCap(i).Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// capNames returns the sorted and deduplicated union of the first an or bn
+// standard capability names (whichever is more), and the given extended
+// capability names.
+func capNames(an, bn int, name func(int) string, ext []string) []string {
+	n := an
+	if bn > n {
+		n = bn
+	}
+	names := make([]string, 0, n+len(ext))
+	for i := 0; i < n; i++ {
+		names = append(names, name(i))
+	}
+	names = append(names, ext...)
+	sort.Strings(names)
+	uniq := names[:0]
+	for _, name := range names {
+		if len(uniq) == 0 || name != uniq[len(uniq)-1] {
+			uniq = append(uniq, name)
+		}
+	}
+	return uniq
+}
+
+// boolValue, numValue, and strValue format capability values like infocmp
+// -d: "@" for cancelled, and "NULL" for absent numbers and strings.
+
+func boolValue(ti *terminfo.Terminfo, name string) string {
+	switch {
+	case ti.Cancelled[name]:
+		return "@"
+	case ti.BoolNamed(name):
+		return "T"
+	}
+	return "F"
+}
+
+func numValue(ti *terminfo.Terminfo, name string) string {
+	if ti.Cancelled[name] {
+		return "@"
+	}
+	if n, ok := ti.NumNamed(name); ok {
+		return fmt.Sprint(n)
+	}
+	return "NULL"
+}
+
+func strValue(ti *terminfo.Terminfo, name string) string {
+	if ti.Cancelled[name] {
+		return "@"
+	}
+	if s, ok := ti.StrNamed(name); ok {
+		return "'" + terminfo.EscapeSource(s) + "'"
+	}
+	return "NULL"
+}
@@ -14,8 +14,9 @@ This is a very rough prototype of an "interact" command:
 - beyond basic free-form variables, there's an obvious path for a small DSL to
   specify numeric arguments, enumeration arguments, and such
 - another direction would be allow free from adding and removing of arguments
-- command output handling is nascent, should at least support paging,
-  scrolling, etc; may even consider embedding $PAGER here...
+- the command runs live on a pty, and receives input while no variable is
+  being edited; but its output should at least support paging, scrolling, etc
+  once it exits; may even consider embedding $PAGER here...
 - ...speaking of embedding, a more advanced feature would be to support a stdin
   file, and embed $EDITOR
 - finally, other adjacent features, like parity with `watch(1)` come easily to mind
@@ -25,15 +26,14 @@ This is a very rough prototype of an "interact" command:
 import (
 	"errors"
 	"flag"
-	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/exec"
 	"strings"
 
-	"github.com/jcorbin/anansi"
 	"github.com/jcorbin/anansi/ansi"
+	"github.com/jcorbin/anansi/pty"
 	"github.com/jcorbin/anansi/x/platform"
 )
 
@@ -49,7 +49,9 @@ func Run(p *platform.Platform) error {
 	for {
 		in := inspect{}
 		in.setCmd(cmd)
-		if err := p.Run(&in); platform.IsReplayDone(err) {
+		err := p.Run(&in)
+		in.stopCmd()
+		if platform.IsReplayDone(err) {
 			continue // loop replay
 		} else if err == io.EOF || err == errInt {
 			return nil
@@ -69,7 +71,7 @@ type inspect struct {
 	edid int
 	ed   platform.EditLine
 
-	cmdOutput anansi.ScreenDiffer
+	cmdView platform.TerminalView
 }
 
 func (in *inspect) setCmd(cmd []string) {
@@ -99,13 +101,8 @@ func (in *inspect) haveAllArgVals() bool {
 }
 
 func (in *inspect) runCmd() {
-	in.cmdOutput.Clear()
-	if in.cmdOutput.Bounds().Empty() {
-		return
-	}
-	in.cmdOutput.To(ansi.Pt(1, 1))
-	if !in.haveAllArgVals() {
-		in.cmdOutput.WriteString("Define variables to run")
+	in.stopCmd()
+	if in.cmdView.Box.Empty() || !in.haveAllArgVals() {
 		return
 	}
 
@@ -114,19 +111,19 @@ func (in *inspect) runCmd() {
 		args[in.argi[ii]] = val
 	}
 
-	cmd := exec.Command(args[0], args[1:]...)
-	// TODO pipe into cmdOutput; pty
-	out, err := cmd.Output()
-
-	// TODO CR handling no work
-	fmt.Fprintf(&in.cmdOutput, "status: %v\r\n", cmd.ProcessState)
+	term, err := pty.StartTerminal(exec.Command(args[0], args[1:]...), in.cmdView.Box.Size())
 	if err != nil {
-		in.cmdOutput.WriteSGR(ansi.SGRRed.FG())
-		fmt.Fprintf(&in.cmdOutput, "error: %v\r\n", err)
-		in.cmdOutput.WriteSGR(ansi.SGRAttrClear)
+		log.Printf("unable to run %q: %v", args, err)
+		return
 	}
-	in.cmdOutput.Write(out)
+	in.cmdView.Term = term
+}
 
+func (in *inspect) stopCmd() {
+	if term := in.cmdView.Term; term != nil {
+		in.cmdView.Term = nil
+		log.Printf("command done: %v", term.Close())
+	}
 }
 
 func (in *inspect) Update(ctx *platform.Context) (err error) {
@@ -240,16 +237,24 @@ func (in *inspect) Update(ctx *platform.Context) (err error) {
 	}
 	p.Y++
 
-	sz := ctx.Output.Bounds().Size()
-	sz.Y -= p.Y
-	if !in.cmdOutput.Bounds().Size().Eq(sz) {
-		log.Printf("resize cmd out %v", sz)
-		in.cmdOutput.Resize(sz)
+	box := ctx.Output.Bounds()
+	box.Min.Y = p.Y
+	if in.cmdView.Box.Empty() && !box.Empty() {
+		in.cmdView.Box = box
 		in.runCmd()
 	}
+	in.cmdView.Box = box
 
-	// TODO scroll w/in cmdOutput
-	anansi.DrawGrid(ctx.Output.Grid.SubAt(p), in.cmdOutput.Grid)
+	if in.cmdView.Term == nil {
+		ctx.Output.To(p)
+		ctx.Output.WriteString("Define variables to run")
+	} else {
+		// TODO scroll w/in cmdView
+		in.cmdView.Focused = in.edid == 0
+		if uerr := in.cmdView.Update(ctx); err == nil {
+			err = uerr
+		}
+	}
 
 	return err
 }
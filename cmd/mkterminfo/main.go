@@ -0,0 +1,248 @@
+// Command mkterminfo generates a builtin Terminfo Go file for package
+// terminfo; it is run by go generate, see terminfo/gen.go.
+//
+// Usage:
+//
+//	mkterminfo [-src FILE | -dir DIR] [-o DIR] NAME [PARTIAL...]
+//
+// The named entry is read either from a terminfo source file, or from a
+// compiled terminfo directory, and written to builtin_NAME_terminfo.go with
+// its full capability set. Any PARTIAL names are added to the builtin compat
+// table, so that GetBuiltin will use the entry for any terminal name
+// containing them.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jcorbin/anansi/terminfo"
+)
+
+var (
+	srcFlag = flag.String("src", "", "read entry from a terminfo source file")
+	dirFlag = flag.String("dir", "", "read entry from a compiled terminfo directory")
+	outFlag = flag.String("o", ".", "directory to write generated code into")
+)
+
+func main() {
+	flag.Parse()
+	if err := run(flag.Args()); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing terminal name argument")
+	}
+	name, partials := args[0], args[1:]
+
+	ti, err := load(name)
+	if err != nil {
+		return err
+	}
+
+	code, err := generate(ti, partials)
+	if err != nil {
+		return err
+	}
+
+	fileName := "builtin_" + strings.ToLower(strings.NewReplacer("-", "_", ".", "_", "+", "_").Replace(name)) + "_terminfo.go"
+	return os.WriteFile(filepath.Join(*outFlag, fileName), code, 0644)
+}
+
+func load(name string) (*terminfo.Terminfo, error) {
+	switch {
+	case *srcFlag != "":
+		return terminfo.LoadSource(*srcFlag, name)
+	case *dirFlag != "":
+		f, err := os.Open(filepath.Join(*dirFlag, name[:1], name))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		var ti terminfo.Terminfo
+		if err := ti.ReadFrom(f); err != nil {
+			return nil, fmt.Errorf("%v: %w", f.Name(), err)
+		}
+		return &ti, nil
+	}
+	return terminfo.Load(name)
+}
+
+func generate(ti *terminfo.Terminfo, partials []string) ([]byte, error) {
+	var (
+		buf     bytes.Buffer
+		varName = goName(ti.Name)
+	)
+	fmt.Fprintf(&buf, "// Code generated by mkterminfo; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package terminfo\n\n")
+	fmt.Fprintf(&buf, "var %s = Terminfo{\n", varName)
+	fmt.Fprintf(&buf, "Name: %q,\n", ti.Name)
+
+	fmt.Fprintf(&buf, "Names: []string{")
+	for i, name := range ti.Names {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(&buf, "%q", name)
+	}
+	buf.WriteString("},\n")
+
+	buf.WriteString("Bools: builtinBools(\n")
+	for i, b := range ti.Bools {
+		if b {
+			fmt.Fprintf(&buf, "%s,\n", capGoName("Bool", terminfo.BoolCap(i).LongName()))
+		}
+	}
+	buf.WriteString("),\n")
+
+	buf.WriteString("Numbers: builtinNumbers(map[NumCap]int{\n")
+	for i, n := range ti.Numbers {
+		if n >= 0 {
+			fmt.Fprintf(&buf, "%s: %d,\n", capGoName("Num", terminfo.NumCap(i).LongName()), n)
+		}
+	}
+	buf.WriteString("}),\n")
+
+	buf.WriteString("Strings: builtinStrings(map[StrCap]string{\n")
+	for i, s := range ti.Strings {
+		if s != "" {
+			fmt.Fprintf(&buf, "%s: %s,\n", capGoName("Str", terminfo.StrCap(i).LongName()), strconv.Quote(s))
+		}
+	}
+	buf.WriteString("}),\n")
+
+	if len(ti.EmptyStrings) > 0 {
+		buf.WriteString("EmptyStrings: map[StrCap]bool{\n")
+		for i := range ti.Strings {
+			if c := terminfo.StrCap(i); ti.EmptyStrings[c] {
+				fmt.Fprintf(&buf, "%s: true,\n", capGoName("Str", c.LongName()))
+			}
+		}
+		buf.WriteString("},\n")
+	}
+	if len(ti.Cancelled) > 0 {
+		buf.WriteString("Cancelled: map[string]bool{\n")
+		names := make([]string, 0, len(ti.Cancelled))
+		for name := range ti.Cancelled {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(&buf, "%q: true,\n", name)
+		}
+		buf.WriteString("},\n")
+	}
+	if len(ti.ExtBools) > 0 {
+		buf.WriteString("ExtBools: map[string]bool{\n")
+		names := make([]string, 0, len(ti.ExtBools))
+		for name := range ti.ExtBools {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(&buf, "%q: %v,\n", name, ti.ExtBools[name])
+		}
+		buf.WriteString("},\n")
+	}
+	if len(ti.ExtNumbers) > 0 {
+		buf.WriteString("ExtNumbers: map[string]int{\n")
+		names := make([]string, 0, len(ti.ExtNumbers))
+		for name := range ti.ExtNumbers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(&buf, "%q: %d,\n", name, ti.ExtNumbers[name])
+		}
+		buf.WriteString("},\n")
+	}
+	if len(ti.ExtStrings) > 0 {
+		buf.WriteString("ExtStrings: map[string]string{\n")
+		names := make([]string, 0, len(ti.ExtStrings))
+		for name := range ti.ExtStrings {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(&buf, "%q: %s,\n", name, strconv.Quote(ti.ExtStrings[name]))
+		}
+		buf.WriteString("},\n")
+	}
+	buf.WriteString("}\n\n")
+
+	fmt.Fprintf(&buf, "func init() {\n")
+	fmt.Fprintf(&buf, "registerBuiltin(&%s", varName)
+	for _, partial := range partials {
+		fmt.Fprintf(&buf, ", %q", partial)
+	}
+	buf.WriteString(")\n}\n")
+
+	return format.Source(buf.Bytes())
+}
+
+// goName returns a Go identifier for a terminal name, e.g. "rxvt-unicode"
+// becomes "rxvtUnicode".
+func goName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, part := range parts {
+		if i == 0 {
+			parts[i] = strings.ToLower(part[:1]) + part[1:]
+		} else {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// capGoName returns the name of a capability constant, given its type prefix
+// and long name; this must match the naming used by terminfo/caps.go.
+func capGoName(prefix, longName string) string {
+	var buf strings.Builder
+	buf.WriteString(prefix)
+	for _, word := range strings.Split(longName, "_") {
+		if up, def := capWords[word]; def {
+			buf.WriteString(up)
+		} else if word != "" {
+			buf.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return buf.String()
+}
+
+var capWords = map[string]string{
+	"acs":  "ACS",
+	"ansi": "ANSI",
+	"bs":   "BS",
+	"ca":   "CA",
+	"cr":   "CR",
+	"crt":  "CRT",
+	"ctlc": "CtlC",
+	"dec":  "DEC",
+	"dsl":  "DSL",
+	"esc":  "Esc",
+	"ff":   "FF",
+	"hp":   "HP",
+	"id":   "ID",
+	"lf":   "LF",
+	"nl":   "NL",
+	"pc":   "PC",
+	"rgb":  "RGB",
+	"sgr":  "SGR",
+	"xoff": "XOFF",
+	"xon":  "XON",
+}
@@ -0,0 +1,287 @@
+// Command probe asks the attached terminal what it supports, and reports the
+// results side by side with the claims made by its terminfo entry; attach its
+// -json output to bug reports about misbehaving terminals.
+//
+// The terminal is put into raw mode, and then probed using:
+//   - XTVERSION, DA2, and DA1 identification queries
+//   - DECRQM mode state queries
+//   - XTGETTCAP terminfo capability queries
+//   - OSC 10, 11, and 4 color queries
+//   - XTWINOPS size queries
+//   - CPR cursor position queries after writing test strings, to measure how
+//     wide the terminal renders them
+//
+// Terminals that don't support a query simply don't reply, so each query
+// waits for up to -timeout before giving up.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jcorbin/anansi"
+	"github.com/jcorbin/anansi/ansi"
+	"github.com/jcorbin/anansi/terminfo"
+)
+
+var (
+	jsonFlag    = flag.Bool("json", false, "write results as JSON")
+	timeoutFlag = flag.Duration("timeout", 250*time.Millisecond, "how long to wait for each reply")
+)
+
+// Report is the result of probing a terminal.
+type Report struct {
+	Term     string   `json:"term"`
+	Terminfo string   `json:"terminfo"` // where the terminfo entry came from
+	Results  []Result `json:"results"`
+}
+
+// Result is a single probed feature; Terminal is empty if the terminal didn't
+// reply, Terminfo is empty if the entry makes no related claim.
+type Result struct {
+	Section  string `json:"section"`
+	Name     string `json:"name"`
+	Terminal string `json:"terminal"`
+	Terminfo string `json:"terminfo,omitempty"`
+}
+
+func main() {
+	flag.Parse()
+
+	var rep Report
+	rep.Term = os.Getenv("TERM")
+	ti, origin, err := terminfo.Locate(rep.Term)
+	if err != nil {
+		rep.Terminfo = err.Error()
+	} else {
+		rep.Terminfo = origin.String()
+	}
+
+	term := anansi.NewTerm(os.Stdin, os.Stdout)
+	if !term.IsTerminal() {
+		anansi.MustRun(fmt.Errorf("probe must be run in a terminal"))
+	}
+	term.Terminfo = ti
+	if err := term.SetRaw(true); err != nil {
+		anansi.MustRun(err)
+	}
+	anansi.MustRun(term.RunWith(func(term *anansi.Term) error {
+		p := prober{term: term, ti: ti, timeout: *timeoutFlag}
+		rep.Results = p.probe()
+		return nil
+	}))
+
+	if *jsonFlag {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		anansi.MustRun(enc.Encode(rep))
+	} else {
+		anansi.MustRun(writeTable(os.Stdout, rep))
+	}
+}
+
+func writeTable(w io.Writer, rep Report) error {
+	fmt.Fprintf(w, "TERM=%v terminfo: %v\n\n", rep.Term, rep.Terminfo)
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "SECTION\tNAME\tTERMINAL\tTERMINFO\n")
+	for _, r := range rep.Results {
+		terminal := r.Terminal
+		if terminal == "" {
+			terminal = "-"
+		}
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\n", r.Section, r.Name, terminal, r.Terminfo)
+	}
+	return tw.Flush()
+}
+
+type prober struct {
+	term    *anansi.Term
+	ti      *terminfo.Terminfo
+	timeout time.Duration
+	results []Result
+}
+
+func (p *prober) add(section, name, terminal, claim string) {
+	p.results = append(p.results, Result{section, name, terminal, claim})
+}
+
+func (p *prober) probe() []Result {
+	p.probeDevice()
+	p.probeModes()
+	p.probeTermcaps()
+	p.probeColors()
+	p.probeSize()
+	p.probeWidths()
+	return p.results
+}
+
+func (p *prober) probeDevice() {
+	da, _ := p.term.QueryDeviceAttributes(p.timeout)
+	name := ""
+	if p.ti != nil {
+		name = p.ti.Name
+	}
+	p.add("device", "XTVERSION", da.Version, name)
+	p.add("device", "DA2", formatInts(da.Secondary), "")
+	p.add("device", "DA1", formatInts(da.Primary), "")
+}
+
+// probedModes pairs modes with the terminfo capability that would use them.
+var probedModes = []struct {
+	name string
+	mode ansi.Mode
+	cap  string
+}{
+	{"DECCKM (cursor keys)", ansi.ModeCursorKeys, "smkx"},
+	{"DECAWM (auto wrap)", ansi.ModeAutoWrap, "am"},
+	{"DECTCEM (show cursor)", ansi.ShowCursor, "cnorm"},
+	{"alternate screen", ansi.ModeAlternateScreen, "smcup"},
+	{"mouse vt200", ansi.ModeMouseVt200, "kmous"},
+	{"mouse button events", ansi.ModeMouseBtnEvent, "XM"},
+	{"mouse any events", ansi.ModeMouseAnyEvent, "XM"},
+	{"mouse sgr", ansi.ModeMouseSgrExt, "XM"},
+	{"focus events", ansi.ModeMouseFocusEvent, "fe"},
+	{"bracketed paste", ansi.ModeBracketedPaste, "BE"},
+	{"synchronized output", ansi.ModeSynchronizedOutput, "Sync"},
+}
+
+func (p *prober) probeModes() {
+	modes := make([]ansi.Mode, len(probedModes))
+	for i, pm := range probedModes {
+		modes[i] = pm.mode
+	}
+	states, _ := p.term.QueryModes(p.timeout, modes...)
+	for _, pm := range probedModes {
+		reported := ""
+		if st, ok := states[pm.mode]; ok {
+			reported = st.String()
+		}
+		p.add("mode", pm.name, reported, p.claim(pm.cap))
+	}
+}
+
+// probedTermcaps are capabilities queried by XTGETTCAP.
+var probedTermcaps = []string{
+	"TN", "colors", "RGB", "Tc",
+	"setrgbf", "setrgbb", "Smulx", "Ss", "Se", "Ms",
+	"smcup", "rmcup", "kbs", "kcuu1", "kf1",
+}
+
+func (p *prober) probeTermcaps() {
+	caps, _ := p.term.QueryTermcap(p.timeout, probedTermcaps...)
+	for _, name := range probedTermcaps {
+		reported, ok := caps[name]
+		if ok && reported == "" {
+			reported = "true" // boolean
+		} else {
+			reported = terminfo.EscapeSource(reported)
+		}
+		p.add("termcap", name, reported, p.claim(name))
+	}
+}
+
+func (p *prober) probeColors() {
+	cs, _ := p.term.QueryColors(p.timeout)
+	p.add("color", "foreground", formatColor(cs.Foreground), "")
+	p.add("color", "background", formatColor(cs.Background), "")
+	known := 0
+	for i, c := range cs.Theme {
+		if c != ansi.SGRColor(i) { // unknown colors are left as-is
+			known++
+		}
+	}
+	reported := ""
+	if known > 0 {
+		reported = fmt.Sprintf("%v/%v colors", known, len(cs.Theme))
+	}
+	p.add("color", "palette", reported, p.claim("colors"))
+	if cs.Foreground != 0 && cs.Background != 0 {
+		p.add("color", "dark", strconv.FormatBool(cs.IsDark()), "")
+	}
+}
+
+func (p *prober) probeSize() {
+	ts, _ := p.term.QuerySize(p.timeout)
+	reported := ""
+	if ts.Cells.X > 0 && ts.Cells.Y > 0 {
+		reported = fmt.Sprintf("%vx%v", ts.Cells.X, ts.Cells.Y)
+	}
+	claim := ""
+	if cols, lines := p.claim("cols"), p.claim("lines"); cols != "" && lines != "" {
+		claim = cols + "x" + lines
+	}
+	p.add("size", "cells", reported, claim)
+	if ts.Cell.X > 0 && ts.Cell.Y > 0 {
+		p.add("size", "cell pixels", fmt.Sprintf("%vx%v", ts.Cell.X, ts.Cell.Y), "")
+	}
+}
+
+// probedWidths are strings whose rendered width varies between terminals;
+// width is what Unicode (UAX #11 and UTS #51) says it should be.
+var probedWidths = []struct {
+	name  string
+	s     string
+	width int
+}{
+	{"ascii", "a", 1},
+	{"combining accent", "e\u0301", 1},
+	{"ambiguous width", "\u00A7", 1},
+	{"cjk ideograph", "\u4E2D", 2},
+	{"emoji", "\U0001F600", 2},
+	{"text presentation", "\u2764", 1},
+	{"emoji presentation selector", "\u2764\uFE0F", 2},
+	{"emoji modifier", "\U0001F44D\U0001F3FD", 2},
+	{"emoji zwj sequence", "\U0001F468\u200D\U0001F469\u200D\U0001F467", 2},
+	{"regional indicator flag", "\U0001F1FA\U0001F1F8", 2},
+}
+
+func (p *prober) probeWidths() {
+	for _, pw := range probedWidths {
+		reported := ""
+		if width, err := p.term.MeasureWidth(pw.s, p.timeout); err == nil {
+			reported = strconv.Itoa(width)
+		}
+		p.add("width", pw.name, reported, "expect "+strconv.Itoa(pw.width))
+	}
+}
+
+// claim returns the terminfo entry's value for the named capability, or the
+// empty string if it is absent.
+func (p *prober) claim(name string) string {
+	if p.ti == nil {
+		return ""
+	}
+	if s, ok := p.ti.StrNamed(name); ok {
+		return terminfo.EscapeSource(s)
+	}
+	if n, ok := p.ti.NumNamed(name); ok {
+		return strconv.Itoa(n)
+	}
+	if p.ti.BoolNamed(name) {
+		return "true"
+	}
+	return ""
+}
+
+func formatInts(ns []int) string {
+	parts := make([]string, len(ns))
+	for i, n := range ns {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ";")
+}
+
+func formatColor(c ansi.SGRColor) string {
+	if c == 0 {
+		return ""
+	}
+	r, g, b := c.RGB()
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
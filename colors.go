@@ -0,0 +1,171 @@
+package anansi
+
+import (
+	"time"
+
+	"github.com/jcorbin/anansi/ansi"
+)
+
+// numThemeColors is how many palette colors are queried and restored: the 8
+// classic colors and their bright counterparts.
+const numThemeColors = 16
+
+// Colors holds a terminal's default foreground and background colors, and its
+// definition of the first 16 palette colors.
+//
+// Colors reported by a terminal are always 24-bit, so a zero Foreground or
+// Background means that it is unknown; similarly any Theme entry that is not
+// 24-bit (e.g. ansi.SGRColor(i) for entry i) means that it is unknown.
+type Colors struct {
+	Foreground ansi.SGRColor
+	Background ansi.SGRColor
+	Theme      ansi.ColorTheme
+}
+
+// IsDark returns true if the background color is darker than the foreground
+// color; if either is unknown, dark is assumed, since that's the most common
+// terminal default.
+func (cs Colors) IsDark() bool {
+	if cs.Foreground == 0 || cs.Background == 0 {
+		return true
+	}
+	return luma(cs.Background) < luma(cs.Foreground)
+}
+
+func luma(c ansi.SGRColor) int {
+	r, g, b := c.RGB()
+	return 2126*int(r) + 7152*int(g) + 722*int(b)
+}
+
+// QueryColors asks the terminal for its default foreground and background
+// colors, and for its first 16 palette colors, using OSC 10, 11, and 4
+// queries. All queries are sent at once, and then replies are collected until
+// all have been received, or the timeout elapses. An error for which
+// IsNoReply() is true is returned only if no replies were received at all;
+// partial replies leave any unreported colors unknown.
+func (term *Term) QueryColors(timeout time.Duration) (cs Colors, err error) {
+	if timeout == 0 {
+		timeout = defaultQueryTimeout
+	}
+
+	reqs := make([]ansi.Seq, 0, 2+numThemeColors)
+	reqs = append(reqs, ansi.QueryForeground(), ansi.QueryBackground())
+	for i := 0; i < numThemeColors; i++ {
+		reqs = append(reqs, ansi.QueryPaletteColor(i))
+	}
+
+	cs.Theme = make(ansi.ColorTheme, numThemeColors)
+	for i := range cs.Theme {
+		cs.Theme[i] = ansi.SGRColor(i)
+	}
+
+	if err := term.Request(reqs...); err != nil {
+		return cs, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for n := 0; n < len(reqs); n++ {
+		_, a, err := term.Input.AwaitReply(time.Until(deadline), ansi.IsColorReply)
+		if IsNoReply(err) && n > 0 {
+			break
+		} else if err != nil {
+			return cs, err
+		}
+		ps, i, c, err := ansi.DecodeColorReply(a)
+		if err != nil {
+			continue
+		}
+		switch ps {
+		case ansi.OSCForeground:
+			cs.Foreground = c
+		case ansi.OSCBackground:
+			cs.Background = c
+		case ansi.OSCPaletteColor:
+			if 0 <= i && i < len(cs.Theme) {
+				cs.Theme[i] = c
+			}
+		}
+	}
+	return cs, nil
+}
+
+// TermColors supports saving, overriding, and restoring terminal colors as a
+// Term Context.
+//
+// During its first Enter, it queries the terminal's current colors into
+// Saved; a terminal that doesn't reply is not an error, Saved simply remains
+// unknown. Every Enter then applies any non-zero Override colors; every Exit
+// restores any overridden colors to their Saved values (or to the terminal's
+// defaults if unknown). This makes it safe to use under Term.RunWithout and
+// Term.Suspend.
+//
+// Querying requires the terminal to already be in raw mode when entered (see
+// Attr.SetRaw).
+type TermColors struct {
+	Override Colors
+	Saved    Colors
+	Timeout  time.Duration
+
+	saved bool
+	buf   Buffer
+}
+
+// Enter saves terminal colors (only the first time), and applies any
+// overrides.
+func (tc *TermColors) Enter(term *Term) error {
+	if !tc.saved {
+		cs, err := term.QueryColors(tc.Timeout)
+		if err != nil && !IsNoReply(err) {
+			return err
+		}
+		tc.Saved, tc.saved = cs, true
+	}
+
+	if c := tc.Override.Foreground; c != 0 {
+		tc.buf.WriteSeq(ansi.SetForeground(c))
+	}
+	if c := tc.Override.Background; c != 0 {
+		tc.buf.WriteSeq(ansi.SetBackground(c))
+	}
+	for i, c := range tc.Override.Theme {
+		tc.buf.WriteSeq(ansi.SetPaletteColor(i, c))
+	}
+	return term.Flush(&tc.buf)
+}
+
+// Exit restores any overridden colors.
+func (tc *TermColors) Exit(term *Term) error {
+	if tc.Override.Foreground != 0 {
+		if c := tc.Saved.Foreground; c != 0 {
+			tc.buf.WriteSeq(ansi.SetForeground(c))
+		} else {
+			tc.buf.WriteSeq(ansi.ResetForeground())
+		}
+	}
+	if tc.Override.Background != 0 {
+		if c := tc.Saved.Background; c != 0 {
+			tc.buf.WriteSeq(ansi.SetBackground(c))
+		} else {
+			tc.buf.WriteSeq(ansi.ResetBackground())
+		}
+	}
+	for i := range tc.Override.Theme {
+		if c, known := tc.savedThemeColor(i); known {
+			tc.buf.WriteSeq(ansi.SetPaletteColor(i, c))
+		} else {
+			tc.buf.WriteSeq(ansi.ResetPaletteColor(i))
+		}
+	}
+	return term.Flush(&tc.buf)
+}
+
+func (tc *TermColors) savedThemeColor(i int) (ansi.SGRColor, bool) {
+	if i < len(tc.Saved.Theme) {
+		if c := tc.Saved.Theme[i]; c != ansi.SGRColor(i) {
+			return c, true
+		}
+	}
+	return 0, false
+}
+
+var _ Context = &TermColors{}
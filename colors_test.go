@@ -0,0 +1,102 @@
+package anansi_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi"
+	"github.com/jcorbin/anansi/ansi"
+)
+
+func TestTermColors(t *testing.T) {
+	inR, inW, err := blockingPipe()
+	require.NoError(t, err)
+	defer inR.Close()
+	defer inW.Close()
+	outR, outW, err := blockingPipe()
+	require.NoError(t, err)
+	defer outR.Close()
+
+	term := anansi.NewTerm(inR, outW)
+
+	var (
+		wg  sync.WaitGroup
+		out bytes.Buffer
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var buf [4096]byte
+		replied := false
+		for {
+			n, err := outR.Read(buf[:])
+			out.Write(buf[:n])
+			if !replied && bytes.Contains(out.Bytes(), []byte("\x1b]4;15;?\x1b\\")) {
+				// reply to only some queries, with other input interleaved
+				replied = true
+				_, _ = io.WriteString(inW, "a\x1b]10;rgb:eeee/eeee/eeee\x1b\\")
+				_, _ = io.WriteString(inW, "\x1b]11;rgb:1111/1111/1111\x1b\\b")
+				for i := 0; i < 8; i++ {
+					_, _ = io.WriteString(inW, fmt.Sprintf("\x1b]4;%d;rgb:%02x/00/00\x1b\\", i, i))
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	tc := anansi.TermColors{Timeout: 50 * time.Millisecond}
+	tc.Override.Background = ansi.RGB(0, 0, 0x40)
+	tc.Override.Theme = ansi.ColorTheme{ansi.RGB(0x20, 0, 0)}
+	require.NoError(t, tc.Enter(term))
+	require.NoError(t, tc.Exit(term))
+	require.NoError(t, tc.Enter(term))
+	require.NoError(t, tc.Exit(term))
+
+	outW.Close()
+	wg.Wait()
+
+	assert.Equal(t, ansi.RGB(0xee, 0xee, 0xee), tc.Saved.Foreground)
+	assert.Equal(t, ansi.RGB(0x11, 0x11, 0x11), tc.Saved.Background)
+	assert.True(t, tc.Saved.IsDark())
+	if assert.Len(t, tc.Saved.Theme, 16) {
+		assert.Equal(t, ansi.RGB(0x07, 0, 0), tc.Saved.Theme[7])
+		assert.Equal(t, ansi.SGRColor(8), tc.Saved.Theme[8], "expected unreported color to be unknown")
+	}
+
+	var expected []byte
+	expected = ansi.QueryForeground().AppendTo(expected)
+	expected = ansi.QueryBackground().AppendTo(expected)
+	for i := 0; i < 16; i++ {
+		expected = ansi.QueryPaletteColor(i).AppendTo(expected)
+	}
+	for i := 0; i < 2; i++ {
+		expected = ansi.SetBackground(ansi.RGB(0, 0, 0x40)).AppendTo(expected)
+		expected = ansi.SetPaletteColor(0, ansi.RGB(0x20, 0, 0)).AppendTo(expected)
+		expected = ansi.SetBackground(ansi.RGB(0x11, 0x11, 0x11)).AppendTo(expected)
+		expected = ansi.SetPaletteColor(0, ansi.RGB(0, 0, 0)).AppendTo(expected)
+	}
+	assert.Equal(t, string(expected), out.String())
+
+	var rs []rune
+	for e, _, ok := term.Decode(); ok; e, _, ok = term.Decode() {
+		rs = append(rs, rune(e))
+	}
+	assert.Equal(t, "ab", string(rs), "expected other input to remain")
+}
+
+func TestColors_IsDark(t *testing.T) {
+	assert.True(t, anansi.Colors{}.IsDark(), "expected unknown to be dark")
+	assert.False(t, anansi.Colors{
+		Foreground: ansi.RGB(0, 0, 0),
+		Background: ansi.RGB(0xff, 0xff, 0xf0),
+	}.IsDark())
+}
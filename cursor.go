@@ -124,7 +124,7 @@ func (c *VirtualCursor) Hide() {
 // Apply the given cursor state, writing any necessary escape sequences into
 // the internal buffer.
 func (c *VirtualCursor) Apply(cs Cursor) {
-	_, c.Cursor = cs.applyTo(&c.buf, c.Cursor)
+	_, c.Cursor = cs.applyTo(&c.buf, c.Cursor, nil)
 }
 
 var _ ansiWriter = &VirtualCursor{}
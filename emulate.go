@@ -0,0 +1,283 @@
+package anansi
+
+import (
+	"bytes"
+
+	"github.com/jcorbin/anansi/ansi"
+)
+
+// savedCursor is cursor state saved by DECSC, and by modes 1048 and 1049;
+// each screen buffer has its own.
+type savedCursor struct {
+	Cursor
+	charsets    charsets
+	origin      bool
+	pendingWrap bool
+}
+
+// saveCursor saves or restores cursor position, attributes, charsets, and
+// origin mode; restoring without a prior save homes the cursor and resets the
+// rest.
+func (sc *Screen) saveCursor(save bool) {
+	if save {
+		sc.saved = savedCursor{sc.Cursor, sc.charsets, sc.origin, sc.pendingWrap}
+		return
+	}
+	if !sc.saved.Point.Valid() {
+		sc.Cursor.Attr = 0
+		sc.charsets = charsets{}
+		sc.origin = false
+		sc.home()
+		return
+	}
+	sc.Cursor.Point = sc.clamp(sc.saved.Point)
+	sc.Cursor.Attr = sc.saved.Attr
+	sc.charsets = sc.saved.charsets
+	sc.origin = sc.saved.origin
+	sc.pendingWrap = sc.saved.pendingWrap
+}
+
+// margins returns the top and bottom rows of the scrolling region, as set by
+// DECSTBM; defaults to the entire screen.
+func (sc *Screen) margins() (top, bottom int) {
+	top, bottom = sc.Rect.Min.Y, sc.Rect.Max.Y-1
+	if sc.top != 0 && sc.bottom <= bottom {
+		top, bottom = sc.top, sc.bottom
+	}
+	return top, bottom
+}
+
+// setMargins implements DECSTBM, ignoring invalid regions; homes the cursor.
+func (sc *Screen) setMargins(a []byte) {
+	top, bottom := sc.Rect.Min.Y, sc.Rect.Max.Y-1
+	ta, ba := a, []byte(nil)
+	if i := bytes.IndexByte(a, ';'); i >= 0 {
+		ta, ba = a[:i], a[i+1:]
+	}
+	if len(ta) > 0 {
+		t, _, err := ansi.DecodeNumber(ta)
+		if err != nil {
+			return
+		} else if t > 0 {
+			top = t
+		}
+	}
+	if len(ba) > 0 {
+		b, _, err := ansi.DecodeNumber(ba)
+		if err != nil {
+			return
+		} else if b > 0 {
+			bottom = b
+		}
+	}
+	if top >= bottom || bottom >= sc.Rect.Max.Y {
+		return
+	}
+	if top == sc.Rect.Min.Y && bottom == sc.Rect.Max.Y-1 {
+		sc.top, sc.bottom = 0, 0
+	} else {
+		sc.top, sc.bottom = top, bottom
+	}
+	sc.home()
+}
+
+// home moves the cursor to the top left of the screen, or of the scrolling
+// region under origin mode.
+func (sc *Screen) home() {
+	sc.cursorTo(ansi.Pt(1, 1))
+}
+
+// cursorTo moves the cursor to the given point, which is relative to the
+// scrolling region under origin mode (DECOM).
+func (sc *Screen) cursorTo(pt ansi.Point) {
+	if !sc.origin {
+		sc.Cursor.Point = sc.clamp(pt)
+		return
+	}
+	top, bottom := sc.margins()
+	pt.Y += top - 1
+	if pt.Y > bottom {
+		pt.Y = bottom
+	}
+	sc.Cursor.Point = sc.clamp(pt)
+}
+
+// isTabStop returns true if there's a tab stop at the given column; unless
+// changed by HTS or TBC, there's one every 8 columns.
+func (sc *Screen) isTabStop(x int) bool {
+	if i := x - 1; i < len(sc.tabs) {
+		return sc.tabs[i]
+	}
+	return x > 1 && (x-1)%8 == 0
+}
+
+// setTabStop sets or clears the tab stop at the given column.
+func (sc *Screen) setTabStop(x int, stop bool) {
+	if sc.tabs == nil {
+		sc.resizeTabs(sc.Rect.Dx())
+	}
+	if i := x - 1; i >= 0 && i < len(sc.tabs) {
+		sc.tabs[i] = stop
+	}
+}
+
+// resizeTabs resizes any custom tab stops to the given width, setting default
+// stops in any new columns.
+func (sc *Screen) resizeTabs(width int) {
+	if width <= len(sc.tabs) {
+		if sc.tabs != nil {
+			sc.tabs = sc.tabs[:width]
+		}
+		return
+	}
+	for x := len(sc.tabs) + 1; x <= width; x++ {
+		sc.tabs = append(sc.tabs, x > 1 && (x-1)%8 == 0)
+	}
+}
+
+// tab moves the cursor forward (n > 0) or backward (n < 0) by n tab stops,
+// stopping at the screen edge.
+func (sc *Screen) tab(n int) {
+	r := sc.Bounds()
+	x := sc.Cursor.X
+	for ; n > 0 && x < r.Max.X-1; n-- {
+		for x++; x < r.Max.X-1 && !sc.isTabStop(x); x++ {
+		}
+	}
+	for ; n < 0 && x > r.Min.X; n++ {
+		for x--; x > r.Min.X && !sc.isTabStop(x); x-- {
+		}
+	}
+	sc.Cursor.X = x
+}
+
+// writeRune writes a graphic rune at the cursor, advancing it. The cursor
+// does not advance past the last column; rather, under autowrap (DECAWM), the
+// last column flag is set, and the next graphic rune wraps onto the next line
+// before being written.
+func (sc *Screen) writeRune(r rune) {
+	br := sc.Bounds()
+	if sc.pendingWrap {
+		sc.pendingWrap = false
+		sc.setWrapped(sc.Cursor.Y, true)
+		sc.Cursor.X = br.Min.X
+		sc.linefeed()
+	}
+	if i, ok := sc.Grid.CellOffset(sc.Cursor.Point); ok {
+		sc.Grid.Rune[i], sc.Grid.Attr[i] = r, sc.Cursor.Attr
+	}
+	if sc.Cursor.X+1 < br.Max.X {
+		sc.Cursor.X++
+	} else if !sc.noAutoWrap {
+		sc.pendingWrap = true
+	}
+}
+
+// processControl handles C0 and C1 control runes, returning false if e is
+// not a supported one.
+func (sc *Screen) processControl(e ansi.Escape) bool {
+	switch e {
+	case '\x07': // BEL; nothing to emulate
+	case '\x08': // BS
+		if sc.Cursor.X > sc.Bounds().Min.X {
+			sc.Cursor.X--
+		}
+	case '\x09': // HT
+		sc.tab(1)
+	case '\x0A', '\x0B', '\x0C', '\x84': // LF, VT, FF, IND
+		sc.linefeed()
+	case '\x0D': // CR
+		sc.Cursor.X = sc.Bounds().Min.X
+	case '\x85': // NEL
+		sc.Cursor.X = sc.Bounds().Min.X
+		sc.linefeed()
+	case '\x88': // HTS
+		sc.setTabStop(sc.Cursor.X, true)
+	case '\x8D': // RI
+		sc.reverseIndex()
+	default:
+		return false
+	}
+	return true
+}
+
+// processCursorEscape handles cursor escape sequences that need screen state
+// beyond what Cursor.processEscape supports, returning false if e is not one,
+// or if its arguments are invalid.
+func (sc *Screen) processCursorEscape(e ansi.Escape, a []byte) bool {
+	switch e {
+	case ansi.CUP, ansi.HVP:
+		// each parameter defaults to 1 when empty or 0, so "CSI ; 4 H" moves
+		// to column 4 of the first row
+		pt := ansi.Pt(1, 1)
+		ya, xa := a, []byte(nil)
+		if i := bytes.IndexByte(a, ';'); i >= 0 {
+			ya, xa = a[:i], a[i+1:]
+		}
+		if len(ya) > 0 {
+			y, _, err := ansi.DecodeNumber(ya)
+			if err != nil {
+				return false
+			} else if y > 0 {
+				pt.Y = y
+			}
+		}
+		if len(xa) > 0 {
+			x, _, err := ansi.DecodeNumber(xa)
+			if err != nil {
+				return false
+			} else if x > 0 {
+				pt.X = x
+			}
+		}
+		sc.cursorTo(pt)
+
+	case ansi.CUU, ansi.CUD:
+		// stop at the scrolling margins when starting within them
+		top, bottom := sc.margins()
+		y := sc.Cursor.Y
+		if !sc.Cursor.processEscape(e, a, sc.clamp) {
+			return false
+		}
+		if top <= y && y <= bottom {
+			if sc.Cursor.Y < top {
+				sc.Cursor.Y = top
+			} else if sc.Cursor.Y > bottom {
+				sc.Cursor.Y = bottom
+			}
+		}
+
+	case ansi.CHT:
+		sc.tab(countArg(a))
+	case ansi.CBT:
+		sc.tab(-countArg(a))
+
+	case ansi.TBC:
+		switch string(a) {
+		case "", "0":
+			sc.setTabStop(sc.Cursor.X, false)
+		case "3":
+			sc.tabs = append(sc.tabs[:0], make([]bool, sc.Rect.Dx())...)
+		}
+
+	case ansi.DECSC:
+		sc.saveCursor(true)
+	case ansi.DECRC:
+		sc.saveCursor(false)
+
+	case ansi.DECSTBM:
+		sc.setMargins(a)
+
+	default:
+		return false
+	}
+	return true
+}
+
+// countArg decodes a count argument, which defaults to 1 if missing or zero.
+func countArg(a []byte) int {
+	if n, _, err := ansi.DecodeNumber(a); err == nil && n > 0 {
+		return n
+	}
+	return 1
+}
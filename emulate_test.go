@@ -0,0 +1,150 @@
+package anansi_test
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jcorbin/anansi"
+	"github.com/jcorbin/anansi/ansi"
+)
+
+func TestScreen_emulation(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		size  image.Point
+		in    string
+		lines []string
+		cur   ansi.Point
+	}{
+		{"tabs", image.Pt(20, 2),
+			"a\tb\tc\r\n\x1b[3gx\tz",
+			[]string{"a       b       c", "x                  z"},
+			ansi.Pt(20, 2)},
+
+		{"set tab", image.Pt(20, 1),
+			"\x1b[1;4H\x1bH\r\tx\x1b[D\x1b[g\r\tyy\x1b[2Zz",
+			[]string{"z  x    yy"},
+			ansi.Pt(2, 1)},
+
+		{"CHT", image.Pt(20, 1),
+			"\x1b[2Ia",
+			[]string{"                a"},
+			ansi.Pt(18, 1)},
+
+		{"backspace", image.Pt(5, 1),
+			"abc\b\bX\b\b\b\bY",
+			[]string{"YXc"},
+			ansi.Pt(2, 1)},
+
+		{"pending wrap", image.Pt(3, 2),
+			"abc",
+			[]string{"abc", ""},
+			ansi.Pt(3, 1)},
+
+		{"pending wrap then wrap", image.Pt(3, 2),
+			"abc\x1b[1md",
+			[]string{"abc", "d"},
+			ansi.Pt(2, 2)},
+
+		{"pending wrap cleared by CR", image.Pt(3, 2),
+			"abc\rd",
+			[]string{"dbc", ""},
+			ansi.Pt(2, 1)},
+
+		{"last cell doesn't scroll", image.Pt(3, 2),
+			"abc\r\ndef",
+			[]string{"abc", "def"},
+			ansi.Pt(3, 2)},
+
+		{"no autowrap", image.Pt(3, 2),
+			"\x1b[?7labcdef",
+			[]string{"abf", ""},
+			ansi.Pt(3, 1)},
+
+		{"DECSC/DECRC", image.Pt(5, 2),
+			"ab\x1b7\x1b[2;4H\x1b(0q\x1b8c\x1b[2;5Hq",
+			[]string{"abc", "   ─q"},
+			ansi.Pt(5, 2)},
+
+		{"DECSC pending wrap", image.Pt(3, 2),
+			"abc\x1b7\x1b[2;1Hz\x1b8X",
+			[]string{"abc", "X"},
+			ansi.Pt(2, 2)},
+
+		{"scrolling region", image.Pt(3, 4),
+			"a\r\nb\r\nc\r\nd\x1b[2;3r\x1b[3;1Hx\ny\n",
+			[]string{"a", " y", "", "d"},
+			ansi.Pt(3, 3)},
+
+		{"reverse index", image.Pt(3, 4),
+			"a\r\nb\r\nc\r\nd\x1b[2;3r\x1b[2;1H\x1bMx",
+			[]string{"a", "x", "b", "d"},
+			ansi.Pt(2, 2)},
+
+		{"origin mode", image.Pt(3, 4),
+			"\x1b[2;3r\x1b[?6h\x1b[1;2Hx\x1b[9;1Hy",
+			[]string{"", " x", "y"},
+			ansi.Pt(2, 3)},
+
+		{"CUD stops at bottom margin", image.Pt(3, 4),
+			"\x1b[1;2r\x1b[9By\x1b[4;1H\x1b[9Az",
+			[]string{"z", "y"},
+			ansi.Pt(2, 1)},
+
+		{"CUP empty row defaults to 1", image.Pt(5, 3),
+			"\x1b[3;3H\x1b[;4Hx",
+			[]string{"   x", "", ""},
+			ansi.Pt(5, 1)},
+
+		{"CUP empty column defaults to 1", image.Pt(5, 5),
+			"\x1b[1;3H\x1b[5;Hx",
+			[]string{"", "", "", "", "x"},
+			ansi.Pt(2, 5)},
+
+		{"HVP zeros default to 1", image.Pt(3, 2),
+			"\x1b[2;3H\x1b[0;0fx",
+			[]string{"x", ""},
+			ansi.Pt(2, 1)},
+
+		{"ED defaults to erasing below, from the cursor", image.Pt(3, 3),
+			"abc\r\ndef\r\nghi\x1b[2;2H\x1b[J",
+			[]string{"abc", "d"},
+			ansi.Pt(2, 2)},
+
+		{"ED 1 erases above, through the cursor", image.Pt(3, 3),
+			"abc\r\ndef\r\nghi\x1b[2;2H\x1b[1J",
+			[]string{"", "  f", "ghi"},
+			ansi.Pt(2, 2)},
+
+		{"EL defaults to erasing right, from the cursor", image.Pt(3, 2),
+			"abc\r\ndef\x1b[1;2H\x1b[K",
+			[]string{"a", "def"},
+			ansi.Pt(2, 1)},
+
+		{"EL 1 erases left, through the cursor", image.Pt(3, 1),
+			"abc\x1b[1;2H\x1b[1K",
+			[]string{"  c"},
+			ansi.Pt(2, 1)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var vs anansi.VirtualScreen
+			vs.Resize(tc.size)
+			vs.WriteString(tc.in)
+			lines := strings.Split(gridString(vs.Grid), "")
+			var got []string
+			for y := 0; y < tc.size.Y; y++ {
+				line := strings.Join(lines[y*tc.size.X:(y+1)*tc.size.X], "")
+				got = append(got, strings.TrimRight(line, " "))
+			}
+			expected := append([]string(nil), tc.lines...)
+			for len(expected) < tc.size.Y {
+				expected = append(expected, "")
+			}
+			assert.Equal(t, expected, got, "expected screen lines")
+			assert.Equal(t, tc.cur, vs.Cursor.Point, "expected cursor point")
+		})
+	}
+}
@@ -155,7 +155,7 @@ func (in *Input) decodeRune() (rune, bool) {
 	r, n := utf8.DecodeRune(in.buf.Bytes())
 	if !in.ateof {
 		switch r {
-		case 0x90, 0x9B, 0x9D, 0x9E, 0x9F: // DCS, CSI, OSC, PM, APC
+		case 0x90, 0x98, 0x9B, 0x9D, 0x9E, 0x9F: // DCS, SOS, CSI, OSC, PM, APC
 			return 0, false
 		case 0x1B: // ESC
 			if p := in.buf.Bytes(); len(p) == cap(p) && !in.ateof {
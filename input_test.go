@@ -56,6 +56,18 @@ func TestInput_ReadMore(t *testing.T) {
 				{10, "helloworld"},
 			},
 		},
+
+		{
+			name: "ignored sequences",
+			steps: []write{
+				{time.Millisecond, "\x1b[3?1mx"},
+				{time.Millisecond, "\x1b[1<2mX"},
+			},
+			expected: []read{
+				{7, "x"},
+				{7, "X"},
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			var wg sync.WaitGroup
@@ -49,6 +49,14 @@ func (mode *Mode) AddModePair(set, reset ansi.Seq) {
 	mode.Reset = append(reset.AppendTo(b[:0]), mode.Reset...)
 }
 
+// AddModeString appends a set string to the Set buffer, and prepends a reset
+// string to the Reset buffer; useful for control strings from terminfo, see
+// TermCaps.
+func (mode *Mode) AddModeString(set, reset string) {
+	mode.Set = append(mode.Set, set...)
+	mode.Reset = append([]byte(reset), mode.Reset...)
+}
+
 // AddModeSeq appends one or more ansi sequences to the set buffer and
 // prepends them to the reset buffer.
 func (mode *Mode) AddModeSeq(seqs ...ansi.Seq) {
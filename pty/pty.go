@@ -0,0 +1,140 @@
+// Package pty supports hosting programs on pseudo-terminals, e.g. to build a
+// terminal multiplexer or debug wrapper; see Terminal for a child process
+// whose output is emulated into an anansi screen.
+package pty
+
+import (
+	"errors"
+	"image"
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/jcorbin/anansi"
+)
+
+var (
+	errUnsupported = errors.New("pseudo-terminals are not supported on this platform")
+	errClosed      = errors.New("pty closed")
+	errStarted     = errors.New("pty already started")
+)
+
+// Pty is a pseudo-terminal pair: the Slave end is the terminal that a child
+// process reads and writes as its stdio, while the Master end is read and
+// written by the host to see its output and send it input.
+type Pty struct {
+	Master *os.File
+	Slave  *os.File // nil after Start
+}
+
+// Open opens a new pseudo-terminal pair.
+func Open() (*Pty, error) {
+	ptm, pts, err := open()
+	if err != nil {
+		return nil, err
+	}
+	return &Pty{Master: ptm, Slave: pts}, nil
+}
+
+// Start opens a new pseudo-terminal of the given size (a zero size is left
+// for the platform to default), and starts the given command on it; see
+// Pty.Start.
+func Start(cmd *exec.Cmd, size image.Point) (*Pty, error) {
+	pt, err := Open()
+	if err != nil {
+		return nil, err
+	}
+	if size != image.ZP {
+		err = pt.SetSize(size)
+	}
+	if err == nil {
+		err = pt.Start(cmd)
+	}
+	if err != nil {
+		_ = pt.Close()
+		return nil, err
+	}
+	return pt, nil
+}
+
+// Start starts the given command with its standard input, output and error
+// attached to the slave terminal (unless already set), in a new session
+// with the slave as its controlling terminal. The parent's copy of the
+// slave is then closed, so that Read returns io.EOF once the child and any of
+// its descendants have exited.
+//
+// NOTE the controlling terminal is set from the child's stdin, so if
+// cmd.Stdin is not nil, it must be the slave.
+func (pt *Pty) Start(cmd *exec.Cmd) error {
+	if pt.Slave == nil {
+		return errStarted
+	}
+	if cmd.Stdin == nil {
+		cmd.Stdin = pt.Slave
+	}
+	if cmd.Stdout == nil {
+		cmd.Stdout = pt.Slave
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = pt.Slave
+	}
+	if err := setCtty(cmd); err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	err := pt.Slave.Close()
+	pt.Slave = nil
+	return err
+}
+
+// Read reads the child's output from the master; the EIO error that Linux
+// returns once the slave has been closed by all processes is translated to
+// io.EOF.
+func (pt *Pty) Read(p []byte) (int, error) {
+	n, err := pt.Master.Read(p)
+	if pe, ok := err.(*os.PathError); ok && pe.Err == syscall.EIO {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Write writes input for the child to the master.
+func (pt *Pty) Write(p []byte) (int, error) { return pt.Master.Write(p) }
+
+// Close closes both ends of the pty.
+func (pt *Pty) Close() (err error) {
+	if pt.Slave != nil {
+		err = pt.Slave.Close()
+		pt.Slave = nil
+	}
+	if pt.Master != nil {
+		if cerr := pt.Master.Close(); err == nil {
+			err = cerr
+		}
+		pt.Master = nil
+	}
+	return err
+}
+
+// SetSize sets the pty's window size in character cells, which causes the
+// kernel to send SIGWINCH to its foreground process group.
+func (pt *Pty) SetSize(size image.Point) error {
+	if pt.Master == nil {
+		return errClosed
+	}
+	return setSize(pt.Master, size)
+}
+
+// SyncSize sets the pty's window size to the given terminal's current size
+// (see anansi.Attr.Size), returning it; call it initially, and then whenever
+// the terminal receives SIGWINCH.
+func (pt *Pty) SyncSize(at anansi.Attr) (image.Point, error) {
+	size, err := at.Size()
+	if err == nil {
+		err = pt.SetSize(size)
+	}
+	return size, err
+}
@@ -0,0 +1,42 @@
+// +build darwin
+
+package pty
+
+import (
+	"bytes"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+func open() (ptm, pts *os.File, err error) {
+	ptm, err = os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		if err != nil {
+			ptm.Close()
+		}
+	}()
+
+	if err := fileIoctl(ptm, syscall.TIOCPTYGRANT, 0); err != nil {
+		return nil, nil, err
+	}
+	if err := fileIoctl(ptm, syscall.TIOCPTYUNLK, 0); err != nil {
+		return nil, nil, err
+	}
+	var name [128]byte
+	if err := fileIoctl(ptm, syscall.TIOCPTYGNAME, uintptr(unsafe.Pointer(&name[0]))); err != nil {
+		return nil, nil, err
+	}
+	if i := bytes.IndexByte(name[:], 0); i >= 0 {
+		pts, err = os.OpenFile(string(name[:i]), os.O_RDWR|syscall.O_NOCTTY, 0)
+	} else {
+		err = syscall.ENAMETOOLONG
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return ptm, pts, nil
+}
@@ -0,0 +1,13 @@
+// +build !linux,!darwin
+
+package pty
+
+import (
+	"image"
+	"os"
+	"os/exec"
+)
+
+func open() (ptm, pts *os.File, err error)       { return nil, nil, errUnsupported }
+func setCtty(cmd *exec.Cmd) error                { return errUnsupported }
+func setSize(f *os.File, size image.Point) error { return errUnsupported }
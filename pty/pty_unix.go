@@ -0,0 +1,50 @@
+// +build linux darwin
+
+package pty
+
+import (
+	"image"
+	"os"
+	"os/exec"
+	"syscall"
+	"unsafe"
+)
+
+func setCtty(cmd *exec.Cmd) error {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	cmd.SysProcAttr.Ctty = 0
+	return nil
+}
+
+func setSize(f *os.File, size image.Point) error {
+	ws := struct {
+		rows    uint16
+		cols    uint16
+		xpixels uint16
+		ypixels uint16
+	}{rows: uint16(size.Y), cols: uint16(size.X)}
+	return fileIoctl(f, syscall.TIOCSWINSZ, uintptr(unsafe.Pointer(&ws)))
+}
+
+// fileIoctl performs an ioctl on the given file, without putting it into
+// blocking mode as calling Fd() would.
+func fileIoctl(f *os.File, req, arg uintptr) error {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var errno syscall.Errno
+	if err := rc.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, req, arg)
+	}); err != nil {
+		return err
+	}
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package pty
+
+import (
+	"image"
+	"io"
+	"os/exec"
+	"sync"
+
+	"github.com/jcorbin/anansi"
+	"github.com/jcorbin/anansi/ansi"
+)
+
+// Terminal hosts a child process on a pseudo-terminal, emulating its output
+// into a screen. A background goroutine reads the child's output and
+// processes it into Screen under the Terminal's lock; any code that reads
+// Screen, or calls Mode, while the child runs must hold the lock too.
+type Terminal struct {
+	sync.Mutex
+	Cmd    *exec.Cmd
+	Pty    *Pty
+	Screen anansi.Screen
+
+	// Updated receives a (non-blocking) notification after each chunk of
+	// child output has been written into Screen.
+	Updated chan struct{}
+
+	buf    anansi.Buffer
+	modes  map[ansi.Mode]bool
+	done   chan struct{}
+	closed bool
+	err    error
+}
+
+// StartTerminal starts the given command on a new pseudo-terminal of the
+// given size, and starts reading its output into a screen of the same size.
+func StartTerminal(cmd *exec.Cmd, size image.Point) (*Terminal, error) {
+	pt, err := Start(cmd, size)
+	if err != nil {
+		return nil, err
+	}
+	t := &Terminal{
+		Cmd:     cmd,
+		Pty:     pt,
+		Updated: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	t.Screen.Resize(size)
+	go t.readLoop()
+	return t, nil
+}
+
+func (t *Terminal) readLoop() {
+	defer close(t.done)
+	var buf [4096]byte
+	for {
+		n, err := t.Pty.Read(buf[:])
+		if n > 0 {
+			t.Lock()
+			_, _ = t.buf.Write(buf[:n])
+			t.buf.Process(t)
+			t.buf.Discard()
+			t.Unlock()
+			select {
+			case t.Updated <- struct{}{}:
+			default:
+			}
+		}
+		if err != nil {
+			t.Lock()
+			if err != io.EOF && !t.closed {
+				t.err = err
+			}
+			t.Unlock()
+			return
+		}
+	}
+}
+
+// ProcessANSI tracks any modes set or reset by the child, and passes all
+// output on to Screen; see Mode.
+func (t *Terminal) ProcessANSI(e ansi.Escape, a []byte) {
+	switch e {
+	case ansi.SM, ansi.RM:
+		var tmp [4]ansi.Mode
+		modes, _ := ansi.DecodeModes(a, tmp[:0])
+		if len(modes) > 0 && t.modes == nil {
+			t.modes = make(map[ansi.Mode]bool, len(modes))
+		}
+		for _, mode := range modes {
+			t.modes[mode] = e == ansi.SM
+		}
+	}
+	t.Screen.ProcessANSI(e, a)
+}
+
+// Mode returns true if the given mode has been set by the child, or if it
+// is set by default (e.g. ShowCursor and ModeAutoWrap) and has not been
+// reset by the child.
+func (t *Terminal) Mode(mode ansi.Mode) bool {
+	if set, ok := t.modes[mode]; ok {
+		return set
+	}
+	switch mode {
+	case ansi.ShowCursor, ansi.ModeAutoWrap:
+		return true
+	}
+	return false
+}
+
+// Write sends input to the child.
+func (t *Terminal) Write(p []byte) (int, error) { return t.Pty.Write(p) }
+
+// Resize resizes both the screen and the pty, so that the child receives
+// SIGWINCH; returns any pty error.
+func (t *Terminal) Resize(size image.Point) error {
+	t.Lock()
+	t.Screen.Resize(size)
+	t.Unlock()
+	return t.Pty.SetSize(size)
+}
+
+// SyncSize resizes to match the given terminal's current size (see
+// anansi.Attr.Size); call it initially, and then whenever the terminal
+// receives SIGWINCH.
+func (t *Terminal) SyncSize(at anansi.Attr) error {
+	size, err := at.Size()
+	if err == nil {
+		err = t.Resize(size)
+	}
+	return err
+}
+
+// Done returns a channel that is closed once all of the child's output has
+// been read, i.e. after it (and any descendants holding the pty) has exited.
+func (t *Terminal) Done() <-chan struct{} { return t.done }
+
+// Wait waits for all child output to be read, and for the child to exit,
+// then closes the pty; returns the first read, wait or close error.
+func (t *Terminal) Wait() error {
+	<-t.done
+	err := t.err
+	if werr := t.Cmd.Wait(); err == nil {
+		err = werr
+	}
+	if cerr := t.closeMaster(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Close closes the pty, which hangs up the child, and then waits for it as
+// in Wait.
+func (t *Terminal) Close() error {
+	err := t.closeMaster()
+	if werr := t.Wait(); err == nil {
+		err = werr
+	}
+	return err
+}
+
+func (t *Terminal) closeMaster() error {
+	t.Lock()
+	defer t.Unlock()
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	return t.Pty.Master.Close()
+}
@@ -0,0 +1,40 @@
+package pty_test
+
+import (
+	"image"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi/ansi"
+	"github.com/jcorbin/anansi/pty"
+)
+
+func TestTerminal(t *testing.T) {
+	cmd := exec.Command("sh", "-c", `stty size; printf 'hello\033[2;3Hworld\033[?2004;25l\033[?2004h'`)
+	term, err := pty.StartTerminal(cmd, image.Pt(20, 4))
+	if err != nil {
+		t.Skipf("unable to start terminal: %v", err)
+	}
+	require.NoError(t, term.Wait())
+
+	term.Lock()
+	defer term.Unlock()
+	line := func(y int) string {
+		var rs []rune
+		for x := 1; x <= 20; x++ {
+			i, _ := term.Screen.CellOffset(ansi.Pt(x, y))
+			if r := term.Screen.Rune[i]; r != 0 {
+				rs = append(rs, r)
+			}
+		}
+		return string(rs)
+	}
+	assert.Equal(t, "4 20", line(1))
+	assert.Equal(t, "heworld", line(2))
+	assert.True(t, term.Mode(ansi.ModeBracketedPaste), "expected bracketed paste set")
+	assert.False(t, term.Mode(ansi.ShowCursor), "expected cursor hidden")
+	assert.True(t, term.Mode(ansi.ModeAutoWrap), "expected default autowrap")
+}
@@ -0,0 +1,327 @@
+package anansi
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jcorbin/anansi/ansi"
+)
+
+const (
+	defaultQueryTimeout = 250 * time.Millisecond
+	queryPollInterval   = 5 * time.Millisecond
+)
+
+var (
+	errNoReply        = errors.New("no reply from terminal")
+	errQueryNoFile    = errors.New("anansi.Term.Query: no input File set")
+	errQueryNoOutput  = errors.New("anansi.Term.Query: no output File set")
+	errInvalidSetting = errors.New("terminal reported invalid setting request")
+	errMeasureWrapped = errors.New("measured string wrapped onto the next line")
+)
+
+// IsNoReply returns true if the error was due to a terminal query timing out
+// without receiving a reply, e.g. because the terminal doesn't support it.
+func IsNoReply(err error) bool {
+	return err == errNoReply
+}
+
+// ReplyMatcher recognizes a terminal reply amongst decoded input.
+type ReplyMatcher func(e ansi.Escape, a []byte) bool
+
+// Query writes the given request control sequence to the terminal, and then
+// waits for a reply recognized by the given matcher; see Input.AwaitReply.
+// A zero timeout defaults to 250 milliseconds.
+//
+// The terminal should be in raw mode (see Attr.SetRaw), otherwise any reply
+// may be held in the line buffer, and echoed back to the screen.
+func (term *Term) Query(req ansi.Seq, timeout time.Duration, match ReplyMatcher) (ansi.Escape, []byte, error) {
+	if timeout == 0 {
+		timeout = defaultQueryTimeout
+	}
+	if err := term.Request(req); err != nil {
+		return 0, nil, err
+	}
+	return term.Input.AwaitReply(timeout, match)
+}
+
+// Request writes one or more request control sequences to the terminal all at
+// once; the caller should then collect replies with Input.AwaitReply. This
+// allows batching several queries into one round trip.
+func (term *Term) Request(reqs ...ansi.Seq) error {
+	if term.Output.File == nil {
+		return errQueryNoOutput
+	}
+	var buf Buffer
+	buf.WriteSeq(reqs...)
+	return term.Flush(&buf)
+}
+
+// QuerySetting asks the terminal to report the current state of a setting
+// using DECRQSS (see ansi.QuerySetting), returning it as the control sequence
+// that would restore it, e.g. ansi.SGR and its argument for setting "m". A
+// zero timeout defaults to 250 milliseconds.
+func (term *Term) QuerySetting(setting string, timeout time.Duration) (ansi.Escape, []byte, error) {
+	_, a, err := term.Query(ansi.QuerySetting(setting), timeout, ansi.IsSettingReply)
+	if err != nil {
+		return 0, nil, err
+	}
+	valid, e, a, err := ansi.DecodeSettingReply(a)
+	if err == nil && !valid {
+		err = errInvalidSetting
+	}
+	return e, a, err
+}
+
+// QueryTermcap asks the terminal for the values of one or more of its
+// terminfo capabilities using XTGETTCAP (see ansi.QueryTermcap). This allows
+// probing capabilities that the terminal itself knows about, even over ssh
+// without a local terminfo entry for it.
+//
+// A separate query is sent for each name, and replies are collected until all
+// names have been answered, or the timeout elapses (a zero timeout defaults to
+// 250 milliseconds). The returned map holds only those capabilities that the
+// terminal reported as known; boolean capabilities have an empty value. An
+// error for which IsNoReply() is true is returned only if no replies were
+// received at all.
+func (term *Term) QueryTermcap(timeout time.Duration, names ...string) (map[string]string, error) {
+	if timeout == 0 {
+		timeout = defaultQueryTimeout
+	}
+	reqs := make([]ansi.Seq, len(names))
+	for i, name := range names {
+		reqs[i] = ansi.QueryTermcap(name)
+	}
+	if err := term.Request(reqs...); err != nil {
+		return nil, err
+	}
+
+	caps := make(map[string]string, len(names))
+	deadline := time.Now().Add(timeout)
+	for n := 0; n < len(names); {
+		_, a, err := term.Input.AwaitReply(time.Until(deadline), ansi.IsTermcapReply)
+		if IsNoReply(err) && n > 0 {
+			break
+		} else if err != nil {
+			return caps, err
+		}
+		valid, ns, vs, err := ansi.DecodeTermcapReply(a)
+		if err != nil {
+			n++
+			continue
+		}
+		if len(ns) == 0 {
+			n++ // e.g. "DCS 0 + r ST" without naming the unknown capability
+		}
+		for i, name := range ns {
+			n++
+			if valid {
+				caps[name] = vs[i]
+			}
+		}
+	}
+	return caps, nil
+}
+
+// QueryModes asks the terminal to report the state of one or more modes using
+// DECRQM (see ansi.Mode.Request), so that applications may check which modes a
+// terminal supports.
+//
+// All requests are sent at once, and then replies are collected until all
+// modes have been reported, or the timeout elapses (a zero timeout defaults to
+// 250 milliseconds). Modes that the terminal doesn't report are absent from
+// the returned map; terminals that don't implement DECRQM at all won't reply,
+// in which case an error for which IsNoReply() is true is returned.
+func (term *Term) QueryModes(timeout time.Duration, modes ...ansi.Mode) (map[ansi.Mode]ansi.ModeState, error) {
+	if timeout == 0 {
+		timeout = defaultQueryTimeout
+	}
+	reqs := make([]ansi.Seq, len(modes))
+	for i, mode := range modes {
+		reqs[i] = mode.Request()
+	}
+	if err := term.Request(reqs...); err != nil {
+		return nil, err
+	}
+
+	states := make(map[ansi.Mode]ansi.ModeState, len(modes))
+	deadline := time.Now().Add(timeout)
+	for n := 0; n < len(modes); n++ {
+		_, a, err := term.Input.AwaitReply(time.Until(deadline), ansi.IsModeReport)
+		if IsNoReply(err) && n > 0 {
+			break
+		} else if err != nil {
+			return states, err
+		}
+		if mode, state, err := ansi.DecodeModeReport(a); err == nil {
+			states[mode] = state
+		}
+	}
+	return states, nil
+}
+
+// AwaitReply reads input until a reply recognized by the given matcher has
+// been read, returning its escape identifier and a copy of its argument bytes.
+// Any other input read while waiting remains buffered for later Decode()ing.
+// If no reply arrives before the timeout elapses, an error is returned for
+// which IsNoReply() is true.
+func (in *Input) AwaitReply(timeout time.Duration, match ReplyMatcher) (ansi.Escape, []byte, error) {
+	if in.File == nil {
+		return 0, nil, errQueryNoFile
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		if e, a, ok := in.DecodeReply(match); ok {
+			return e, a, nil
+		}
+		remain := time.Until(deadline)
+		if remain <= 0 {
+			return 0, nil, errNoReply
+		}
+		n, err := in.ReadAny()
+		if err != nil {
+			return 0, nil, err
+		}
+		if n == 0 {
+			if remain > queryPollInterval {
+				remain = queryPollInterval
+			}
+			time.Sleep(remain)
+		}
+	}
+}
+
+// DecodeReply scans all currently buffered input for the first complete
+// escape sequence recognized by the given matcher, removing it from the
+// buffer; all other buffered input is left in place, to be processed normally
+// by Decode. The returned argument bytes are a copy, and remain valid.
+func (in *Input) DecodeReply(match ReplyMatcher) (ansi.Escape, []byte, bool) {
+	p := in.buf.Bytes()
+	for i := 0; i < len(p); {
+		e, a, n := ansi.DecodeEscape(p[i:])
+		if e == 0 {
+			i += n
+			_, m := utf8.DecodeRune(p[i:])
+			i += m
+			continue
+		}
+		if match(e, a) {
+			a = append([]byte(nil), a...)
+			copy(p[i:], p[i+n:])
+			in.buf.Truncate(len(p) - n)
+			return e, a, true
+		}
+		i += n
+	}
+	return 0, nil, false
+}
+
+// DeviceAttributes holds a terminal's self identification, as reported by
+// Term.QueryDeviceAttributes; any part that the terminal didn't report is
+// left empty.
+type DeviceAttributes struct {
+	Primary   []int  // DA1: conformance level, followed by supported extensions
+	Secondary []int  // DA2: terminal type, firmware version, and options
+	Version   string // XTVERSION: name and version, e.g. "XTerm(388)"
+}
+
+// QueryDeviceAttributes asks the terminal to identify itself using XTVERSION,
+// DA2, and DA1 queries. Since practically every terminal answers DA1, and
+// replies arrive in order, it is sent last: once its reply arrives, no other
+// replies are awaited. An error for which IsNoReply() is true is returned only
+// if no replies were received at all before the timeout (which defaults to 250
+// milliseconds if zero).
+func (term *Term) QueryDeviceAttributes(timeout time.Duration) (da DeviceAttributes, err error) {
+	if timeout == 0 {
+		timeout = defaultQueryTimeout
+	}
+	if err := term.Request(
+		ansi.QueryVersion(),
+		ansi.QuerySecondaryDA(),
+		ansi.QueryPrimaryDA(),
+	); err != nil {
+		return da, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for n := 0; da.Primary == nil; n++ {
+		e, a, err := term.Input.AwaitReply(time.Until(deadline), func(e ansi.Escape, a []byte) bool {
+			return ansi.IsPrimaryDAReply(e, a) ||
+				ansi.IsSecondaryDAReply(e, a) ||
+				ansi.IsVersionReply(e, a)
+		})
+		if IsNoReply(err) && n > 0 {
+			break
+		} else if err != nil {
+			return da, err
+		}
+		switch {
+		case ansi.IsVersionReply(e, a):
+			da.Version, _ = ansi.DecodeVersionReply(a)
+		case ansi.IsSecondaryDAReply(e, a):
+			da.Secondary, _ = ansi.DecodeDeviceAttributes(a)
+		default:
+			if da.Primary, _ = ansi.DecodeDeviceAttributes(a); da.Primary == nil {
+				da.Primary = []int{}
+			}
+		}
+	}
+	return da, nil
+}
+
+// QueryCursorPosition asks the terminal to report the cursor position using a
+// DSR 6 query; a zero timeout defaults to 250 milliseconds.
+func (term *Term) QueryCursorPosition(timeout time.Duration) (ansi.Point, error) {
+	_, a, err := term.Query(ansi.QueryCursorPosition(), timeout, ansi.IsCursorPositionReply)
+	if err != nil {
+		return ansi.ZP, err
+	}
+	return ansi.DecodeCursorPosition(a)
+}
+
+// MeasureWidth writes the given string at the cursor, and asks the terminal
+// where that left the cursor, returning how many columns the terminal
+// advanced. This reveals how the terminal actually renders ambiguous or wide
+// characters (e.g. emoji), which may differ from what any width table
+// predicts.
+//
+// The cursor is saved and restored around the measurement (DECSC and DECRC),
+// and the string then erased (EL 0); since that erases through the end of the
+// line, the cursor should be at the end of the user's output (or on the
+// alternate screen). An error is returned if the string wraps onto the next
+// line, as from too close to the right margin.
+//
+// The terminal should be in raw mode (see Attr.SetRaw); a zero timeout
+// defaults to 250 milliseconds.
+func (term *Term) MeasureWidth(s string, timeout time.Duration) (int, error) {
+	var buf Buffer
+	buf.WriteESC(ansi.DECSC)
+	if err := term.Flush(&buf); err != nil {
+		return 0, err
+	}
+	width := 0
+	start, err := term.QueryCursorPosition(timeout)
+	if err == nil {
+		buf.WriteString(s)
+		err = term.Flush(&buf)
+	}
+	if err == nil {
+		var end ansi.Point
+		if end, err = term.QueryCursorPosition(timeout); err == nil {
+			width = end.X - start.X
+			if end.Y != start.Y {
+				err = errMeasureWrapped
+			}
+		}
+	}
+	buf.WriteESC(ansi.DECRC)
+	buf.WriteSeq(ansi.EL.With())
+	if ferr := term.Flush(&buf); err == nil {
+		err = ferr
+	}
+	if err != nil {
+		return 0, err
+	}
+	return width, nil
+}
@@ -0,0 +1,180 @@
+package anansi_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi"
+	"github.com/jcorbin/anansi/ansi"
+)
+
+func TestTerm_QuerySetting(t *testing.T) {
+	term, done := queryResponder(t, map[string]string{
+		"\x1bP$qm\x1b\\": "\x1bP1$r0;1;31m\x1b\\",
+		"\x1bP$qr\x1b\\": "\x1bP0$r\x1b\\",
+	})
+	defer done()
+
+	e, a, err := term.QuerySetting("m", 0)
+	require.NoError(t, err)
+	assert.Equal(t, ansi.SGR, e)
+	attr, _, err := ansi.DecodeSGR(a)
+	require.NoError(t, err)
+	assert.Equal(t, ansi.SGRAttrClear|ansi.SGRAttrBold|ansi.SGRRed.FG(), attr)
+
+	_, _, err = term.QuerySetting("r", 0)
+	assert.Error(t, err, "expected invalid setting error")
+}
+
+func TestTerm_QueryTermcap(t *testing.T) {
+	term, done := queryResponder(t, map[string]string{
+		termcapReq("TN"):     "\x1bP1+r544E=6B69747479\x1b\\",
+		termcapReq("RGB"):    "\x1bP1+r524742\x1b\\",
+		termcapReq("nope"):   "\x1bP0+r6E6F7065\x1b\\",
+		termcapReq("colors"): "\x1bP1+r636F6C6F7273=323536\x1b\\",
+	})
+	defer done()
+
+	caps, err := term.QueryTermcap(0, "TN", "RGB", "nope", "colors")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"TN":     "kitty",
+		"RGB":    "",
+		"colors": "256",
+	}, caps)
+}
+
+func TestTerm_QueryModes(t *testing.T) {
+	term, done := queryResponder(t, map[string]string{
+		"\x1b[?1049$p": "\x1b[?1049;2$y",
+		"\x1b[?2026$p": "\x1b[?2026;0$y",
+		"\x1b[4$p":     "\x1b[4;1$y",
+	})
+	defer done()
+
+	states, err := term.QueryModes(0, ansi.ModeAlternateScreen, ansi.ModeSynchronizedOutput, ansi.ModeInsert)
+	require.NoError(t, err)
+	assert.Equal(t, map[ansi.Mode]ansi.ModeState{
+		ansi.ModeAlternateScreen:    ansi.ModeReset,
+		ansi.ModeSynchronizedOutput: ansi.ModeUnknown,
+		ansi.ModeInsert:             ansi.ModeSet,
+	}, states)
+}
+
+func TestTerm_QueryDeviceAttributes(t *testing.T) {
+	// all queries are sent at once, and replies must arrive in order
+	term, done := queryResponder(t, map[string]string{
+		"\x1b[>0q\x1b[>c\x1b[c": "" +
+			"\x1bP>|XTerm(388)\x1b\\" +
+			"\x1b[>41;388;0c" +
+			"\x1b[?64;1;2;6;9;15;18;21;22c",
+	})
+	defer done()
+
+	da, err := term.QueryDeviceAttributes(0)
+	require.NoError(t, err)
+	assert.Equal(t, anansi.DeviceAttributes{
+		Primary:   []int{64, 1, 2, 6, 9, 15, 18, 21, 22},
+		Secondary: []int{41, 388, 0},
+		Version:   "XTerm(388)",
+	}, da)
+}
+
+func TestTerm_QueryDeviceAttributes_da1Only(t *testing.T) {
+	term, done := queryResponder(t, map[string]string{
+		"\x1b[c": "\x1b[?1;2c",
+	})
+	defer done()
+
+	// the DA1 reply ends collection, long before the timeout
+	start := time.Now()
+	da, err := term.QueryDeviceAttributes(10 * time.Second)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, anansi.DeviceAttributes{Primary: []int{1, 2}}, da)
+}
+
+func TestTerm_QueryCursorPosition(t *testing.T) {
+	term, done := queryResponder(t, map[string]string{
+		"\x1b[6n": "\x1b[5;3R",
+	})
+	defer done()
+
+	pt, err := term.QueryCursorPosition(0)
+	require.NoError(t, err)
+	assert.Equal(t, ansi.Pt(3, 5), pt)
+}
+
+func TestTerm_MeasureWidth(t *testing.T) {
+	// the cursor is saved before the first query, and the string written
+	// before the second
+	term, done := queryResponder(t, map[string]string{
+		"\x1b7\x1b[6n":        "\x1b[5;3R",
+		"\U0001F600\x1b[6n":   "\x1b[5;5R",
+		"\u4E2D\u4E2D\x1b[6n": "\x1b[6;2R",
+	})
+	defer done()
+
+	width, err := term.MeasureWidth("\U0001F600", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, width)
+
+	_, err = term.MeasureWidth("\u4E2D\u4E2D", 0)
+	assert.Error(t, err, "expected wrapping error")
+}
+
+// queryResponder returns a Term attached to pipes, whose output is answered
+// by writing the given reply after any request; the returned function must be
+// called to stop responding and clean up.
+func queryResponder(t *testing.T, replies map[string]string) (*anansi.Term, func()) {
+	inR, inW, err := blockingPipe()
+	require.NoError(t, err)
+	outR, outW, err := blockingPipe()
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var (
+			buf [4096]byte
+			out bytes.Buffer
+		)
+		for {
+			n, err := outR.Read(buf[:])
+			out.Write(buf[:n])
+			s := out.String()
+			for req, reply := range replies {
+				for strings.Contains(s, req) {
+					_, _ = io.WriteString(inW, reply)
+					s = strings.Replace(s, req, "", 1)
+				}
+			}
+			out.Reset()
+			out.WriteString(s)
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return anansi.NewTerm(inR, outW), func() {
+		outW.Close()
+		wg.Wait()
+		for _, f := range []*os.File{inR, inW, outR} {
+			f.Close()
+		}
+	}
+}
+
+func termcapReq(name string) string {
+	return string(ansi.QueryTermcap(name).AppendTo(nil))
+}
@@ -22,6 +22,10 @@ type TermScreen struct {
 type ScreenDiffer struct {
 	UserCursor Cursor
 
+	// Caps, if not nil, provides the strings used to show and hide the
+	// cursor, rather than DECTCEM; TermScreen.Enter sets it from Term.Caps.
+	Caps *TermCaps
+
 	VirtualScreen
 	Real Screen
 }
@@ -120,7 +124,7 @@ func (sc *ScreenDiffer) WriteTo(w io.Writer) (n int64, err error) {
 
 	// perform (full or differential) update
 	var m int
-	m, state = sc.VirtualScreen.update(aw, state)
+	m, state = sc.VirtualScreen.update(aw, state, sc.Caps)
 	return int64(m), err
 }
 
@@ -192,17 +196,29 @@ func (vsc *VirtualScreen) process() {
 	vsc.buf.Discard()
 }
 
-// Enter calls SizeToTerm.
-func (tsc *TermScreen) Enter(term *Term) error { return tsc.SizeToTerm(term) }
+// Enter sets Caps from the terminal (see Term.Caps), and calls SizeToTerm.
+func (tsc *TermScreen) Enter(term *Term) error {
+	caps := term.Caps()
+	tsc.Caps = &caps
+	return tsc.SizeToTerm(term)
+}
 
 // Exit Reset()s all virtual state, and restores real terminal graphics and
-// cursor state.
+// cursor state, using the terminal's sgr0 and cnorm strings (see Term.Caps).
 func (tsc *TermScreen) Exit(term *Term) error {
 	// discard all virtual state...
 	tsc.Reset()
 	// ...and restore real cursor state
-	n := tsc.buf.WriteSGR(tsc.Real.Cursor.MergeSGR(0))
-	n += tsc.buf.WriteSeq(tsc.Real.Cursor.Show())
+	caps := term.Caps()
+	var n int
+	if tsc.Real.Cursor.MergeSGR(0) != 0 {
+		m, _ := tsc.buf.WriteString(caps.SGR0)
+		n += m
+	}
+	if tsc.Real.Cursor.Show().ID() != 0 {
+		m, _ := tsc.buf.WriteString(caps.ShowCursor)
+		n += m
+	}
 	if n > 0 {
 		return term.Flush(&tsc.buf)
 	}
@@ -210,9 +226,17 @@ func (tsc *TermScreen) Exit(term *Term) error {
 }
 
 // SizeToTerm invalidates and resizes the screen to match the passed terminal's
-// current size.
+// current size; if the size ioctl fails, it falls back to querying the
+// terminal (see Term.QuerySize).
 func (tsc *TermScreen) SizeToTerm(term *Term) error {
 	sz, err := term.Size()
+	if err != nil {
+		var ts TermSize
+		if ts, err = term.QuerySize(0); err == nil && ts.Cells == image.ZP {
+			err = errNoReply
+		}
+		sz = ts.Cells
+	}
 	if err == nil {
 		tsc.Invalidate()
 		tsc.Resize(sz)
@@ -13,6 +13,7 @@ import (
 
 	"github.com/jcorbin/anansi"
 	"github.com/jcorbin/anansi/ansi"
+	"github.com/jcorbin/anansi/terminfo"
 	anansitest "github.com/jcorbin/anansi/test"
 )
 
@@ -149,6 +150,29 @@ func TestScreen_steps(t *testing.T) {
 	}
 }
 
+func TestScreen_caps(t *testing.T) {
+	ti, err := terminfo.GetBuiltin("linux")
+	require.NoError(t, err)
+	caps := anansi.ResolveCaps(ti)
+
+	var out bytes.Buffer
+	var sc anansi.ScreenDiffer
+	sc.Caps = &caps
+	sc.Resize(image.Pt(10, 10))
+	sc.UserCursor.Point = ansi.Pt(2, 3)
+	sc.UserCursor.Visible = true
+	_, err = sc.WriteTo(&out)
+	require.NoError(t, err)
+	assert.Equal(t, "\x1b[?25l\x1b[?1c\x1b[2J\x1b[3;2H\x1b[0m\x1b[?25h\x1b[?0c", out.String(),
+		"expected linux civis and cnorm")
+
+	out.Reset()
+	sc.UserCursor.Visible = false
+	_, err = sc.WriteTo(&out)
+	require.NoError(t, err)
+	assert.Equal(t, "\x1b[?25l\x1b[?1c", out.String(), "expected linux civis")
+}
+
 func TestScreen_blobs(t *testing.T) {
 	for _, tc := range []struct {
 		name  string
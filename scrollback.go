@@ -0,0 +1,147 @@
+package anansi
+
+import (
+	"strings"
+
+	"github.com/jcorbin/anansi/ansi"
+)
+
+// Scrollback is a bounded ring of lines that have scrolled off the top of a
+// Screen; attach one to Screen.Scrollback to collect them.
+type Scrollback struct {
+	// Limit is the maximum number of lines kept; once reached, the oldest
+	// line is dropped for each new one. A zero Limit keeps no lines.
+	Limit int
+
+	lines []ScrollbackLine
+	head  int
+}
+
+// ScrollbackLine is a line of scrollback history.
+type ScrollbackLine struct {
+	Rune []rune
+	Attr []ansi.SGRAttr
+
+	// Wrapped is true if the line was soft-wrapped into the next one, rather
+	// than ended by a line feed.
+	Wrapped bool
+}
+
+// String returns the line's runes, with zero runes rendered as spaces and any
+// trailing ones trimmed.
+func (line ScrollbackLine) String() string {
+	var sb strings.Builder
+	for _, r := range line.Rune {
+		if r == 0 {
+			r = ' '
+		}
+		sb.WriteRune(r)
+	}
+	return strings.TrimRight(sb.String(), " ")
+}
+
+// Len returns the number of lines in the scrollback.
+func (sb *Scrollback) Len() int { return len(sb.lines) }
+
+// Line returns the i-th line of scrollback history, counting from the oldest
+// (0) to the most recent (Len()-1). The returned line shares its data with the
+// scrollback, and is only valid until the next Push.
+func (sb *Scrollback) Line(i int) ScrollbackLine {
+	if i < 0 || i >= len(sb.lines) {
+		return ScrollbackLine{}
+	}
+	return sb.lines[(sb.head+i)%len(sb.lines)]
+}
+
+// Push copies the given line data into the scrollback, dropping the oldest
+// line if Limit has been reached.
+func (sb *Scrollback) Push(rs []rune, as []ansi.SGRAttr, wrapped bool) {
+	if sb.Limit <= 0 {
+		return
+	}
+	if n := len(sb.lines); n > sb.Limit || (n < sb.Limit && sb.head != 0) {
+		// Limit has changed; linearize the ring, keeping the most recent lines
+		lines := make([]ScrollbackLine, 0, sb.Limit)
+		i := 0
+		if n > sb.Limit {
+			i = n - sb.Limit
+		}
+		for ; i < n; i++ {
+			lines = append(lines, sb.Line(i))
+		}
+		sb.lines, sb.head = lines, 0
+	}
+	var line *ScrollbackLine
+	if len(sb.lines) < sb.Limit {
+		sb.lines = append(sb.lines, ScrollbackLine{})
+		line = &sb.lines[len(sb.lines)-1]
+	} else {
+		line = &sb.lines[sb.head]
+		sb.head = (sb.head + 1) % len(sb.lines)
+	}
+	line.Rune = append(line.Rune[:0], rs...)
+	line.Attr = append(line.Attr[:0], as...)
+	line.Wrapped = wrapped
+}
+
+// Clear discards all scrollback lines.
+func (sb *Scrollback) Clear() {
+	sb.lines = sb.lines[:0]
+	sb.head = 0
+}
+
+// Search searches backwards through history for the given string, starting
+// with the line before the given index (pass Len() to search from the most
+// recent line). Returns the line index and rune column of the match, or -1, -1
+// if not found. Matches do not span lines, even wrapped ones.
+func (sb *Scrollback) Search(s string, before int) (line, col int) {
+	if before > len(sb.lines) {
+		before = len(sb.lines)
+	}
+	for i := before - 1; i >= 0; i-- {
+		str := sb.Line(i).String()
+		if j := strings.Index(str, s); j >= 0 {
+			return i, len([]rune(str[:j]))
+		}
+	}
+	return -1, -1
+}
+
+// ScrollbackView resizes the given view grid to the screen's size, and fills
+// it with the screen content as if scrolled back by offset lines: the last
+// offset lines of history, followed by the top of the screen grid. The offset
+// is clamped to the available history; returns the effective offset.
+func (sc *Screen) ScrollbackView(offset int, view *Grid) int {
+	size := sc.Bounds().Size()
+	if n := sc.Scrollback.len(); offset > n {
+		offset = n
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	view.Resize(size)
+	view.Clear()
+	n := sc.Scrollback.len()
+	for y := 0; y < size.Y; y++ {
+		vi, _ := view.CellOffset(ansi.Pt(1, 1+y))
+		vrs := view.Rune[vi : vi+size.X]
+		vas := view.Attr[vi : vi+size.X]
+		if y < offset {
+			line := sc.Scrollback.Line(n - offset + y)
+			copy(vas, line.Attr)
+			copy(vrs, line.Rune)
+		} else {
+			si, _ := sc.CellOffset(ansi.Pt(sc.Rect.Min.X, sc.Rect.Min.Y+y-offset))
+			copy(vas, sc.Attr[si:si+size.X])
+			copy(vrs, sc.Rune[si:si+size.X])
+		}
+	}
+	return offset
+}
+
+func (sb *Scrollback) len() int {
+	if sb == nil {
+		return 0
+	}
+	return len(sb.lines)
+}
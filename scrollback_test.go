@@ -0,0 +1,88 @@
+package anansi_test
+
+import (
+	"fmt"
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jcorbin/anansi"
+)
+
+func scrollbackLines(sb *anansi.Scrollback) (lines []string) {
+	for i := 0; i < sb.Len(); i++ {
+		line := sb.Line(i)
+		s := line.String()
+		if line.Wrapped {
+			s += "\\"
+		}
+		lines = append(lines, s)
+	}
+	return lines
+}
+
+func TestScrollback(t *testing.T) {
+	var vs anansi.VirtualScreen
+	vs.Scrollback = &anansi.Scrollback{Limit: 4}
+	vs.Resize(image.Pt(5, 3))
+
+	vs.WriteString("a\r\nb\r\nc\r\nd")
+	assert.Equal(t, []string{"a"}, scrollbackLines(vs.Scrollback))
+	assert.Equal(t, "b    c    d    ", gridString(vs.Grid))
+
+	vs.WriteString("\r\nlonger\r\nx\r\ny")
+	assert.Equal(t, []string{"b", "c", "d", "longe\\"}, scrollbackLines(vs.Scrollback))
+	assert.Equal(t, "r    x    y    ", gridString(vs.Grid))
+
+	// full screen clears don't scroll into history, but ED 3 clears it
+	vs.WriteString("\x1b[2J")
+	assert.Equal(t, 4, vs.Scrollback.Len())
+	vs.WriteString("\x1b[3J")
+	assert.Equal(t, 0, vs.Scrollback.Len())
+}
+
+func TestScrollback_view(t *testing.T) {
+	var vs anansi.VirtualScreen
+	vs.Scrollback = &anansi.Scrollback{Limit: 100}
+	vs.Resize(image.Pt(4, 2))
+	for i := 1; i <= 5; i++ {
+		if i > 1 {
+			vs.WriteString("\r\n")
+		}
+		fmt.Fprintf(&vs, "l%d", i)
+	}
+	assert.Equal(t, []string{"l1", "l2", "l3"}, scrollbackLines(vs.Scrollback))
+
+	var view anansi.Grid
+	for _, tc := range []struct {
+		offset, effective int
+		expected          string
+	}{
+		{0, 0, "l4  l5  "},
+		{1, 1, "l3  l4  "},
+		{3, 3, "l1  l2  "},
+		{9, 3, "l1  l2  "},
+	} {
+		assert.Equal(t, tc.effective, vs.ScrollbackView(tc.offset, &view), "offset %v", tc.offset)
+		assert.Equal(t, tc.expected, gridString(view), "offset %v", tc.offset)
+	}
+
+	line, col := vs.Scrollback.Search("2", vs.Scrollback.Len())
+	assert.Equal(t, []int{1, 1}, []int{line, col})
+	line, col = vs.Scrollback.Search("l", 2)
+	assert.Equal(t, []int{1, 0}, []int{line, col})
+	line, col = vs.Scrollback.Search("l5", vs.Scrollback.Len())
+	assert.Equal(t, []int{-1, -1}, []int{line, col})
+}
+
+func gridString(g anansi.Grid) string {
+	rs := make([]rune, len(g.Rune))
+	for i, r := range g.Rune {
+		if r == 0 {
+			r = ' '
+		}
+		rs[i] = r
+	}
+	return string(rs)
+}
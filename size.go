@@ -0,0 +1,102 @@
+package anansi
+
+import (
+	"image"
+	"time"
+
+	"github.com/jcorbin/anansi/ansi"
+)
+
+// TermSize describes the size of a terminal's text area, both in character
+// cells and in pixels; any zero component is unknown.
+type TermSize struct {
+	Cells  image.Point // text area size in character cells
+	Pixels image.Point // text area size in pixels
+	Cell   image.Point // size of a single character cell in pixels
+}
+
+// complete fills in any unknown pixel dimension that may be derived from the
+// others.
+func (ts *TermSize) complete() {
+	if ts.Cells.X == 0 || ts.Cells.Y == 0 {
+		return
+	}
+	if ts.Cell == image.ZP && ts.Pixels.X > 0 && ts.Pixels.Y > 0 {
+		ts.Cell = image.Pt(ts.Pixels.X/ts.Cells.X, ts.Pixels.Y/ts.Cells.Y)
+	} else if ts.Pixels == image.ZP && ts.Cell.X > 0 && ts.Cell.Y > 0 {
+		ts.Pixels = image.Pt(ts.Cell.X*ts.Cells.X, ts.Cell.Y*ts.Cells.Y)
+	}
+}
+
+// QuerySize asks the terminal to report the size of its text area in cells
+// and pixels, and the size of a cell in pixels, using XTWINOPS 18, 14, and 16
+// queries. This works even when the ioctl used by Attr.Size fails (e.g. when
+// output isn't a tty), and supplements pixel dimensions that many multiplexers
+// leave zero. All queries are sent at once, and then replies are collected
+// until all have been received, or the timeout elapses. An error for which
+// IsNoReply() is true is returned only if no replies were received at all.
+//
+// The terminal should be in raw mode (see Attr.SetRaw).
+func (term *Term) QuerySize(timeout time.Duration) (ts TermSize, err error) {
+	if timeout == 0 {
+		timeout = defaultQueryTimeout
+	}
+	if term.Input.File == nil {
+		return ts, errQueryNoFile
+	}
+	reqs := []ansi.Seq{
+		ansi.QueryTextAreaChars(),
+		ansi.QueryTextAreaPixels(),
+		ansi.QueryCellPixels(),
+	}
+	if err := term.Request(reqs...); err != nil {
+		return ts, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for n := 0; n < len(reqs); n++ {
+		_, a, err := term.Input.AwaitReply(time.Until(deadline), ansi.IsWinOpsSizeReply)
+		if IsNoReply(err) && n > 0 {
+			break
+		} else if err != nil {
+			return ts, err
+		}
+		op, size, err := ansi.DecodeWinOpsSize(a)
+		if err != nil {
+			continue
+		}
+		switch op {
+		case ansi.WinOpTextAreaChars:
+			ts.Cells = size
+		case ansi.WinOpTextAreaPixels:
+			ts.Pixels = size
+		case ansi.WinOpCellPixels:
+			ts.Cell = size
+		}
+	}
+	ts.complete()
+	return ts, nil
+}
+
+// FullSize returns the terminal size as reported by Attr.WindowSize, falling
+// back to (or supplementing missing pixel dimensions with) QuerySize.
+func (term *Term) FullSize(timeout time.Duration) (TermSize, error) {
+	ts, err := term.WindowSize()
+	if err == nil && ts.Pixels != image.ZP {
+		return ts, nil
+	}
+	qts, qerr := term.QuerySize(timeout)
+	if err != nil {
+		return qts, qerr
+	}
+	if qerr == nil {
+		if ts.Pixels == image.ZP {
+			ts.Pixels = qts.Pixels
+		}
+		if ts.Cell == image.ZP {
+			ts.Cell = qts.Cell
+		}
+		ts.complete()
+	}
+	return ts, nil
+}
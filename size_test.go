@@ -0,0 +1,66 @@
+package anansi_test
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi"
+)
+
+func TestTermScreen_SizeToTerm_query(t *testing.T) {
+	inR, inW, err := blockingPipe()
+	require.NoError(t, err)
+	defer inR.Close()
+	defer inW.Close()
+	outR, outW, err := blockingPipe()
+	require.NoError(t, err)
+	defer outR.Close()
+
+	term := anansi.NewTerm(inR, outW)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var (
+			buf [256]byte
+			out bytes.Buffer
+		)
+		for {
+			n, err := outR.Read(buf[:])
+			out.Write(buf[:n])
+			if bytes.HasSuffix(out.Bytes(), []byte("\x1b[16t")) {
+				// no pixel size, only cell size and text area
+				_, _ = io.WriteString(inW, "\x1b[6;16;8t\x1b[8;24;80t")
+				out.Reset()
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	_, err = term.Size()
+	require.Error(t, err, "expected size ioctl to fail on a pipe")
+
+	ts, err := term.QuerySize(0)
+	require.NoError(t, err)
+	assert.Equal(t, anansi.TermSize{
+		Cells:  image.Pt(80, 24),
+		Pixels: image.Pt(640, 384),
+		Cell:   image.Pt(8, 16),
+	}, ts)
+
+	var tsc anansi.TermScreen
+	require.NoError(t, tsc.SizeToTerm(term))
+	assert.Equal(t, image.Pt(80, 24), tsc.Bounds().Size())
+
+	outW.Close()
+	wg.Wait()
+}
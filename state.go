@@ -35,6 +35,25 @@ type Cursor struct {
 type Screen struct {
 	Cursor Cursor
 	Grid
+
+	// Scrollback, if not nil, collects lines that scroll off the top of the
+	// screen during ProcessANSI.
+	Scrollback *Scrollback
+
+	wrapped []bool // per grid row, whether it soft-wrapped into the next
+
+	alt          bool   // whether the alternate screen buffer is active
+	other        Grid   // the inactive (primary or alternate) buffer
+	otherWrapped []bool // wrapped flags for other
+	saved        savedCursor
+	otherSaved   savedCursor // saved cursor for other
+	charsets     charsets
+
+	pendingWrap bool   // last column flag, see writeRune
+	noAutoWrap  bool   // DECAWM reset
+	origin      bool   // DECOM set
+	top, bottom int    // DECSTBM scrolling margins, if non-zero
+	tabs        []bool // tab stops by column, if changed from the default
 }
 
 // Full returns a shallow copy of the screen with the Grid restored to its full
@@ -76,12 +95,24 @@ func (sc Screen) String() string {
 func (sc *Screen) Clear() {
 	sc.Grid.Clear()
 	sc.Cursor = Cursor{}
+	sc.pendingWrap = false
+	clearWrapped(sc.wrapped)
 }
 
 // Resize the underlying Grid, and zero the cursor position if out of bounds.
 // Returns true only if the resize was a change, false if it was a no-op.
 func (sc *Screen) Resize(size image.Point) bool {
 	if sc.Grid.Resize(size) {
+		sc.pendingWrap = false
+		sc.top, sc.bottom = 0, 0
+		if !sc.IsSub() {
+			sc.resizeTabs(size.X)
+			sc.wrapped = resizeWrapped(sc.wrapped, size.Y)
+			if sc.other.Stride != 0 {
+				sc.other.Resize(size)
+				sc.otherWrapped = resizeWrapped(sc.otherWrapped, size.Y)
+			}
+		}
 		if !sc.Cursor.Point.In(sc.Bounds()) {
 			sc.Cursor.Point.Point = image.ZP
 		}
@@ -90,10 +121,18 @@ func (sc *Screen) Resize(size image.Point) bool {
 	return false
 }
 
+func resizeWrapped(wrapped []bool, n int) []bool {
+	if n <= len(wrapped) {
+		return wrapped[:n]
+	}
+	return append(wrapped, make([]bool, n-len(wrapped))...)
+}
+
 // Show returns the control sequence necessary to show the cursor if it is not
-// visible, the zero sequence otherwise.
+// visible, the zero sequence otherwise. This is always DECTCEM, since cursor
+// visibility is tracked by processing that mode; a ScreenDiffer with Caps set
+// writes the terminal's own cnorm string instead.
 func (cs *Cursor) Show() ansi.Seq {
-	// TODO terminfo
 	if !cs.visKnown || !cs.Visible {
 		cs.visKnown = true
 		cs.Visible = true
@@ -103,9 +142,8 @@ func (cs *Cursor) Show() ansi.Seq {
 }
 
 // Hide returns the control sequence necessary to hide the cursor if it is
-// visible, the zero sequence otherwise.
+// visible, the zero sequence otherwise; see Show.
 func (cs *Cursor) Hide() ansi.Seq {
-	// TODO terminfo
 	if !cs.visKnown || cs.Visible {
 		cs.visKnown = true
 		cs.Visible = false
@@ -208,35 +246,59 @@ func (sc *Screen) To(pt ansi.Point) {
 // the number of bytes written, and the updated cursor state.
 func (cs *Cursor) ApplyTo(w io.Writer, cur Cursor) (int, Cursor, error) {
 	return withAnsiCursorWriter(w, cur, func(aw ansiWriter, cur Cursor) (int, Cursor) {
-		return cs.applyTo(aw, cur)
+		return cs.applyTo(aw, cur, nil)
 	})
 }
 
-func (cs *Cursor) applyTo(aw ansiWriter, cur Cursor) (n int, _ Cursor) {
+func (cs *Cursor) applyTo(aw ansiWriter, cur Cursor, caps *TermCaps) (n int, _ Cursor) {
 	if cs.Visible && cs.Point.Valid() {
 		n += aw.WriteSeq(cur.To(cs.Point))
 		n += aw.WriteSGR(cur.MergeSGR(cs.Attr))
-		n += aw.WriteSeq(cur.Show())
+		n += cur.writeShow(aw, caps)
 	} else {
-		n += aw.WriteSeq(cur.Hide())
+		n += cur.writeHide(aw, caps)
 	}
 	return n, cur
 }
 
+// writeShow writes the string needed to show the cursor, if it is not visible:
+// the cnorm string from caps, or DECTCEM if caps is nil.
+func (cs *Cursor) writeShow(aw ansiWriter, caps *TermCaps) int {
+	seq := cs.Show()
+	if caps == nil || seq.ID() == 0 {
+		return aw.WriteSeq(seq)
+	}
+	n, _ := aw.WriteString(caps.ShowCursor)
+	return n
+}
+
+// writeHide writes the string needed to hide the cursor, if it is visible: the
+// civis string from caps, or DECTCEM if caps is nil.
+func (cs *Cursor) writeHide(aw ansiWriter, caps *TermCaps) int {
+	seq := cs.Hide()
+	if caps == nil || seq.ID() == 0 {
+		return aw.WriteSeq(seq)
+	}
+	n, _ := aw.WriteString(caps.HideCursor)
+	return n
+}
+
 // Update syncs screen state from the receiver against the given prior screen
 // state, generating writing any/all necessary ansi control sequences into the
 // given writer. Returns the number of bytes written and the final screen state,
 // which will now equal the receiver state.
 func (sc *Screen) Update(w io.Writer, prior Screen) (int, Screen, error) {
-	return withAnsiScreenWriter(w, prior, sc.update)
+	return withAnsiScreenWriter(w, prior, func(aw ansiWriter, prior Screen) (int, Screen) {
+		return sc.update(aw, prior, nil)
+	})
 }
 
-func (sc *Screen) update(aw ansiWriter, prior Screen) (int, Screen) {
+func (sc *Screen) update(aw ansiWriter, prior Screen, caps *TermCaps) (int, Screen) {
 	var n, m int
-	n += aw.WriteSeq(prior.Cursor.Hide())
+	n += prior.Cursor.writeHide(aw, caps)
 	m, prior = writeGrid(aw, sc.Grid, prior, NoopStyle)
 	n += m
-	m, prior.Cursor = sc.Cursor.applyTo(aw, prior.Cursor)
+	m, prior.Cursor = sc.Cursor.applyTo(aw, prior.Cursor, caps)
 	n += m
 	prior.Resize(sc.Grid.Bounds().Size())
 	copy(prior.Rune, sc.Rune)
@@ -276,10 +338,11 @@ func ptID(pt ansi.Point) ansi.Point { return pt }
 
 // processEscape implements cursor escape processing shared with ScreenState,
 // which passes a non-identity clamp function.
+// Returns false if the sequence isn't supported, or its arguments are invalid.
 func (cs *Cursor) processEscape(
 	e ansi.Escape, a []byte,
 	clamp func(pt ansi.Point) ansi.Point,
-) {
+) bool {
 	switch e {
 	case ansi.CUU, ansi.CUD, ansi.CUF, ansi.CUB: // relative cursor motion
 		b, _ := e.CSI()
@@ -287,7 +350,7 @@ func (cs *Cursor) processEscape(
 		if len(a) > 0 {
 			n, _, err := ansi.DecodeNumber(a)
 			if err != nil {
-				return
+				return false
 			}
 			d = d.Mul(n)
 		}
@@ -299,118 +362,140 @@ func (cs *Cursor) processEscape(
 			var err error
 			p, _, err = ansi.DecodePoint(a)
 			if err != nil {
-				return
+				return false
 			}
 		}
 		cs.Point = clamp(p)
 
 	case ansi.SGR:
-		if attr, _, err := ansi.DecodeSGR(a); err == nil {
-			cs.Attr = cs.Attr.Merge(attr)
+		attr, _, err := ansi.DecodeSGR(a)
+		if err != nil {
+			return false
 		}
+		cs.Attr = cs.Attr.Merge(attr)
 
-	case ansi.SM:
-		// TODO better mode decoding (follow SGRAttr's example, and mature the ansi.Mode type)
+	case ansi.SM, ansi.RM:
 		// TODO better mode processing: injected handler for ScreenState
-		if len(a) > 1 && a[0] == '?' {
-			n := 1
-			for n < len(a) {
-				mode, m, err := ansi.DecodeMode(true, a[n:])
-				n += m
-				if err != nil {
-					return
-				}
-				switch mode {
-				case ansi.ShowCursor: // TODO terminfo
-					cs.Visible = true
-				}
+		var tmp [8]ansi.Mode
+		// apply any modes decoded before an invalid parameter
+		modes, err := ansi.DecodeModes(a, tmp[:0])
+		handled := err == nil
+		for _, mode := range modes {
+			switch mode {
+			case ansi.ShowCursor: // DECTCEM; also used by common cnorm and civis strings
+				cs.Visible = e == ansi.SM
+			default:
+				handled = false
 			}
 		}
+		return handled
 
-	case ansi.RM:
-		if len(a) > 1 && a[0] == '?' {
-			n := 1
-			for n < len(a) {
-				mode, m, err := ansi.DecodeMode(true, a[n:])
-				n += m
-				if err != nil {
-					return
-				}
-				switch mode {
-				case ansi.ShowCursor: // TODO terminfo
-					cs.Visible = false
-				}
-			}
-		}
+	default:
+		return false
 	}
+	return true
 }
 
 // ProcessANSI updates screen state to reflect having written the given escape
 // value or rune to a terminal; in addition to CursorState.ProcessANSI semantics:
 //
 // Graphic runes update the virtual cell grid, using the current cursor SGR
-// attribute, at the current cursor point.
+// attribute, at the current cursor point. Writing into the last column sets a
+// pending wrap flag rather than advancing the cursor; under autowrap (DECAWM,
+// set by default) the next graphic rune then wraps onto the next line.
+//
+// Line feeds at the bottom margin scroll the scrolling region up, collecting
+// lines that scroll off the top of the screen into any Scrollback; erasing
+// the display, and scrolling the alternate screen, does not.
+//
+// Supported control runes: BEL (ignored), BS, HT, LF, VT, FF, CR, IND, NEL, HTS and RI.
 //
 // Supported escape sequences:
-//   - ED to erase display
+//   - ED to erase display; ED 3 erases the Scrollback
 //   - EL to erase line
+//   - SM and RM of ModeAlternateScreen, ModeAlternateBufferClear,
+//     ModeAlternateBuffer and ModeSaveCursor, with xterm semantics
+//   - SM and RM of ModeAutoWrap (DECAWM) and ModeOrigin (DECOM)
+//   - SCS to designate G0-G3 charsets; SI, SO, LS2 and LS3 to invoke them,
+//     and SS2 and SS3 to invoke G2 or G3 for the next graphic rune only; see
+//     ansi.Charset for supported charsets
+//   - DECSTBM to set scrolling margins, DECSC and DECRC to save and restore
+//     the cursor
+//   - CHT, CBT and TBC for tab stops
 //   - cursor movement sequences, as per CursorState.ProcessANSI, but clamped
-//     to the screen bounds
+//     to the screen bounds; CUP and HVP are relative to the scrolling region
+//     under origin mode
 //
 // Any errors decoding escape arguments are silenced, and the offending
-// escape sequence(s) ignored.
+// escape sequence(s) ignored; see Emulate to find such unsupported input.
 func (sc *Screen) ProcessANSI(e ansi.Escape, a []byte) {
+	sc.Emulate(e, a)
+}
+
+// Emulate processes the given escape value or rune as ProcessANSI does, but
+// returns false if it isn't supported: the escape sequence or control rune is
+// unknown, its arguments are invalid, or it sets or resets any unsupported
+// mode. Unsupported input is otherwise ignored, with the exception of partially
+// supported mode sequences, which still apply any supported modes.
+func (sc *Screen) Emulate(e ansi.Escape, a []byte) bool {
 	if sc.Cursor.Point.Point == image.ZP {
 		sc.Cursor.Point = ansi.Pt(1, 1)
 	}
+	// DECSC saves, and DECRC restores, any pending wrap
+	if e != ansi.SGR && e != ansi.DECSC && e != ansi.DECRC &&
+		!e.IsCharacterSetControl() && !unicode.IsGraphic(rune(e)) {
+		sc.pendingWrap = false
+	}
 	switch {
+	case sc.charsets.process(e, a):
+	case sc.processControl(e):
 	case e.IsEscape():
-		sc.processEscape(e, a)
-	case e == '\x0A': // LF
-		sc.linefeed()
-	case e == '\x0D': // CR
-		sc.Cursor.X = 1
-	// TODO anything for other control runes?
+		return sc.processEscape(e, a)
 	case unicode.IsGraphic(rune(e)):
-		br := sc.Bounds()
-		if i, ok := sc.Grid.CellOffset(sc.Cursor.Point); ok {
-			sc.Grid.Rune[i], sc.Grid.Attr[i] = rune(e), sc.Cursor.Attr
-		}
-		if sc.Cursor.X++; sc.Cursor.X >= br.Max.X {
-			sc.Cursor.X = br.Min.X
-			sc.linefeed()
-		}
+		sc.writeRune(sc.charsets.translate(rune(e)))
+	default:
+		return false
 	}
+	return true
 }
 
-func (sc *Screen) processEscape(e ansi.Escape, a []byte) {
+func (sc *Screen) processEscape(e ansi.Escape, a []byte) bool {
 	switch e {
 	case ansi.ED:
-		var val byte
+		var val byte = '0'
 		if len(a) == 1 {
 			val = a[0]
-		} else {
-			return
+		} else if len(a) > 1 {
+			return false
 		}
 		switch val {
 		case '0': // Erase from current position to bottom of screen inclusive
 			if i, ok := sc.CellOffset(sc.Cursor.Point); ok {
-				sc.clearRegion(i+1, len(sc.Rune))
+				sc.clearRegion(i, len(sc.Rune))
 			}
 		case '1': // Erase from top of screen to current position inclusive
 			if i, ok := sc.CellOffset(sc.Cursor.Point); ok {
 				sc.clearRegion(0, i+1)
 			}
 		case '2': // Erase entire screen (without moving the cursor)
-			sc.clearRegion(0, len(sc.Rune))
+			sc.clearGrid()
+		case '3': // Erase saved lines
+			if sc.Scrollback != nil {
+				sc.Scrollback.Clear()
+			}
+		default:
+			return false
 		}
 
+	case ansi.SM, ansi.RM:
+		return sc.processModes(e, a)
+
 	case ansi.EL:
-		var val byte
+		var val byte = '0'
 		if len(a) == 1 {
 			val = a[0]
-		} else {
-			return
+		} else if len(a) > 1 {
+			return false
 		}
 
 		lo := sc.Cursor.Point
@@ -425,7 +510,7 @@ func (sc *Screen) processEscape(e ansi.Escape, a []byte) {
 			lo.X = 1
 			hi.Y = sc.Cursor.Y
 		default:
-			return
+			return false
 		}
 
 		i, iok := sc.CellOffset(lo)
@@ -433,18 +518,16 @@ func (sc *Screen) processEscape(e ansi.Escape, a []byte) {
 		if iok && jok {
 			sc.clearRegion(i, j+1)
 		}
-
-		// case ansi.DECSTBM: TODO
-		// [12;24r Set scrolling region to lines 12 thru 24.  If a linefeed or an
-		//         INDex is received while on line 24, the former line 12 is
-		//         deleted and rows 13-24 move up.  If a RI (reverse Index) is
-		//         received while on line 12, a blank line is inserted there as
-		//         rows 12-13 move down.  All VT100 compatible terminals (except
-		//         GIGI) have this feature.
+		if val != '1' {
+			sc.setWrapped(sc.Cursor.Y, false)
+		}
 
 	default:
-		sc.Cursor.processEscape(e, a, sc.clamp)
+		if !sc.processCursorEscape(e, a) {
+			return sc.Cursor.processEscape(e, a, sc.clamp)
+		}
 	}
+	return true
 }
 
 func (sc *Screen) clearRegion(i, max int) {
@@ -454,24 +537,100 @@ func (sc *Screen) clearRegion(i, max int) {
 	}
 }
 
+// linefeed moves the cursor down a line, scrolling if it was at the bottom
+// margin.
 func (sc *Screen) linefeed() {
-	if sc.Cursor.Y+1 < sc.Bounds().Max.Y {
-		sc.Cursor.Y++
-	} else {
+	_, bottom := sc.margins()
+	if sc.Cursor.Y == bottom {
 		sc.scrollBy(1)
+	} else if sc.Cursor.Y+1 < sc.Bounds().Max.Y {
+		sc.Cursor.Y++
+	}
+}
+
+// reverseIndex moves the cursor up a line, scrolling down if it was at the top
+// margin.
+func (sc *Screen) reverseIndex() {
+	top, _ := sc.margins()
+	if sc.Cursor.Y == top {
+		sc.scrollBy(-1)
+	} else if sc.Cursor.Y > sc.Bounds().Min.Y {
+		sc.Cursor.Y--
 	}
 }
 
+// scrollBy scrolls the scrolling region up by n lines, or down if n is
+// negative. Lines scrolled off the top of the primary screen are collected
+// into any Scrollback.
 func (sc *Screen) scrollBy(n int) {
-	i, ok := sc.CellOffset(sc.Cursor.Point.Add(image.Pt(1, 2)))
-	if !ok {
+	top, bottom := sc.margins()
+	h := bottom - top + 1
+	if n > h {
+		n = h
+	} else if n < -h {
+		n = -h
+	}
+	if n == 0 || h <= 0 {
 		return
 	}
-	for j := copy(sc.Grid.Rune, sc.Grid.Rune[i:]); j < len(sc.Grid.Rune); j++ {
-		sc.Grid.Rune[j] = 0
+
+	full := sc.Grid.Full()
+	stride := full.Stride
+	rs := full.Rune[(top-1)*stride : bottom*stride]
+	as := full.Attr[(top-1)*stride : bottom*stride]
+	if len(sc.wrapped) < bottom {
+		sc.wrapped = resizeWrapped(sc.wrapped, full.Rect.Dy())
+	}
+	ws := sc.wrapped[top-1 : bottom]
+
+	if n > 0 {
+		if sc.Scrollback != nil && !sc.alt && top == 1 {
+			for y := 0; y < n; y++ {
+				i := y * stride
+				sc.Scrollback.Push(rs[i:i+stride], as[i:i+stride], ws[y])
+			}
+		}
+		i := n * stride
+		copy(rs, rs[i:])
+		copy(as, as[i:])
+		copy(ws, ws[n:])
+		clearCells(rs[len(rs)-i:], as[len(as)-i:])
+		clearWrapped(ws[h-n:])
+	} else {
+		n = -n
+		i := n * stride
+		copy(rs[i:], rs)
+		copy(as[i:], as)
+		copy(ws[n:], ws)
+		clearCells(rs[:i], as[:i])
+		clearWrapped(ws[:n])
+	}
+}
+
+func clearCells(rs []rune, as []ansi.SGRAttr) {
+	for i := range rs {
+		rs[i] = 0
+	}
+	for i := range as {
+		as[i] = 0
 	}
-	for j := copy(sc.Grid.Attr, sc.Grid.Attr[i:]); j < len(sc.Grid.Attr); j++ {
-		sc.Grid.Attr[j] = 0
+}
+
+func clearWrapped(ws []bool) {
+	for i := range ws {
+		ws[i] = false
+	}
+}
+
+// isWrapped returns true if the given (1-based) screen row soft-wrapped into
+// the next one.
+func (sc *Screen) isWrapped(y int) bool {
+	return y >= 1 && y <= len(sc.wrapped) && sc.wrapped[y-1]
+}
+
+func (sc *Screen) setWrapped(y int, wrapped bool) {
+	if y >= 1 && y <= len(sc.wrapped) {
+		sc.wrapped[y-1] = wrapped
 	}
 }
 
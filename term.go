@@ -5,17 +5,22 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+
+	"github.com/jcorbin/anansi/terminfo"
 )
 
 // OpenTerm opens the standard terminal, attached to the controlling terminal.
 // Prefers to existing os.Stdin and os.Stdout files if they're still attached,
-// opens /dev/tty otherwise.
+// opens /dev/tty otherwise. Terminfo is loaded for the TERM environment
+// variable if possible; if not, ANSI fallbacks are used (see ResolveCaps).
 func OpenTerm() (*Term, error) {
 	in, out, err := openTermFiles(os.Stdin, os.Stdout)
 	if err != nil {
 		return nil, err
 	}
-	return NewTerm(in, out), nil
+	term := NewTerm(in, out)
+	_ = term.LoadTerminfo("")
+	return term, nil
 }
 
 // openTermFiles opens /dev/tty if the given files are not terminals,
@@ -68,12 +73,17 @@ func NewTerm(in, out *os.File, cs ...Context) *Term {
 
 // Term combines a terminal file handle with attribute control and further
 // Context-ual state.
+//
+// Terminfo, if loaded (see LoadTerminfo), is used to resolve control strings
+// for common functions like the alternate screen; see Caps.
 type Term struct {
 	Attr
 	Mode
 	Input
 	Output
 
+	Terminfo *terminfo.Terminfo
+
 	under  bool // under any RunWith
 	active bool // under RunWith, not RunWithout
 	ctx    Context
@@ -174,7 +184,7 @@ func (term *Term) Suspend() error {
 		return term.RunWithout((*Term).Suspend)
 	}
 
-	cont := make(chan os.Signal)
+	cont := make(chan os.Signal, 1)
 	signal.Notify(cont, syscall.SIGCONT)
 	defer signal.Stop(cont)
 	log.Printf("suspending")
@@ -0,0 +1,84 @@
+package anansi
+
+import (
+	"errors"
+	"os"
+
+	"github.com/jcorbin/anansi/ansi"
+	"github.com/jcorbin/anansi/terminfo"
+)
+
+var errNoTermName = errors.New("no terminal name given, and TERM not set")
+
+// TermCaps holds control strings for common terminal functions, as resolved
+// from terminfo by ResolveCaps.
+type TermCaps struct {
+	EnterCA     string // smcup: enter the alternate screen (cursor addressing mode)
+	ExitCA      string // rmcup: exit the alternate screen
+	ShowCursor  string // cnorm: make the cursor normally visible
+	HideCursor  string // civis: make the cursor invisible
+	EnterKeypad string // smkx: enter keypad transmit (application) mode
+	ExitKeypad  string // rmkx: exit keypad transmit mode
+	SGR0        string // sgr0: reset all graphic rendition attributes
+}
+
+// ResolveCaps returns control strings from the given terminfo, falling back to
+// standard ANSI sequences for any absent capability (or for all of them if
+// ti is nil).
+func ResolveCaps(ti *terminfo.Terminfo) TermCaps {
+	str := func(c terminfo.StrCap, fallback ...interface{ AppendTo([]byte) []byte }) string {
+		if ti != nil {
+			if s, ok := ti.Str(c); ok {
+				return s
+			}
+		}
+		var b []byte
+		for _, seq := range fallback {
+			b = seq.AppendTo(b)
+		}
+		return string(b)
+	}
+	return TermCaps{
+		EnterCA:     str(terminfo.StrEnterCAMode, ansi.ModeAlternateScreen.Set()),
+		ExitCA:      str(terminfo.StrExitCAMode, ansi.ModeAlternateScreen.Reset()),
+		ShowCursor:  str(terminfo.StrCursorNormal, ansi.ShowCursor.Set()),
+		HideCursor:  str(terminfo.StrCursorInvisible, ansi.ShowCursor.Reset()),
+		EnterKeypad: str(terminfo.StrKeypadXmit, ansi.ModeCursorKeys.Set(), ansi.DECKPAM.With()),
+		ExitKeypad:  str(terminfo.StrKeypadLocal, ansi.ModeCursorKeys.Reset(), ansi.DECKPNM.With()),
+		SGR0:        str(terminfo.StrExitAttributeMode, ansi.SGRClear),
+	}
+}
+
+// LoadTerminfo loads terminfo for the named terminal, or for the TERM
+// environment variable if name is empty, setting term.Terminfo on success.
+func (term *Term) LoadTerminfo(name string) error {
+	if name == "" {
+		name = os.Getenv("TERM")
+	}
+	if name == "" {
+		return errNoTermName
+	}
+	ti, err := terminfo.Load(name)
+	if err != nil {
+		return err
+	}
+	term.Terminfo = ti
+	return nil
+}
+
+// Caps returns control strings resolved from term.Terminfo; see ResolveCaps.
+func (term *Term) Caps() TermCaps { return ResolveCaps(term.Terminfo) }
+
+// AddAlternateScreen adds the terminal's alternate screen enter/exit strings
+// to its Mode; see Mode.AddModeString.
+func (term *Term) AddAlternateScreen() {
+	caps := term.Caps()
+	term.AddModeString(caps.EnterCA, caps.ExitCA)
+}
+
+// AddKeypadMode adds the terminal's keypad transmit mode enter/exit strings to
+// its Mode; see Mode.AddModeString.
+func (term *Term) AddKeypadMode() {
+	caps := term.Caps()
+	term.AddModeString(caps.EnterKeypad, caps.ExitKeypad)
+}
@@ -0,0 +1,77 @@
+package anansi_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi"
+	"github.com/jcorbin/anansi/terminfo"
+)
+
+func TestResolveCaps(t *testing.T) {
+	ansiCaps := anansi.TermCaps{
+		EnterCA:     "\x1b[?1049h",
+		ExitCA:      "\x1b[?1049l",
+		ShowCursor:  "\x1b[?25h",
+		HideCursor:  "\x1b[?25l",
+		EnterKeypad: "\x1b[?1h\x1b=",
+		ExitKeypad:  "\x1b[?1l\x1b>",
+		SGR0:        "\x1b[0m",
+	}
+
+	for _, tc := range []struct {
+		name string
+		term string
+		caps anansi.TermCaps
+	}{
+		{"no terminfo", "", ansiCaps},
+		{"xterm", "xterm", anansi.TermCaps{
+			EnterCA:     "\x1b[?1049h\x1b[22;0;0t",
+			ExitCA:      "\x1b[?1049l\x1b[23;0;0t",
+			ShowCursor:  "\x1b[?12l\x1b[?25h",
+			HideCursor:  "\x1b[?25l",
+			EnterKeypad: "\x1b[?1h\x1b=",
+			ExitKeypad:  "\x1b[?1l\x1b>",
+			SGR0:        "\x1b(B\x1b[m",
+		}},
+		{"linux fallbacks", "linux", anansi.TermCaps{
+			EnterCA:     ansiCaps.EnterCA,
+			ExitCA:      ansiCaps.ExitCA,
+			ShowCursor:  "\x1b[?25h\x1b[?0c",
+			HideCursor:  "\x1b[?25l\x1b[?1c",
+			EnterKeypad: ansiCaps.EnterKeypad,
+			ExitKeypad:  ansiCaps.ExitKeypad,
+			SGR0:        "\x1b[m\x0f",
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var ti *terminfo.Terminfo
+			if tc.term != "" {
+				var err error
+				ti, err = terminfo.GetBuiltin(tc.term)
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.caps, anansi.ResolveCaps(ti))
+		})
+	}
+}
+
+func TestTerm_AddAlternateScreen(t *testing.T) {
+	var term anansi.Term
+	term.AddAlternateScreen()
+	term.AddKeypadMode()
+	assert.Equal(t, "\x1b[?1049h\x1b[?1h\x1b=", string(term.Mode.Set))
+	assert.Equal(t, "\x1b[?1l\x1b>\x1b[?1049l", string(term.Mode.Reset))
+
+	ti, err := terminfo.GetBuiltin("xterm")
+	require.NoError(t, err)
+	term = anansi.Term{Terminfo: ti}
+	term.AddAlternateScreen()
+	assert.Equal(t, "\x1b[?1049h\x1b[22;0;0t", string(term.Mode.Set))
+	assert.Equal(t, "\x1b[?1049l\x1b[23;0;0t", string(term.Mode.Reset))
+
+	require.NoError(t, term.LoadTerminfo("linux"))
+	assert.Equal(t, "linux", term.Terminfo.Name)
+}
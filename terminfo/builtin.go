@@ -6,21 +6,78 @@ import (
 	"strings"
 )
 
-// GetBuiltin returns a named builtin Terminfo.
+// GetBuiltin returns a named builtin Terminfo; if no builtin has the given
+// name, the first one registered with a compat partial name contained by it
+// is returned.
 func GetBuiltin(term string) (*Terminfo, error) {
+	ti, _, err := LocateBuiltin(term)
+	return ti, err
+}
+
+// LocateBuiltin gets a builtin Terminfo like GetBuiltin, but also reports which
+// builtin was used, and any compat partial name that matched.
+func LocateBuiltin(term string) (*Terminfo, Origin, error) {
 	if term == "" {
 		if defaultTerm == "" {
-			return nil, errors.New("no term name given, and no default defined")
+			return nil, Origin{}, errors.New("no term name given, and no default defined")
 		}
 		term = defaultTerm
 	}
 	if ti, def := builtins[term]; def {
-		return ti, nil
+		return ti, Origin{Builtin: ti.Name}, nil
 	}
 	for _, compat := range compatTable {
 		if strings.Contains(term, compat.partial) {
-			return compat.Terminfo, nil
+			return compat.Terminfo, Origin{Builtin: compat.Name, Partial: compat.partial}, nil
 		}
 	}
-	return nil, fmt.Errorf("unsupported TERM=%q", term)
+	return nil, Origin{}, fmt.Errorf("unsupported TERM=%q", term)
+}
+
+// registerBuiltin adds a builtin Terminfo under all of its names (other than
+// any trailing description), and adds any partial names to the compat table.
+func registerBuiltin(ti *Terminfo, partials ...string) {
+	ti.setKeysFuncs()
+	names := ti.Names
+	if len(names) > 1 {
+		names = names[:len(names)-1]
+	}
+	for _, name := range names {
+		builtins[name] = ti
+	}
+	for _, partial := range partials {
+		compatTable = append(compatTable, compatEntry{partial, ti})
+	}
+}
+
+// builtinBools returns a Bools slice with only the given capabilities set.
+func builtinBools(caps ...BoolCap) []bool {
+	bs := make([]bool, numBoolCaps)
+	for _, c := range caps {
+		bs[c] = true
+	}
+	return bs
+}
+
+// builtinNumbers returns a Numbers slice with only the given capabilities
+// present.
+func builtinNumbers(caps map[NumCap]int) []int {
+	ns := make([]int, numNumCaps)
+	for i := range ns {
+		ns[i] = capAbsent
+	}
+	for c, n := range caps {
+		ns[c] = n
+	}
+	return ns
+}
+
+// builtinStrings returns a Strings slice with only the given capabilities
+// present.
+func builtinStrings(caps map[StrCap]string) []string {
+	ss := make([]string, numStrCaps)
+	for c, s := range caps {
+		ss[c] = s
+	}
+	return ss
 }
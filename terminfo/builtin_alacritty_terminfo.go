@@ -0,0 +1,288 @@
+// Code generated by mkterminfo; DO NOT EDIT.
+
+package terminfo
+
+var alacritty = Terminfo{
+	Name:  "alacritty",
+	Names: []string{"alacritty", "alacritty terminal emulator"},
+	Bools: builtinBools(
+		BoolAutoRightMargin,
+		BoolEatNewlineGlitch,
+		BoolHasStatusLine,
+		BoolMoveInsertMode,
+		BoolMoveStandoutMode,
+		BoolPrtrSilent,
+		BoolNoPadChar,
+		BoolCanChange,
+		BoolBackColorErase,
+		BoolBackspacesWithBS,
+	),
+	Numbers: builtinNumbers(map[NumCap]int{
+		NumColumns:   80,
+		NumInitTabs:  8,
+		NumLines:     24,
+		NumMaxColors: 256,
+		NumMaxPairs:  65536,
+	}),
+	Strings: builtinStrings(map[StrCap]string{
+		StrBackTab:             "\x1b[Z",
+		StrBell:                "\a",
+		StrCarriageReturn:      "\r",
+		StrChangeScrollRegion:  "\x1b[%i%p1%d;%p2%dr",
+		StrClearAllTabs:        "\x1b[3g",
+		StrClearScreen:         "\x1b[H\x1b[2J",
+		StrClrEol:              "\x1b[K",
+		StrClrEos:              "\x1b[J",
+		StrColumnAddress:       "\x1b[%i%p1%dG",
+		StrCursorAddress:       "\x1b[%i%p1%d;%p2%dH",
+		StrCursorDown:          "\n",
+		StrCursorHome:          "\x1b[H",
+		StrCursorInvisible:     "\x1b[?25l",
+		StrCursorLeft:          "\b",
+		StrCursorNormal:        "\x1b[?12l\x1b[?25h",
+		StrCursorRight:         "\x1b[C",
+		StrCursorUp:            "\x1b[A",
+		StrCursorVisible:       "\x1b[?12;25h",
+		StrDeleteCharacter:     "\x1b[P",
+		StrDeleteLine:          "\x1b[M",
+		StrDisStatusLine:       "\x1b]2;\a",
+		StrEnterAltCharsetMode: "\x1b(0",
+		StrEnterBlinkMode:      "\x1b[5m",
+		StrEnterBoldMode:       "\x1b[1m",
+		StrEnterCAMode:         "\x1b[?1049h\x1b[22;0;0t",
+		StrEnterDimMode:        "\x1b[2m",
+		StrEnterInsertMode:     "\x1b[4h",
+		StrEnterSecureMode:     "\x1b[8m",
+		StrEnterReverseMode:    "\x1b[7m",
+		StrEnterStandoutMode:   "\x1b[7m",
+		StrEnterUnderlineMode:  "\x1b[4m",
+		StrEraseChars:          "\x1b[%p1%dX",
+		StrExitAltCharsetMode:  "\x1b(B",
+		StrExitAttributeMode:   "\x1b(B\x1b[m",
+		StrExitCAMode:          "\x1b[?1049l\x1b[23;0;0t",
+		StrExitInsertMode:      "\x1b[4l",
+		StrExitStandoutMode:    "\x1b[27m",
+		StrExitUnderlineMode:   "\x1b[24m",
+		StrFlashScreen:         "\x1b[?5h$<100/>\x1b[?5l",
+		StrFromStatusLine:      "\a",
+		StrInit2string:         "\x1b[!p\x1b[?3;4l\x1b[4l\x1b>",
+		StrInsertLine:          "\x1b[L",
+		StrKeyBackspace:        "\x7f",
+		StrKeyDc:               "\x1b[3~",
+		StrKeyDown:             "\x1bOB",
+		StrKeyF1:               "\x1bOP",
+		StrKeyF10:              "\x1b[21~",
+		StrKeyF2:               "\x1bOQ",
+		StrKeyF3:               "\x1bOR",
+		StrKeyF4:               "\x1bOS",
+		StrKeyF5:               "\x1b[15~",
+		StrKeyF6:               "\x1b[17~",
+		StrKeyF7:               "\x1b[18~",
+		StrKeyF8:               "\x1b[19~",
+		StrKeyF9:               "\x1b[20~",
+		StrKeyHome:             "\x1bOH",
+		StrKeyIc:               "\x1b[2~",
+		StrKeyLeft:             "\x1bOD",
+		StrKeyNpage:            "\x1b[6~",
+		StrKeyPpage:            "\x1b[5~",
+		StrKeyRight:            "\x1bOC",
+		StrKeySf:               "\x1b[1;2B",
+		StrKeySr:               "\x1b[1;2A",
+		StrKeyUp:               "\x1bOA",
+		StrKeypadLocal:         "\x1b[?1l\x1b>",
+		StrKeypadXmit:          "\x1b[?1h\x1b=",
+		StrMetaOff:             "\x1b[?1034l",
+		StrMetaOn:              "\x1b[?1034h",
+		StrParmDch:             "\x1b[%p1%dP",
+		StrParmDeleteLine:      "\x1b[%p1%dM",
+		StrParmDownCursor:      "\x1b[%p1%dB",
+		StrParmIch:             "\x1b[%p1%d@",
+		StrParmIndex:           "\x1b[%p1%dS",
+		StrParmInsertLine:      "\x1b[%p1%dL",
+		StrParmLeftCursor:      "\x1b[%p1%dD",
+		StrParmRightCursor:     "\x1b[%p1%dC",
+		StrParmRindex:          "\x1b[%p1%dT",
+		StrParmUpCursor:        "\x1b[%p1%dA",
+		StrPrintScreen:         "\x1b[i",
+		StrPrtrOff:             "\x1b[4i",
+		StrPrtrOn:              "\x1b[5i",
+		StrRepeatChar:          "%p1%c\x1b[%p2%{1}%-%db",
+		StrReset1string:        "\x1bc\x1b]104\a",
+		StrReset2string:        "\x1b[!p\x1b[?3;4l\x1b[4l\x1b>",
+		StrRestoreCursor:       "\x1b8",
+		StrRowAddress:          "\x1b[%i%p1%dd",
+		StrSaveCursor:          "\x1b7",
+		StrScrollForward:       "\n",
+		StrScrollReverse:       "\x1bM",
+		StrSetAttributes:       "%?%p9%t\x1b(0%e\x1b(B%;\x1b[0%?%p6%t;1%;%?%p5%t;2%;%?%p2%t;4%;%?%p1%p3%|%t;7%;%?%p4%t;5%;%?%p7%t;8%;m",
+		StrSetTab:              "\x1bH",
+		StrTab:                 "\t",
+		StrToStatusLine:        "\x1b]2;",
+		StrKeyB2:               "\x1bOE",
+		StrACSChars:            "``aaffggiijjkkllmmnnooppqqrrssttuuvvwwxxyyzz{{||}}~~",
+		StrKeyBtab:             "\x1b[Z",
+		StrEnterAmMode:         "\x1b[?7h",
+		StrExitAmMode:          "\x1b[?7l",
+		StrKeyEnd:              "\x1bOF",
+		StrKeyEnter:            "\x1bOM",
+		StrKeySdc:              "\x1b[3;2~",
+		StrKeySend:             "\x1b[1;2F",
+		StrKeyShome:            "\x1b[1;2H",
+		StrKeySic:              "\x1b[2;2~",
+		StrKeySleft:            "\x1b[1;2D",
+		StrKeySnext:            "\x1b[6;2~",
+		StrKeySprevious:        "\x1b[5;2~",
+		StrKeySright:           "\x1b[1;2C",
+		StrKeyF11:              "\x1b[23~",
+		StrKeyF12:              "\x1b[24~",
+		StrKeyF13:              "\x1b[1;2P",
+		StrKeyF14:              "\x1b[1;2Q",
+		StrKeyF15:              "\x1b[1;2R",
+		StrKeyF16:              "\x1b[1;2S",
+		StrKeyF17:              "\x1b[15;2~",
+		StrKeyF18:              "\x1b[17;2~",
+		StrKeyF19:              "\x1b[18;2~",
+		StrKeyF20:              "\x1b[19;2~",
+		StrKeyF21:              "\x1b[20;2~",
+		StrKeyF22:              "\x1b[21;2~",
+		StrKeyF23:              "\x1b[23;2~",
+		StrKeyF24:              "\x1b[24;2~",
+		StrKeyF25:              "\x1b[1;5P",
+		StrKeyF26:              "\x1b[1;5Q",
+		StrKeyF27:              "\x1b[1;5R",
+		StrKeyF28:              "\x1b[1;5S",
+		StrKeyF29:              "\x1b[15;5~",
+		StrKeyF30:              "\x1b[17;5~",
+		StrKeyF31:              "\x1b[18;5~",
+		StrKeyF32:              "\x1b[19;5~",
+		StrKeyF33:              "\x1b[20;5~",
+		StrKeyF34:              "\x1b[21;5~",
+		StrKeyF35:              "\x1b[23;5~",
+		StrKeyF36:              "\x1b[24;5~",
+		StrKeyF37:              "\x1b[1;6P",
+		StrKeyF38:              "\x1b[1;6Q",
+		StrKeyF39:              "\x1b[1;6R",
+		StrKeyF40:              "\x1b[1;6S",
+		StrKeyF41:              "\x1b[15;6~",
+		StrKeyF42:              "\x1b[17;6~",
+		StrKeyF43:              "\x1b[18;6~",
+		StrKeyF44:              "\x1b[19;6~",
+		StrKeyF45:              "\x1b[20;6~",
+		StrKeyF46:              "\x1b[21;6~",
+		StrKeyF47:              "\x1b[23;6~",
+		StrKeyF48:              "\x1b[24;6~",
+		StrKeyF49:              "\x1b[1;3P",
+		StrKeyF50:              "\x1b[1;3Q",
+		StrKeyF51:              "\x1b[1;3R",
+		StrKeyF52:              "\x1b[1;3S",
+		StrKeyF53:              "\x1b[15;3~",
+		StrKeyF54:              "\x1b[17;3~",
+		StrKeyF55:              "\x1b[18;3~",
+		StrKeyF56:              "\x1b[19;3~",
+		StrKeyF57:              "\x1b[20;3~",
+		StrKeyF58:              "\x1b[21;3~",
+		StrKeyF59:              "\x1b[23;3~",
+		StrKeyF60:              "\x1b[24;3~",
+		StrKeyF61:              "\x1b[1;4P",
+		StrKeyF62:              "\x1b[1;4Q",
+		StrKeyF63:              "\x1b[1;4R",
+		StrClrBol:              "\x1b[1K",
+		StrUser6:               "\x1b[%i%d;%dR",
+		StrUser7:               "\x1b[6n",
+		StrUser8:               "\x1b[?%[;0123456789]c",
+		StrUser9:               "\x1b[c",
+		StrOrigPair:            "\x1b[39;49m",
+		StrOrigColors:          "\x1b]104\a",
+		StrInitializeColor:     "\x1b]4;%p1%d;rgb:%p2%{255}%*%{1000}%/%2.2X/%p3%{255}%*%{1000}%/%2.2X/%p4%{255}%*%{1000}%/%2.2X\x1b\\",
+		StrEnterItalicsMode:    "\x1b[3m",
+		StrExitItalicsMode:     "\x1b[23m",
+		StrKeyMouse:            "\x1b[<",
+		StrSetAForeground:      "\x1b[%?%p1%{8}%<%t3%p1%d%e%p1%{16}%<%t9%p1%{8}%-%d%e38;5;%p1%d%;m",
+		StrSetABackground:      "\x1b[%?%p1%{8}%<%t4%p1%d%e%p1%{16}%<%t10%p1%{8}%-%d%e48;5;%p1%d%;m",
+		StrMemoryLock:          "\x1bl",
+		StrMemoryUnlock:        "\x1bm",
+	}),
+	ExtBools: map[string]bool{
+		"AX": true,
+		"XF": true,
+		"XT": true,
+	},
+	ExtStrings: map[string]string{
+		"BD":    "\x1b[?2004l",
+		"BE":    "\x1b[?2004h",
+		"Cr":    "\x1b]112\a",
+		"Cs":    "\x1b]12;%p1%s\a",
+		"E3":    "\x1b[3J",
+		"Ms":    "\x1b]52;%p1%s;%p2%s\a",
+		"PE":    "\x1b[201~",
+		"PS":    "\x1b[200~",
+		"Se":    "\x1b[0 q",
+		"Smulx": "\x1b[4:%p1%dm",
+		"Ss":    "\x1b[%p1%d q",
+		"TS":    "\x1b]2;",
+		"XM":    "\x1b[?1006;1000%?%p1%{1}%=%th%el%;",
+		"fd":    "\x1b[?1004l",
+		"fe":    "\x1b[?1004h",
+		"kDC3":  "\x1b[3;3~",
+		"kDC4":  "\x1b[3;4~",
+		"kDC5":  "\x1b[3;5~",
+		"kDC6":  "\x1b[3;6~",
+		"kDC7":  "\x1b[3;7~",
+		"kDN":   "\x1b[1;2B",
+		"kDN3":  "\x1b[1;3B",
+		"kDN4":  "\x1b[1;4B",
+		"kDN5":  "\x1b[1;5B",
+		"kDN6":  "\x1b[1;6B",
+		"kDN7":  "\x1b[1;7B",
+		"kEND3": "\x1b[1;3F",
+		"kEND4": "\x1b[1;4F",
+		"kEND5": "\x1b[1;5F",
+		"kEND6": "\x1b[1;6F",
+		"kEND7": "\x1b[1;7F",
+		"kHOM3": "\x1b[1;3H",
+		"kHOM4": "\x1b[1;4H",
+		"kHOM5": "\x1b[1;5H",
+		"kHOM6": "\x1b[1;6H",
+		"kHOM7": "\x1b[1;7H",
+		"kIC3":  "\x1b[2;3~",
+		"kIC4":  "\x1b[2;4~",
+		"kIC5":  "\x1b[2;5~",
+		"kIC6":  "\x1b[2;6~",
+		"kIC7":  "\x1b[2;7~",
+		"kLFT3": "\x1b[1;3D",
+		"kLFT4": "\x1b[1;4D",
+		"kLFT5": "\x1b[1;5D",
+		"kLFT6": "\x1b[1;6D",
+		"kLFT7": "\x1b[1;7D",
+		"kNXT3": "\x1b[6;3~",
+		"kNXT4": "\x1b[6;4~",
+		"kNXT5": "\x1b[6;5~",
+		"kNXT6": "\x1b[6;6~",
+		"kNXT7": "\x1b[6;7~",
+		"kPRV3": "\x1b[5;3~",
+		"kPRV4": "\x1b[5;4~",
+		"kPRV5": "\x1b[5;5~",
+		"kPRV6": "\x1b[5;6~",
+		"kPRV7": "\x1b[5;7~",
+		"kRIT3": "\x1b[1;3C",
+		"kRIT4": "\x1b[1;4C",
+		"kRIT5": "\x1b[1;5C",
+		"kRIT6": "\x1b[1;6C",
+		"kRIT7": "\x1b[1;7C",
+		"kUP":   "\x1b[1;2A",
+		"kUP3":  "\x1b[1;3A",
+		"kUP4":  "\x1b[1;4A",
+		"kUP5":  "\x1b[1;5A",
+		"kUP6":  "\x1b[1;6A",
+		"kUP7":  "\x1b[1;7A",
+		"kxIN":  "\x1b[I",
+		"kxOUT": "\x1b[O",
+		"rmxx":  "\x1b[29m",
+		"smxx":  "\x1b[9m",
+		"xm":    "\x1b[<%i%p3%d;%p1%d;%p2%d;%?%p4%tM%em%;",
+	},
+}
+
+func init() {
+	registerBuiltin(&alacritty, "alacritty")
+}
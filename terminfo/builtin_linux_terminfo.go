@@ -1,51 +1,145 @@
+// Code generated by mkterminfo; DO NOT EDIT.
+
 package terminfo
 
 var linux = Terminfo{
-	Name: "linux",
-	Keys: [maxKeys]string{
-		"",
-		"\x1b[[A",
-		"\x1b[[B",
-		"\x1b[[C",
-		"\x1b[[D",
-		"\x1b[[E",
-		"\x1b[17~",
-		"\x1b[18~",
-		"\x1b[19~",
-		"\x1b[20~",
-		"\x1b[21~",
-		"\x1b[23~",
-		"\x1b[24~",
-		"\x1b[2~",
-		"\x1b[3~",
-		"\x1b[1~",
-		"\x1b[4~",
-		"\x1b[5~",
-		"\x1b[6~",
-		"\x1b[A",
-		"\x1b[B",
-		"\x1b[D",
-		"\x1b[C",
+	Name:  "linux",
+	Names: []string{"linux", "Linux console"},
+	Bools: builtinBools(
+		BoolAutoRightMargin,
+		BoolEatNewlineGlitch,
+		BoolEraseOverstrike,
+		BoolMoveInsertMode,
+		BoolMoveStandoutMode,
+		BoolXONXOFF,
+		BoolCanChange,
+		BoolBackColorErase,
+	),
+	Numbers: builtinNumbers(map[NumCap]int{
+		NumInitTabs:     8,
+		NumMaxColors:    8,
+		NumMaxPairs:     64,
+		NumNoColorVideo: 18,
+	}),
+	Strings: builtinStrings(map[StrCap]string{
+		StrBell:                "\a",
+		StrCarriageReturn:      "\r",
+		StrChangeScrollRegion:  "\x1b[%i%p1%d;%p2%dr",
+		StrClearAllTabs:        "\x1b[3g",
+		StrClearScreen:         "\x1b[H\x1b[J",
+		StrClrEol:              "\x1b[K",
+		StrClrEos:              "\x1b[J",
+		StrColumnAddress:       "\x1b[%i%p1%dG",
+		StrCursorAddress:       "\x1b[%i%p1%d;%p2%dH",
+		StrCursorDown:          "\n",
+		StrCursorHome:          "\x1b[H",
+		StrCursorInvisible:     "\x1b[?25l\x1b[?1c",
+		StrCursorLeft:          "\b",
+		StrCursorNormal:        "\x1b[?25h\x1b[?0c",
+		StrCursorRight:         "\x1b[C",
+		StrCursorUp:            "\x1b[A",
+		StrCursorVisible:       "\x1b[?25h\x1b[?8c",
+		StrDeleteCharacter:     "\x1b[P",
+		StrDeleteLine:          "\x1b[M",
+		StrEnterAltCharsetMode: "\x0e",
+		StrEnterBlinkMode:      "\x1b[5m",
+		StrEnterBoldMode:       "\x1b[1m",
+		StrEnterDimMode:        "\x1b[2m",
+		StrEnterInsertMode:     "\x1b[4h",
+		StrEnterReverseMode:    "\x1b[7m",
+		StrEnterStandoutMode:   "\x1b[7m",
+		StrEnterUnderlineMode:  "\x1b[4m",
+		StrEraseChars:          "\x1b[%p1%dX",
+		StrExitAltCharsetMode:  "\x0f",
+		StrExitAttributeMode:   "\x1b[m\x0f",
+		StrExitInsertMode:      "\x1b[4l",
+		StrExitStandoutMode:    "\x1b[27m",
+		StrExitUnderlineMode:   "\x1b[24m",
+		StrFlashScreen:         "\x1b[?5h$<200/>\x1b[?5l",
+		StrInsertCharacter:     "\x1b[@",
+		StrInsertLine:          "\x1b[L",
+		StrKeyBackspace:        "\x7f",
+		StrKeyDc:               "\x1b[3~",
+		StrKeyDown:             "\x1b[B",
+		StrKeyF1:               "\x1b[[A",
+		StrKeyF10:              "\x1b[21~",
+		StrKeyF2:               "\x1b[[B",
+		StrKeyF3:               "\x1b[[C",
+		StrKeyF4:               "\x1b[[D",
+		StrKeyF5:               "\x1b[[E",
+		StrKeyF6:               "\x1b[17~",
+		StrKeyF7:               "\x1b[18~",
+		StrKeyF8:               "\x1b[19~",
+		StrKeyF9:               "\x1b[20~",
+		StrKeyHome:             "\x1b[1~",
+		StrKeyIc:               "\x1b[2~",
+		StrKeyLeft:             "\x1b[D",
+		StrKeyNpage:            "\x1b[6~",
+		StrKeyPpage:            "\x1b[5~",
+		StrKeyRight:            "\x1b[C",
+		StrKeyUp:               "\x1b[A",
+		StrNewline:             "\r\n",
+		StrParmDch:             "\x1b[%p1%dP",
+		StrParmDeleteLine:      "\x1b[%p1%dM",
+		StrParmDownCursor:      "\x1b[%p1%dB",
+		StrParmIch:             "\x1b[%p1%d@",
+		StrParmInsertLine:      "\x1b[%p1%dL",
+		StrParmLeftCursor:      "\x1b[%p1%dD",
+		StrParmRightCursor:     "\x1b[%p1%dC",
+		StrParmUpCursor:        "\x1b[%p1%dA",
+		StrReset1string:        "\x1bc\x1b]R",
+		StrRestoreCursor:       "\x1b8",
+		StrRowAddress:          "\x1b[%i%p1%dd",
+		StrSaveCursor:          "\x1b7",
+		StrScrollForward:       "\n",
+		StrScrollReverse:       "\x1bM",
+		StrSetAttributes:       "\x1b[0;10%?%p1%t;7%;%?%p2%t;4%;%?%p3%t;7%;%?%p4%t;5%;%?%p5%t;2%;%?%p6%t;1%;m%?%p9%t\x0e%e\x0f%;",
+		StrSetTab:              "\x1bH",
+		StrTab:                 "\t",
+		StrKeyB2:               "\x1b[G",
+		StrACSChars:            "++,,--..00``aaffgghhiijjkkllmmnnooppqqrrssttuuvvwwxxyyzz{{||}}~~",
+		StrKeyBtab:             "\x1b\t",
+		StrEnterAmMode:         "\x1b[?7h",
+		StrExitAmMode:          "\x1b[?7l",
+		StrEnaACS:              "\x1b)0",
+		StrKeyEnd:              "\x1b[4~",
+		StrKeySuspend:          "\x1a",
+		StrKeyF11:              "\x1b[23~",
+		StrKeyF12:              "\x1b[24~",
+		StrKeyF13:              "\x1b[25~",
+		StrKeyF14:              "\x1b[26~",
+		StrKeyF15:              "\x1b[28~",
+		StrKeyF16:              "\x1b[29~",
+		StrKeyF17:              "\x1b[31~",
+		StrKeyF18:              "\x1b[32~",
+		StrKeyF19:              "\x1b[33~",
+		StrKeyF20:              "\x1b[34~",
+		StrClrBol:              "\x1b[1K",
+		StrUser6:               "\x1b[%i%d;%dR",
+		StrUser7:               "\x1b[6n",
+		StrUser8:               "\x1b[?6c",
+		StrUser9:               "\x1b[c",
+		StrOrigPair:            "\x1b[39;49m",
+		StrOrigColors:          "\x1b]R",
+		StrInitializeColor:     "\x1b]P%p1%x%p2%{255}%*%{1000}%/%02x%p3%{255}%*%{1000}%/%02x%p4%{255}%*%{1000}%/%02x",
+		StrKeyMouse:            "\x1b[M",
+		StrSetAForeground:      "\x1b[3%p1%dm",
+		StrSetABackground:      "\x1b[4%p1%dm",
+		StrEnterPCCharsetMode:  "\x1b[11m",
+		StrExitPCCharsetMode:   "\x1b[10m",
+	}),
+	ExtBools: map[string]bool{
+		"AX": true,
+	},
+	ExtNumbers: map[string]int{
+		"U8": 1,
 	},
-	Funcs: [maxFuncs]string{
-		"",
-		"",
-		"",
-		"\x1b[?25h\x1b[?0c",
-		"\x1b[?25l\x1b[?1c",
-		"\x1b[H\x1b[J",
-		"\x1b[0;10m",
-		"\x1b[4m",
-		"\x1b[1m",
-		"\x1b[5m",
-		"\x1b[7m",
-		"",
-		"",
-		"",
-		"",
-	}}
+	ExtStrings: map[string]string{
+		"E3":    "\x1b[3J",
+		"kcbt2": "\x1b[Z",
+	},
+}
 
 func init() {
-	builtins["linux"] = &linux
-	compatTable = append(compatTable, compatEntry{"linux", &linux})
+	registerBuiltin(&linux, "linux")
 }
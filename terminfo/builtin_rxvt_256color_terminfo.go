@@ -1,50 +1,205 @@
+// Code generated by mkterminfo; DO NOT EDIT.
+
 package terminfo
 
 var rxvt256color = Terminfo{
-	Name: "rxvt-256color",
-	Keys: [maxKeys]string{
-		"",
-		"\x1b[11~",
-		"\x1b[12~",
-		"\x1b[13~",
-		"\x1b[14~",
-		"\x1b[15~",
-		"\x1b[17~",
-		"\x1b[18~",
-		"\x1b[19~",
-		"\x1b[20~",
-		"\x1b[21~",
-		"\x1b[23~",
-		"\x1b[24~",
-		"\x1b[2~",
-		"\x1b[3~",
-		"\x1b[7~",
-		"\x1b[8~",
-		"\x1b[5~",
-		"\x1b[6~",
-		"\x1b[A",
-		"\x1b[B",
-		"\x1b[D",
-		"\x1b[C",
+	Name:  "rxvt-256color",
+	Names: []string{"rxvt-256color", "rxvt 2.7.9 with xterm 256-colors"},
+	Bools: builtinBools(
+		BoolAutoRightMargin,
+		BoolEatNewlineGlitch,
+		BoolEraseOverstrike,
+		BoolMoveInsertMode,
+		BoolMoveStandoutMode,
+		BoolXONXOFF,
+		BoolCanChange,
+		BoolBackColorErase,
+		BoolBackspacesWithBS,
+	),
+	Numbers: builtinNumbers(map[NumCap]int{
+		NumColumns:   80,
+		NumInitTabs:  8,
+		NumLines:     24,
+		NumMaxColors: 256,
+		NumMaxPairs:  65536,
+	}),
+	Strings: builtinStrings(map[StrCap]string{
+		StrBell:                "\a",
+		StrCarriageReturn:      "\r",
+		StrChangeScrollRegion:  "\x1b[%i%p1%d;%p2%dr",
+		StrClearAllTabs:        "\x1b[3g",
+		StrClearScreen:         "\x1b[H\x1b[2J",
+		StrClrEol:              "\x1b[K",
+		StrClrEos:              "\x1b[J",
+		StrColumnAddress:       "\x1b[%i%p1%dG",
+		StrCursorAddress:       "\x1b[%i%p1%d;%p2%dH",
+		StrCursorDown:          "\n",
+		StrCursorHome:          "\x1b[H",
+		StrCursorInvisible:     "\x1b[?25l",
+		StrCursorLeft:          "\b",
+		StrCursorNormal:        "\x1b[?25h",
+		StrCursorRight:         "\x1b[C",
+		StrCursorUp:            "\x1b[A",
+		StrDeleteLine:          "\x1b[M",
+		StrEnterAltCharsetMode: "\x0e",
+		StrEnterBlinkMode:      "\x1b[5m",
+		StrEnterBoldMode:       "\x1b[1m",
+		StrEnterCAMode:         "\x1b7\x1b[?47h",
+		StrEnterInsertMode:     "\x1b[4h",
+		StrEnterReverseMode:    "\x1b[7m",
+		StrEnterStandoutMode:   "\x1b[7m",
+		StrEnterUnderlineMode:  "\x1b[4m",
+		StrExitAltCharsetMode:  "\x0f",
+		StrExitAttributeMode:   "\x1b[m\x0f",
+		StrExitCAMode:          "\x1b[2J\x1b[?47l\x1b8",
+		StrExitInsertMode:      "\x1b[4l",
+		StrExitStandoutMode:    "\x1b[27m",
+		StrExitUnderlineMode:   "\x1b[24m",
+		StrFlashScreen:         "\x1b[?5h$<100/>\x1b[?5l",
+		StrInit1string:         "\x1b[?47l\x1b=\x1b[?1l",
+		StrInit2string:         "\x1b[r\x1b[m\x1b[2J\x1b[H\x1b[?7h\x1b[?1;3;4;6l\x1b[4l",
+		StrInsertLine:          "\x1b[L",
+		StrKeyBackspace:        "\b",
+		StrKeyDc:               "\x1b[3~",
+		StrKeyDown:             "\x1b[B",
+		StrKeyEol:              "\x1b[8^",
+		StrKeyF0:               "\x1b[21~",
+		StrKeyF1:               "\x1b[11~",
+		StrKeyF10:              "\x1b[21~",
+		StrKeyF2:               "\x1b[12~",
+		StrKeyF3:               "\x1b[13~",
+		StrKeyF4:               "\x1b[14~",
+		StrKeyF5:               "\x1b[15~",
+		StrKeyF6:               "\x1b[17~",
+		StrKeyF7:               "\x1b[18~",
+		StrKeyF8:               "\x1b[19~",
+		StrKeyF9:               "\x1b[20~",
+		StrKeyHome:             "\x1b[7~",
+		StrKeyIc:               "\x1b[2~",
+		StrKeyLeft:             "\x1b[D",
+		StrKeyNpage:            "\x1b[6~",
+		StrKeyPpage:            "\x1b[5~",
+		StrKeyRight:            "\x1b[C",
+		StrKeySf:               "\x1b[a",
+		StrKeySr:               "\x1b[b",
+		StrKeyUp:               "\x1b[A",
+		StrKeypadLocal:         "\x1b>",
+		StrKeypadXmit:          "\x1b=",
+		StrParmDeleteLine:      "\x1b[%p1%dM",
+		StrParmDownCursor:      "\x1b[%p1%dB",
+		StrParmIch:             "\x1b[%p1%d@",
+		StrParmInsertLine:      "\x1b[%p1%dL",
+		StrParmLeftCursor:      "\x1b[%p1%dD",
+		StrParmRightCursor:     "\x1b[%p1%dC",
+		StrParmUpCursor:        "\x1b[%p1%dA",
+		StrReset1string:        "\x1b>\x1b[1;3;4;5;6l\x1b[?7h\x1b[m\x1b[r\x1b[2J\x1b[H",
+		StrReset2string:        "\x1b[r\x1b[m\x1b[2J\x1b[H\x1b[?7h\x1b[?1;3;4;6l\x1b[4l\x1b>\x1b[?1000l\x1b[?25h",
+		StrRestoreCursor:       "\x1b8",
+		StrRowAddress:          "\x1b[%i%p1%dd",
+		StrSaveCursor:          "\x1b7",
+		StrScrollForward:       "\n",
+		StrScrollReverse:       "\x1bM",
+		StrSetAttributes:       "\x1b[0%?%p6%t;1%;%?%p2%t;4%;%?%p1%p3%|%t;7%;%?%p4%t;5%;m%?%p9%t\x0e%e\x0f%;",
+		StrSetTab:              "\x1bH",
+		StrTab:                 "\t",
+		StrKeyA1:               "\x1bOw",
+		StrKeyA3:               "\x1bOy",
+		StrKeyB2:               "\x1bOu",
+		StrKeyC1:               "\x1bOq",
+		StrKeyC3:               "\x1bOs",
+		StrACSChars:            "``aaffggjjkkllmmnnooppqqrrssttuuvvwwxxyyzz{{||}}~~",
+		StrKeyBtab:             "\x1b[Z",
+		StrEnaACS:              "\x1b(B\x1b)0",
+		StrKeyEnd:              "\x1b[8~",
+		StrKeyEnter:            "\x1bOM",
+		StrKeyFind:             "\x1b[1~",
+		StrKeySdc:              "\x1b[3$",
+		StrKeySelect:           "\x1b[4~",
+		StrKeySend:             "\x1b[8$",
+		StrKeyShome:            "\x1b[7$",
+		StrKeySic:              "\x1b[2$",
+		StrKeySleft:            "\x1b[d",
+		StrKeySnext:            "\x1b[6$",
+		StrKeySprevious:        "\x1b[5$",
+		StrKeySright:           "\x1b[c",
+		StrKeyF11:              "\x1b[23~",
+		StrKeyF12:              "\x1b[24~",
+		StrKeyF13:              "\x1b[25~",
+		StrKeyF14:              "\x1b[26~",
+		StrKeyF15:              "\x1b[28~",
+		StrKeyF16:              "\x1b[29~",
+		StrKeyF17:              "\x1b[31~",
+		StrKeyF18:              "\x1b[32~",
+		StrKeyF19:              "\x1b[33~",
+		StrKeyF20:              "\x1b[34~",
+		StrKeyF21:              "\x1b[23$",
+		StrKeyF22:              "\x1b[24$",
+		StrKeyF23:              "\x1b[11^",
+		StrKeyF24:              "\x1b[12^",
+		StrKeyF25:              "\x1b[13^",
+		StrKeyF26:              "\x1b[14^",
+		StrKeyF27:              "\x1b[15^",
+		StrKeyF28:              "\x1b[17^",
+		StrKeyF29:              "\x1b[18^",
+		StrKeyF30:              "\x1b[19^",
+		StrKeyF31:              "\x1b[20^",
+		StrKeyF32:              "\x1b[21^",
+		StrKeyF33:              "\x1b[23^",
+		StrKeyF34:              "\x1b[24^",
+		StrKeyF35:              "\x1b[25^",
+		StrKeyF36:              "\x1b[26^",
+		StrKeyF37:              "\x1b[28^",
+		StrKeyF38:              "\x1b[29^",
+		StrKeyF39:              "\x1b[31^",
+		StrKeyF40:              "\x1b[32^",
+		StrKeyF41:              "\x1b[33^",
+		StrKeyF42:              "\x1b[34^",
+		StrKeyF43:              "\x1b[23@",
+		StrKeyF44:              "\x1b[24@",
+		StrClrBol:              "\x1b[1K",
+		StrUser6:               "\x1b[%i%d;%dR",
+		StrUser7:               "\x1b[6n",
+		StrUser8:               "\x1b[?1;2c",
+		StrUser9:               "\x1b[c",
+		StrOrigPair:            "\x1b[39;49m",
+		StrOrigColors:          "\x1b]104\a",
+		StrInitializeColor:     "\x1b]4;%p1%d;rgb:%p2%{255}%*%{1000}%/%2.2X/%p3%{255}%*%{1000}%/%2.2X/%p4%{255}%*%{1000}%/%2.2X\x1b\\",
+		StrKeyMouse:            "\x1b[M",
+		StrSetAForeground:      "\x1b[%?%p1%{8}%<%t3%p1%d%e%p1%{16}%<%t9%p1%{8}%-%d%e38;5;%p1%d%;m",
+		StrSetABackground:      "\x1b[%?%p1%{8}%<%t4%p1%d%e%p1%{16}%<%t10%p1%{8}%-%d%e48;5;%p1%d%;m",
+		StrSet0DesSeq:          "\x1b(B",
+		StrSet1DesSeq:          "\x1b(0",
+	}),
+	ExtBools: map[string]bool{
+		"AX": true,
+		"XT": true,
+	},
+	ExtStrings: map[string]string{
+		"kDC5":  "\x1b[3^",
+		"kDC6":  "\x1b[3@",
+		"kDN":   "\x1b[b",
+		"kDN5":  "\x1bOb",
+		"kEND5": "\x1b[8^",
+		"kEND6": "\x1b[8@",
+		"kHOM5": "\x1b[7^",
+		"kHOM6": "\x1b[7@",
+		"kIC5":  "\x1b[2^",
+		"kIC6":  "\x1b[2@",
+		"kLFT5": "\x1bOd",
+		"kNXT5": "\x1b[6^",
+		"kNXT6": "\x1b[6@",
+		"kPRV5": "\x1b[5^",
+		"kPRV6": "\x1b[5@",
+		"kRIT5": "\x1bOc",
+		"kUP":   "\x1b[a",
+		"kUP5":  "\x1bOa",
+		"ka2":   "\x1bOx",
+		"kb1":   "\x1bOt",
+		"kb3":   "\x1bOv",
+		"kc2":   "\x1bOr",
 	},
-	Funcs: [maxFuncs]string{
-		"",
-		"\x1b7\x1b[?47h",
-		"\x1b[2J\x1b[?47l\x1b8",
-		"\x1b[?25h",
-		"\x1b[?25l",
-		"\x1b[H\x1b[2J",
-		"\x1b[m\x0f",
-		"\x1b[4m",
-		"\x1b[1m",
-		"\x1b[5m",
-		"\x1b[7m",
-		"\x1b=",
-		"\x1b>",
-		"\x1b[?1000h\x1b[?1002h\x1b[?1015h\x1b[?1006h",
-		"\x1b[?1006l\x1b[?1015l\x1b[?1002l\x1b[?1000l",
-	}}
+}
 
 func init() {
-	builtins["rxvt-256color"] = &rxvt256color
+	registerBuiltin(&rxvt256color)
 }
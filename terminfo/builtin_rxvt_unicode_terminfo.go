@@ -1,51 +1,202 @@
+// Code generated by mkterminfo; DO NOT EDIT.
+
 package terminfo
 
 var rxvtUnicode = Terminfo{
-	Name: "rxvt-unicode",
-	Keys: [maxKeys]string{
-		"",
-		"\x1b[11~",
-		"\x1b[12~",
-		"\x1b[13~",
-		"\x1b[14~",
-		"\x1b[15~",
-		"\x1b[17~",
-		"\x1b[18~",
-		"\x1b[19~",
-		"\x1b[20~",
-		"\x1b[21~",
-		"\x1b[23~",
-		"\x1b[24~",
-		"\x1b[2~",
-		"\x1b[3~",
-		"\x1b[7~",
-		"\x1b[8~",
-		"\x1b[5~",
-		"\x1b[6~",
-		"\x1b[A",
-		"\x1b[B",
-		"\x1b[D",
-		"\x1b[C",
+	Name:  "rxvt-unicode",
+	Names: []string{"rxvt-unicode", "rxvt-unicode terminal (X Window System)"},
+	Bools: builtinBools(
+		BoolAutoLeftMargin,
+		BoolAutoRightMargin,
+		BoolEatNewlineGlitch,
+		BoolEraseOverstrike,
+		BoolHasMetaKey,
+		BoolHasStatusLine,
+		BoolMoveInsertMode,
+		BoolMoveStandoutMode,
+		BoolXONXOFF,
+		BoolPrtrSilent,
+		BoolNoPadChar,
+		BoolCanChange,
+		BoolBackColorErase,
+	),
+	Numbers: builtinNumbers(map[NumCap]int{
+		NumColumns:       80,
+		NumInitTabs:      8,
+		NumLines:         24,
+		NumLinesOfMemory: 0,
+		NumMaxColors:     88,
+		NumMaxPairs:      7744,
+		NumNoColorVideo:  0,
+		NumButtons:       5,
+	}),
+	Strings: builtinStrings(map[StrCap]string{
+		StrBell:                "\a",
+		StrCarriageReturn:      "\r",
+		StrChangeScrollRegion:  "\x1b[%i%p1%d;%p2%dr",
+		StrClearAllTabs:        "\x1b[3g",
+		StrClearScreen:         "\x1b[H\x1b[2J",
+		StrClrEol:              "\x1b[K",
+		StrClrEos:              "\x1b[J",
+		StrColumnAddress:       "\x1b[%i%p1%dG",
+		StrCursorAddress:       "\x1b[%i%p1%d;%p2%dH",
+		StrCursorDown:          "\n",
+		StrCursorHome:          "\x1b[H",
+		StrCursorInvisible:     "\x1b[?25l",
+		StrCursorLeft:          "\b",
+		StrCursorNormal:        "\x1b[?12l\x1b[?25h",
+		StrCursorRight:         "\x1b[C",
+		StrCursorUp:            "\x1b[A",
+		StrCursorVisible:       "\x1b[?12;25h",
+		StrDeleteCharacter:     "\x1b[P",
+		StrDeleteLine:          "\x1b[M",
+		StrDisStatusLine:       "\x1b]2;\a",
+		StrEnterAltCharsetMode: "\x1b(0",
+		StrEnterBlinkMode:      "\x1b[5m",
+		StrEnterBoldMode:       "\x1b[1m",
+		StrEnterCAMode:         "\x1b[?1049h",
+		StrEnterInsertMode:     "\x1b[4h",
+		StrEnterReverseMode:    "\x1b[7m",
+		StrEnterStandoutMode:   "\x1b[7m",
+		StrEnterUnderlineMode:  "\x1b[4m",
+		StrEraseChars:          "\x1b[%p1%dX",
+		StrExitAltCharsetMode:  "\x1b(B",
+		StrExitAttributeMode:   "\x1b[m\x1b(B",
+		StrExitCAMode:          "\x1b[r\x1b[?1049l",
+		StrExitInsertMode:      "\x1b[4l",
+		StrExitStandoutMode:    "\x1b[27m",
+		StrExitUnderlineMode:   "\x1b[24m",
+		StrFlashScreen:         "\x1b[?5h$<20/>\x1b[?5l",
+		StrFromStatusLine:      "\a",
+		StrInit1string:         "\x1b[!p",
+		StrInit2string:         "\x1b[r\x1b[m\x1b[2J\x1b[?7;25h\x1b[?1;3;4;5;6;9;66;1000;1001;1049l\x1b[4l",
+		StrInsertCharacter:     "\x1b[@",
+		StrInsertLine:          "\x1b[L",
+		StrKeyBackspace:        "\x7f",
+		StrKeyDc:               "\x1b[3~",
+		StrKeyDown:             "\x1b[B",
+		StrKeyEol:              "\x1b[8^",
+		StrKeyF1:               "\x1b[11~",
+		StrKeyF10:              "\x1b[21~",
+		StrKeyF2:               "\x1b[12~",
+		StrKeyF3:               "\x1b[13~",
+		StrKeyF4:               "\x1b[14~",
+		StrKeyF5:               "\x1b[15~",
+		StrKeyF6:               "\x1b[17~",
+		StrKeyF7:               "\x1b[18~",
+		StrKeyF8:               "\x1b[19~",
+		StrKeyF9:               "\x1b[20~",
+		StrKeyHome:             "\x1b[7~",
+		StrKeyIc:               "\x1b[2~",
+		StrKeyLeft:             "\x1b[D",
+		StrKeyNpage:            "\x1b[6~",
+		StrKeyPpage:            "\x1b[5~",
+		StrKeyRight:            "\x1b[C",
+		StrKeyUp:               "\x1b[A",
+		StrKeypadLocal:         "\x1b>",
+		StrKeypadXmit:          "\x1b=",
+		StrParmDch:             "\x1b[%p1%dP",
+		StrParmDeleteLine:      "\x1b[%p1%dM",
+		StrParmDownCursor:      "\x1b[%p1%dB",
+		StrParmIch:             "\x1b[%p1%d@",
+		StrParmIndex:           "\x1b[%p1%dS",
+		StrParmInsertLine:      "\x1b[%p1%dL",
+		StrParmLeftCursor:      "\x1b[%p1%dD",
+		StrParmRightCursor:     "\x1b[%p1%dC",
+		StrParmRindex:          "\x1b[%p1%dT",
+		StrParmUpCursor:        "\x1b[%p1%dA",
+		StrPrintScreen:         "\x1b[i",
+		StrPrtrOff:             "\x1b[4i",
+		StrPrtrOn:              "\x1b[5i",
+		StrReset1string:        "\x1bc",
+		StrReset2string:        "\x1b[r\x1b[m\x1b[?7;25h\x1b[?1;3;4;5;6;9;66;1000;1001;1049l\x1b[4l",
+		StrRestoreCursor:       "\x1b8",
+		StrRowAddress:          "\x1b[%i%p1%dd",
+		StrSaveCursor:          "\x1b7",
+		StrScrollForward:       "\n",
+		StrScrollReverse:       "\x1bM",
+		StrSetAttributes:       "\x1b[%?%p6%t;1%;%?%p2%t;4%;%?%p1%p3%|%t;7%;%?%p4%t;5%;%?%p7%t;8%;m%?%p9%t\x1b(0%e\x1b(B%;",
+		StrSetTab:              "\x1bH",
+		StrTab:                 "\t",
+		StrToStatusLine:        "\x1b]2;",
+		StrKeyA1:               "\x1bOw",
+		StrKeyA3:               "\x1bOy",
+		StrKeyB2:               "\x1bOu",
+		StrKeyC1:               "\x1bOq",
+		StrKeyC3:               "\x1bOs",
+		StrACSChars:            "+C,D-A.B0E``aaffgghFiGjjkkllmmnnooppqqrrssttuuvvwwxxyyzz{{||}}~~",
+		StrKeyBtab:             "\x1b[Z",
+		StrEnterAmMode:         "\x1b[?7h",
+		StrExitAmMode:          "\x1b[?7l",
+		StrKeyEnd:              "\x1b[8~",
+		StrKeyEnter:            "\x1bOM",
+		StrKeyFind:             "\x1b[1~",
+		StrKeySdc:              "\x1b[3$",
+		StrKeySelect:           "\x1b[4~",
+		StrKeySend:             "\x1b[8$",
+		StrKeySfind:            "\x1b[1$",
+		StrKeyShome:            "\x1b[7$",
+		StrKeySic:              "\x1b[2$",
+		StrKeySleft:            "\x1b[d",
+		StrKeySnext:            "\x1b[6$",
+		StrKeySprevious:        "\x1b[5$",
+		StrKeySright:           "\x1b[c",
+		StrKeyF11:              "\x1b[23~",
+		StrKeyF12:              "\x1b[24~",
+		StrKeyF13:              "\x1b[25~",
+		StrKeyF14:              "\x1b[26~",
+		StrKeyF15:              "\x1b[28~",
+		StrKeyF16:              "\x1b[29~",
+		StrKeyF17:              "\x1b[31~",
+		StrKeyF18:              "\x1b[32~",
+		StrKeyF19:              "\x1b[33~",
+		StrKeyF20:              "\x1b[34~",
+		StrClrBol:              "\x1b[1K",
+		StrUser6:               "\x1b[%i%d;%dR",
+		StrUser7:               "\x1b[6n",
+		StrUser8:               "\x1b[?1;2c",
+		StrUser9:               "\x1b[c",
+		StrOrigPair:            "\x1b[39;49m",
+		StrInitializeColor:     "\x1b]4;%p1%d;rgb:%p2%{65535}%*%{1000}%/%4.4X/%p3%{65535}%*%{1000}%/%4.4X/%p4%{65535}%*%{1000}%/%4.4X\x1b\\",
+		StrSetForeground:       "%?%p1%{7}%>%t\x1b[38;5;%p1%dm%e\x1b[3%?%p1%{1}%=%t4%e%p1%{3}%=%t6%e%p1%{4}%=%t1%e%p1%{6}%=%t3%e%p1%d%;m%;",
+		StrSetBackground:       "%?%p1%{7}%>%t\x1b[48;5;%p1%dm%e\x1b[4%?%p1%{1}%=%t4%e%p1%{3}%=%t6%e%p1%{4}%=%t1%e%p1%{6}%=%t3%e%p1%d%;m%;",
+		StrEnterItalicsMode:    "\x1b[3m",
+		StrExitItalicsMode:     "\x1b[23m",
+		StrKeyMouse:            "\x1b[M",
+		StrSetAForeground:      "\x1b[38;5;%p1%dm",
+		StrSetABackground:      "\x1b[48;5;%p1%dm",
+		StrSet0DesSeq:          "\x1b(B",
+		StrSet1DesSeq:          "\x1b(0",
+		StrSet2DesSeq:          "\x1b*B",
+		StrSet3DesSeq:          "\x1b+B",
+	}),
+	EmptyStrings: map[StrCap]bool{
+		StrEnaACS: true,
+	},
+	ExtStrings: map[string]string{
+		"kDC5":  "\x1b[3^",
+		"kDC6":  "\x1b[3@",
+		"kDN":   "\x1b[b",
+		"kDN5":  "\x1bOb",
+		"kEND5": "\x1b[8^",
+		"kEND6": "\x1b[8@",
+		"kFND5": "\x1b[1^",
+		"kFND6": "\x1b[1@",
+		"kHOM5": "\x1b[7^",
+		"kHOM6": "\x1b[7@",
+		"kIC5":  "\x1b[2^",
+		"kIC6":  "\x1b[2@",
+		"kLFT5": "\x1bOd",
+		"kNXT5": "\x1b[6^",
+		"kNXT6": "\x1b[6@",
+		"kPRV5": "\x1b[5^",
+		"kPRV6": "\x1b[5@",
+		"kRIT5": "\x1bOc",
+		"kUP":   "\x1b[a",
+		"kUP5":  "\x1bOa",
 	},
-	Funcs: [maxFuncs]string{
-		"",
-		"\x1b[?1049h",
-		"\x1b[r\x1b[?1049l",
-		"\x1b[?25h",
-		"\x1b[?25l",
-		"\x1b[H\x1b[2J",
-		"\x1b[m\x1b(B",
-		"\x1b[4m",
-		"\x1b[1m",
-		"\x1b[5m",
-		"\x1b[7m",
-		"\x1b=",
-		"\x1b>",
-		"\x1b[?1000h\x1b[?1002h\x1b[?1015h\x1b[?1006h",
-		"\x1b[?1006l\x1b[?1015l\x1b[?1002l\x1b[?1000l",
-	}}
+}
 
 func init() {
-	builtins["rxvt-unicode"] = &rxvtUnicode
-	compatTable = append(compatTable, compatEntry{"rxvt", &rxvtUnicode})
+	registerBuiltin(&rxvtUnicode, "rxvt")
 }
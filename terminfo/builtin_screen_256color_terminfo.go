@@ -0,0 +1,136 @@
+// Code generated by mkterminfo; DO NOT EDIT.
+
+package terminfo
+
+var screen256color = Terminfo{
+	Name:  "screen-256color",
+	Names: []string{"screen-256color", "GNU Screen with 256 colors"},
+	Bools: builtinBools(
+		BoolAutoRightMargin,
+		BoolEatNewlineGlitch,
+		BoolHasMetaKey,
+		BoolMoveInsertMode,
+		BoolMoveStandoutMode,
+		BoolBackspacesWithBS,
+		BoolHasHardwareTabs,
+	),
+	Numbers: builtinNumbers(map[NumCap]int{
+		NumColumns:   80,
+		NumInitTabs:  8,
+		NumLines:     24,
+		NumMaxColors: 256,
+		NumMaxPairs:  65536,
+	}),
+	Strings: builtinStrings(map[StrCap]string{
+		StrBackTab:             "\x1b[Z",
+		StrBell:                "\a",
+		StrCarriageReturn:      "\r",
+		StrChangeScrollRegion:  "\x1b[%i%p1%d;%p2%dr",
+		StrClearAllTabs:        "\x1b[3g",
+		StrClearScreen:         "\x1b[H\x1b[J",
+		StrClrEol:              "\x1b[K",
+		StrClrEos:              "\x1b[J",
+		StrColumnAddress:       "\x1b[%i%p1%dG",
+		StrCursorAddress:       "\x1b[%i%p1%d;%p2%dH",
+		StrCursorDown:          "\n",
+		StrCursorHome:          "\x1b[H",
+		StrCursorInvisible:     "\x1b[?25l",
+		StrCursorLeft:          "\b",
+		StrCursorNormal:        "\x1b[34h\x1b[?25h",
+		StrCursorRight:         "\x1b[C",
+		StrCursorUp:            "\x1bM",
+		StrCursorVisible:       "\x1b[34l",
+		StrDeleteCharacter:     "\x1b[P",
+		StrDeleteLine:          "\x1b[M",
+		StrEnterAltCharsetMode: "\x0e",
+		StrEnterBlinkMode:      "\x1b[5m",
+		StrEnterBoldMode:       "\x1b[1m",
+		StrEnterCAMode:         "\x1b[?1049h",
+		StrEnterDimMode:        "\x1b[2m",
+		StrEnterInsertMode:     "\x1b[4h",
+		StrEnterReverseMode:    "\x1b[7m",
+		StrEnterStandoutMode:   "\x1b[3m",
+		StrEnterUnderlineMode:  "\x1b[4m",
+		StrExitAltCharsetMode:  "\x0f",
+		StrExitAttributeMode:   "\x1b[m\x0f",
+		StrExitCAMode:          "\x1b[?1049l",
+		StrExitInsertMode:      "\x1b[4l",
+		StrExitStandoutMode:    "\x1b[23m",
+		StrExitUnderlineMode:   "\x1b[24m",
+		StrFlashScreen:         "\x1bg",
+		StrInit2string:         "\x1b)0",
+		StrInsertLine:          "\x1b[L",
+		StrKeyBackspace:        "\x7f",
+		StrKeyDc:               "\x1b[3~",
+		StrKeyDown:             "\x1bOB",
+		StrKeyF1:               "\x1bOP",
+		StrKeyF10:              "\x1b[21~",
+		StrKeyF2:               "\x1bOQ",
+		StrKeyF3:               "\x1bOR",
+		StrKeyF4:               "\x1bOS",
+		StrKeyF5:               "\x1b[15~",
+		StrKeyF6:               "\x1b[17~",
+		StrKeyF7:               "\x1b[18~",
+		StrKeyF8:               "\x1b[19~",
+		StrKeyF9:               "\x1b[20~",
+		StrKeyHome:             "\x1b[1~",
+		StrKeyIc:               "\x1b[2~",
+		StrKeyLeft:             "\x1bOD",
+		StrKeyNpage:            "\x1b[6~",
+		StrKeyPpage:            "\x1b[5~",
+		StrKeyRight:            "\x1bOC",
+		StrKeyUp:               "\x1bOA",
+		StrKeypadLocal:         "\x1b[?1l\x1b>",
+		StrKeypadXmit:          "\x1b[?1h\x1b=",
+		StrNewline:             "\x1bE",
+		StrParmDch:             "\x1b[%p1%dP",
+		StrParmDeleteLine:      "\x1b[%p1%dM",
+		StrParmDownCursor:      "\x1b[%p1%dB",
+		StrParmIch:             "\x1b[%p1%d@",
+		StrParmIndex:           "\x1b[%p1%dS",
+		StrParmInsertLine:      "\x1b[%p1%dL",
+		StrParmLeftCursor:      "\x1b[%p1%dD",
+		StrParmRightCursor:     "\x1b[%p1%dC",
+		StrParmRindex:          "\x1b[%p1%dT",
+		StrParmUpCursor:        "\x1b[%p1%dA",
+		StrReset2string:        "\x1bc\x1b[?1000l\x1b[?25h",
+		StrRestoreCursor:       "\x1b8",
+		StrRowAddress:          "\x1b[%i%p1%dd",
+		StrSaveCursor:          "\x1b7",
+		StrScrollForward:       "\n",
+		StrScrollReverse:       "\x1bM",
+		StrSetAttributes:       "\x1b[0%?%p6%t;1%;%?%p1%t;3%;%?%p2%t;4%;%?%p3%t;7%;%?%p4%t;5%;%?%p5%t;2%;m%?%p9%t\x0e%e\x0f%;",
+		StrSetTab:              "\x1bH",
+		StrTab:                 "\t",
+		StrACSChars:            "++,,--..00``aaffgghhiijjkkllmmnnooppqqrrssttuuvvwwxxyyzz{{||}}~~",
+		StrKeyBtab:             "\x1b[Z",
+		StrEnaACS:              "\x1b(B\x1b)0",
+		StrKeyEnd:              "\x1b[4~",
+		StrKeyF11:              "\x1b[23~",
+		StrKeyF12:              "\x1b[24~",
+		StrClrBol:              "\x1b[1K",
+		StrUser6:               "\x1b[%i%d;%dR",
+		StrUser7:               "\x1b[6n",
+		StrUser8:               "\x1b[?1;2c",
+		StrUser9:               "\x1b[c",
+		StrOrigPair:            "\x1b[39;49m",
+		StrKeyMouse:            "\x1b[M",
+		StrSetAForeground:      "\x1b[%?%p1%{8}%<%t3%p1%d%e%p1%{16}%<%t9%p1%{8}%-%d%e38;5;%p1%d%;m",
+		StrSetABackground:      "\x1b[%?%p1%{8}%<%t4%p1%d%e%p1%{16}%<%t10%p1%{8}%-%d%e48;5;%p1%d%;m",
+	}),
+	ExtBools: map[string]bool{
+		"AX": true,
+		"G0": true,
+	},
+	ExtNumbers: map[string]int{
+		"U8": 1,
+	},
+	ExtStrings: map[string]string{
+		"E0": "\x1b(B",
+		"S0": "\x1b(%p1%c",
+	},
+}
+
+func init() {
+	registerBuiltin(&screen256color)
+}
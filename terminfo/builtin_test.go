@@ -0,0 +1,98 @@
+package terminfo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi/terminfo"
+)
+
+func TestGetBuiltin(t *testing.T) {
+	for _, tc := range []struct {
+		term  string
+		entry string
+	}{
+		{"Eterm", "Eterm"},
+		{"Eterm-color", "Eterm"},
+		{"linux", "linux"},
+		{"rxvt-256color", "rxvt-256color"},
+		{"rxvt-unicode", "rxvt-unicode"},
+		{"screen", "screen"},
+		{"screen-256color", "screen-256color"},
+		{"xterm", "xterm"},
+		{"xterm-256color", "xterm-256color"},
+		{"tmux-256color", "tmux-256color"},
+		{"alacritty", "alacritty"},
+		{"kitty", "kitty"},
+		{"foot", "foot"},
+		{"wezterm", "wezterm"},
+		{"vte-256color", "vte-256color"},
+
+		// compat partial matches
+		{"xterm-kitty", "kitty"},
+		{"tmux", "tmux-256color"},
+		{"foot-extra", "foot"},
+		{"rxvt-unicode-256color", "rxvt-unicode"},
+		{"xterm-direct", "xterm"},
+		{"cygwin", "xterm"},
+		{"screen.xterm-256color", "screen"},
+	} {
+		t.Run(tc.term, func(t *testing.T) {
+			ti, err := terminfo.GetBuiltin(tc.term)
+			require.NoError(t, err)
+			assert.Equal(t, tc.entry, ti.Name)
+		})
+	}
+
+	_, err := terminfo.GetBuiltin("nonesuch")
+	assert.Error(t, err)
+}
+
+func TestBuiltins_generated(t *testing.T) {
+	// guards against the generated builtins falling out of date with their
+	// source; run go generate if this fails
+	for _, term := range []string{
+		"Eterm", "linux", "rxvt-256color", "rxvt-unicode", "screen",
+		"screen-256color", "xterm", "xterm-256color", "tmux-256color",
+		"alacritty", "kitty", "foot", "wezterm", "vte-256color",
+	} {
+		t.Run(term, func(t *testing.T) {
+			src, err := terminfo.LoadSource("builtins.src", term)
+			require.NoError(t, err)
+			ti, err := terminfo.GetBuiltin(term)
+			require.NoError(t, err)
+			assertSameTerminfo(t, src, ti)
+		})
+	}
+
+	ti, err := terminfo.GetBuiltin("tmux-256color")
+	require.NoError(t, err)
+	smulx, err := ti.Param("Smulx", 3)
+	require.NoError(t, err)
+	assert.Equal(t, "\x1b[4:3m", smulx)
+}
+
+func TestLocate(t *testing.T) {
+	t.Setenv("TERMINFO", "testdata")
+	for _, tc := range []struct {
+		term   string
+		entry  string
+		origin terminfo.Origin
+	}{
+		{"xterm", "xterm", terminfo.Origin{Path: "testdata/x/xterm"}},
+		{"linux", "linux", terminfo.Origin{Builtin: "linux"}},
+		{"xterm-kitty", "kitty", terminfo.Origin{Builtin: "kitty", Partial: "kitty"}},
+	} {
+		t.Run(tc.term, func(t *testing.T) {
+			ti, origin, err := terminfo.Locate(tc.term)
+			require.NoError(t, err)
+			assert.Equal(t, tc.entry, ti.Name)
+			assert.Equal(t, tc.origin, origin)
+		})
+	}
+
+	_, _, err := terminfo.Locate("nonesuch")
+	assert.Error(t, err)
+}
@@ -0,0 +1,1062 @@
+package terminfo
+
+// The standard capability tables below follow the order in which ncurses
+// stores capabilities in compiled terminfo files, as defined by its
+// include/Caps file; see term(5) and terminfo(5). Names prefixed by "OT" are
+// obsolete termcap capabilities that ncurses retains for compatibility.
+
+// BoolCap identifies a standard boolean capability.
+type BoolCap int
+
+// NumCap identifies a standard numeric capability.
+type NumCap int
+
+// StrCap identifies a standard string capability.
+type StrCap int
+
+type capNames struct{ name, longName string }
+
+// Standard boolean capabilities.
+const (
+	BoolAutoLeftMargin         BoolCap = iota // bw
+	BoolAutoRightMargin                       // am
+	BoolNoEscCtlC                             // xsb
+	BoolCeolStandoutGlitch                    // xhp
+	BoolEatNewlineGlitch                      // xenl
+	BoolEraseOverstrike                       // eo
+	BoolGenericType                           // gn
+	BoolHardCopy                              // hc
+	BoolHasMetaKey                            // km
+	BoolHasStatusLine                         // hs
+	BoolInsertNullGlitch                      // in
+	BoolMemoryAbove                           // da
+	BoolMemoryBelow                           // db
+	BoolMoveInsertMode                        // mir
+	BoolMoveStandoutMode                      // msgr
+	BoolOverStrike                            // os
+	BoolStatusLineEscOk                       // eslok
+	BoolDestTabsMagicSmso                     // xt
+	BoolTildeGlitch                           // hz
+	BoolTransparentUnderline                  // ul
+	BoolXONXOFF                               // xon
+	BoolNeedsXONXOFF                          // nxon
+	BoolPrtrSilent                            // mc5i
+	BoolHardCursor                            // chts
+	BoolNonRevRmcup                           // nrrmc
+	BoolNoPadChar                             // npc
+	BoolNonDestScrollRegion                   // ndscr
+	BoolCanChange                             // ccc
+	BoolBackColorErase                        // bce
+	BoolHueLightnessSaturation                // hls
+	BoolColAddrGlitch                         // xhpa
+	BoolCRCancelsMicroMode                    // crxm
+	BoolHasPrintWheel                         // daisy
+	BoolRowAddrGlitch                         // xvpa
+	BoolSemiAutoRightMargin                   // sam
+	BoolCpiChangesRes                         // cpix
+	BoolLpiChangesRes                         // lpix
+	BoolBackspacesWithBS                      // OTbs
+	BoolCRTNoScrolling                        // OTns
+	BoolNoCorrectlyWorkingCR                  // OTnc
+	BoolGnuHasMetaKey                         // OTMT
+	BoolLinefeedIsNewline                     // OTNL
+	BoolHasHardwareTabs                       // OTpt
+	BoolReturnDoesClrEol                      // OTxr
+
+	numBoolCaps
+)
+
+var boolCapNames = [numBoolCaps]capNames{
+	{"bw", "auto_left_margin"},
+	{"am", "auto_right_margin"},
+	{"xsb", "no_esc_ctlc"},
+	{"xhp", "ceol_standout_glitch"},
+	{"xenl", "eat_newline_glitch"},
+	{"eo", "erase_overstrike"},
+	{"gn", "generic_type"},
+	{"hc", "hard_copy"},
+	{"km", "has_meta_key"},
+	{"hs", "has_status_line"},
+	{"in", "insert_null_glitch"},
+	{"da", "memory_above"},
+	{"db", "memory_below"},
+	{"mir", "move_insert_mode"},
+	{"msgr", "move_standout_mode"},
+	{"os", "over_strike"},
+	{"eslok", "status_line_esc_ok"},
+	{"xt", "dest_tabs_magic_smso"},
+	{"hz", "tilde_glitch"},
+	{"ul", "transparent_underline"},
+	{"xon", "xon_xoff"},
+	{"nxon", "needs_xon_xoff"},
+	{"mc5i", "prtr_silent"},
+	{"chts", "hard_cursor"},
+	{"nrrmc", "non_rev_rmcup"},
+	{"npc", "no_pad_char"},
+	{"ndscr", "non_dest_scroll_region"},
+	{"ccc", "can_change"},
+	{"bce", "back_color_erase"},
+	{"hls", "hue_lightness_saturation"},
+	{"xhpa", "col_addr_glitch"},
+	{"crxm", "cr_cancels_micro_mode"},
+	{"daisy", "has_print_wheel"},
+	{"xvpa", "row_addr_glitch"},
+	{"sam", "semi_auto_right_margin"},
+	{"cpix", "cpi_changes_res"},
+	{"lpix", "lpi_changes_res"},
+	{"OTbs", "backspaces_with_bs"},
+	{"OTns", "crt_no_scrolling"},
+	{"OTnc", "no_correctly_working_cr"},
+	{"OTMT", "gnu_has_meta_key"},
+	{"OTNL", "linefeed_is_newline"},
+	{"OTpt", "has_hardware_tabs"},
+	{"OTxr", "return_does_clr_eol"},
+}
+
+// Name returns the capability's terminfo name, e.g. "am".
+func (c BoolCap) Name() string { return boolCapNames[c].name }
+
+// LongName returns the capability's long (C variable) name, e.g. "auto_right_margin".
+func (c BoolCap) LongName() string { return boolCapNames[c].longName }
+
+func (c BoolCap) String() string { return c.Name() }
+
+// Standard numeric capabilities.
+const (
+	NumColumns              NumCap = iota // cols
+	NumInitTabs                           // it
+	NumLines                              // lines
+	NumLinesOfMemory                      // lm
+	NumMagicCookieGlitch                  // xmc
+	NumPaddingBaudRate                    // pb
+	NumVirtualTerminal                    // vt
+	NumWidthStatusLine                    // wsl
+	NumNumLabels                          // nlab
+	NumLabelHeight                        // lh
+	NumLabelWidth                         // lw
+	NumMaxAttributes                      // ma
+	NumMaximumWindows                     // wnum
+	NumMaxColors                          // colors
+	NumMaxPairs                           // pairs
+	NumNoColorVideo                       // ncv
+	NumBufferCapacity                     // bufsz
+	NumDotVertSpacing                     // spinv
+	NumDotHorzSpacing                     // spinh
+	NumMaxMicroAddress                    // maddr
+	NumMaxMicroJump                       // mjump
+	NumMicroColSize                       // mcs
+	NumMicroLineSize                      // mls
+	NumNumberOfPins                       // npins
+	NumOutputResChar                      // orc
+	NumOutputResLine                      // orl
+	NumOutputResHorzInch                  // orhi
+	NumOutputResVertInch                  // orvi
+	NumPrintRate                          // cps
+	NumWideCharSize                       // widcs
+	NumButtons                            // btns
+	NumBitImageEntwining                  // bitwin
+	NumBitImageType                       // bitype
+	NumMagicCookieGlitchUl                // OTug
+	NumCarriageReturnDelay                // OTdC
+	NumNewLineDelay                       // OTdN
+	NumBackspaceDelay                     // OTdB
+	NumHorizontalTabDelay                 // OTdT
+	NumNumberOfFunctionKeys               // OTkn
+
+	numNumCaps
+)
+
+var numCapNames = [numNumCaps]capNames{
+	{"cols", "columns"},
+	{"it", "init_tabs"},
+	{"lines", "lines"},
+	{"lm", "lines_of_memory"},
+	{"xmc", "magic_cookie_glitch"},
+	{"pb", "padding_baud_rate"},
+	{"vt", "virtual_terminal"},
+	{"wsl", "width_status_line"},
+	{"nlab", "num_labels"},
+	{"lh", "label_height"},
+	{"lw", "label_width"},
+	{"ma", "max_attributes"},
+	{"wnum", "maximum_windows"},
+	{"colors", "max_colors"},
+	{"pairs", "max_pairs"},
+	{"ncv", "no_color_video"},
+	{"bufsz", "buffer_capacity"},
+	{"spinv", "dot_vert_spacing"},
+	{"spinh", "dot_horz_spacing"},
+	{"maddr", "max_micro_address"},
+	{"mjump", "max_micro_jump"},
+	{"mcs", "micro_col_size"},
+	{"mls", "micro_line_size"},
+	{"npins", "number_of_pins"},
+	{"orc", "output_res_char"},
+	{"orl", "output_res_line"},
+	{"orhi", "output_res_horz_inch"},
+	{"orvi", "output_res_vert_inch"},
+	{"cps", "print_rate"},
+	{"widcs", "wide_char_size"},
+	{"btns", "buttons"},
+	{"bitwin", "bit_image_entwining"},
+	{"bitype", "bit_image_type"},
+	{"OTug", "magic_cookie_glitch_ul"},
+	{"OTdC", "carriage_return_delay"},
+	{"OTdN", "new_line_delay"},
+	{"OTdB", "backspace_delay"},
+	{"OTdT", "horizontal_tab_delay"},
+	{"OTkn", "number_of_function_keys"},
+}
+
+// Name returns the capability's terminfo name, e.g. "it".
+func (c NumCap) Name() string { return numCapNames[c].name }
+
+// LongName returns the capability's long (C variable) name, e.g. "init_tabs".
+func (c NumCap) LongName() string { return numCapNames[c].longName }
+
+func (c NumCap) String() string { return c.Name() }
+
+// Standard string capabilities.
+const (
+	StrBackTab                StrCap = iota // cbt
+	StrBell                                 // bel
+	StrCarriageReturn                       // cr
+	StrChangeScrollRegion                   // csr
+	StrClearAllTabs                         // tbc
+	StrClearScreen                          // clear
+	StrClrEol                               // el
+	StrClrEos                               // ed
+	StrColumnAddress                        // hpa
+	StrCommandCharacter                     // cmdch
+	StrCursorAddress                        // cup
+	StrCursorDown                           // cud1
+	StrCursorHome                           // home
+	StrCursorInvisible                      // civis
+	StrCursorLeft                           // cub1
+	StrCursorMemAddress                     // mrcup
+	StrCursorNormal                         // cnorm
+	StrCursorRight                          // cuf1
+	StrCursorToLl                           // ll
+	StrCursorUp                             // cuu1
+	StrCursorVisible                        // cvvis
+	StrDeleteCharacter                      // dch1
+	StrDeleteLine                           // dl1
+	StrDisStatusLine                        // dsl
+	StrDownHalfLine                         // hd
+	StrEnterAltCharsetMode                  // smacs
+	StrEnterBlinkMode                       // blink
+	StrEnterBoldMode                        // bold
+	StrEnterCAMode                          // smcup
+	StrEnterDeleteMode                      // smdc
+	StrEnterDimMode                         // dim
+	StrEnterInsertMode                      // smir
+	StrEnterSecureMode                      // invis
+	StrEnterProtectedMode                   // prot
+	StrEnterReverseMode                     // rev
+	StrEnterStandoutMode                    // smso
+	StrEnterUnderlineMode                   // smul
+	StrEraseChars                           // ech
+	StrExitAltCharsetMode                   // rmacs
+	StrExitAttributeMode                    // sgr0
+	StrExitCAMode                           // rmcup
+	StrExitDeleteMode                       // rmdc
+	StrExitInsertMode                       // rmir
+	StrExitStandoutMode                     // rmso
+	StrExitUnderlineMode                    // rmul
+	StrFlashScreen                          // flash
+	StrFormFeed                             // ff
+	StrFromStatusLine                       // fsl
+	StrInit1string                          // is1
+	StrInit2string                          // is2
+	StrInit3string                          // is3
+	StrInitFile                             // if
+	StrInsertCharacter                      // ich1
+	StrInsertLine                           // il1
+	StrInsertPadding                        // ip
+	StrKeyBackspace                         // kbs
+	StrKeyCatab                             // ktbc
+	StrKeyClear                             // kclr
+	StrKeyCtab                              // kctab
+	StrKeyDc                                // kdch1
+	StrKeyDl                                // kdl1
+	StrKeyDown                              // kcud1
+	StrKeyEic                               // krmir
+	StrKeyEol                               // kel
+	StrKeyEos                               // ked
+	StrKeyF0                                // kf0
+	StrKeyF1                                // kf1
+	StrKeyF10                               // kf10
+	StrKeyF2                                // kf2
+	StrKeyF3                                // kf3
+	StrKeyF4                                // kf4
+	StrKeyF5                                // kf5
+	StrKeyF6                                // kf6
+	StrKeyF7                                // kf7
+	StrKeyF8                                // kf8
+	StrKeyF9                                // kf9
+	StrKeyHome                              // khome
+	StrKeyIc                                // kich1
+	StrKeyIl                                // kil1
+	StrKeyLeft                              // kcub1
+	StrKeyLl                                // kll
+	StrKeyNpage                             // knp
+	StrKeyPpage                             // kpp
+	StrKeyRight                             // kcuf1
+	StrKeySf                                // kind
+	StrKeySr                                // kri
+	StrKeyStab                              // khts
+	StrKeyUp                                // kcuu1
+	StrKeypadLocal                          // rmkx
+	StrKeypadXmit                           // smkx
+	StrLabF0                                // lf0
+	StrLabF1                                // lf1
+	StrLabF10                               // lf10
+	StrLabF2                                // lf2
+	StrLabF3                                // lf3
+	StrLabF4                                // lf4
+	StrLabF5                                // lf5
+	StrLabF6                                // lf6
+	StrLabF7                                // lf7
+	StrLabF8                                // lf8
+	StrLabF9                                // lf9
+	StrMetaOff                              // rmm
+	StrMetaOn                               // smm
+	StrNewline                              // nel
+	StrPadChar                              // pad
+	StrParmDch                              // dch
+	StrParmDeleteLine                       // dl
+	StrParmDownCursor                       // cud
+	StrParmIch                              // ich
+	StrParmIndex                            // indn
+	StrParmInsertLine                       // il
+	StrParmLeftCursor                       // cub
+	StrParmRightCursor                      // cuf
+	StrParmRindex                           // rin
+	StrParmUpCursor                         // cuu
+	StrPkeyKey                              // pfkey
+	StrPkeyLocal                            // pfloc
+	StrPkeyXmit                             // pfx
+	StrPrintScreen                          // mc0
+	StrPrtrOff                              // mc4
+	StrPrtrOn                               // mc5
+	StrRepeatChar                           // rep
+	StrReset1string                         // rs1
+	StrReset2string                         // rs2
+	StrReset3string                         // rs3
+	StrResetFile                            // rf
+	StrRestoreCursor                        // rc
+	StrRowAddress                           // vpa
+	StrSaveCursor                           // sc
+	StrScrollForward                        // ind
+	StrScrollReverse                        // ri
+	StrSetAttributes                        // sgr
+	StrSetTab                               // hts
+	StrSetWindow                            // wind
+	StrTab                                  // ht
+	StrToStatusLine                         // tsl
+	StrUnderlineChar                        // uc
+	StrUpHalfLine                           // hu
+	StrInitProg                             // iprog
+	StrKeyA1                                // ka1
+	StrKeyA3                                // ka3
+	StrKeyB2                                // kb2
+	StrKeyC1                                // kc1
+	StrKeyC3                                // kc3
+	StrPrtrNon                              // mc5p
+	StrCharPadding                          // rmp
+	StrACSChars                             // acsc
+	StrPlabNorm                             // pln
+	StrKeyBtab                              // kcbt
+	StrEnterXONMode                         // smxon
+	StrExitXONMode                          // rmxon
+	StrEnterAmMode                          // smam
+	StrExitAmMode                           // rmam
+	StrXONCharacter                         // xonc
+	StrXOFFCharacter                        // xoffc
+	StrEnaACS                               // enacs
+	StrLabelOn                              // smln
+	StrLabelOff                             // rmln
+	StrKeyBeg                               // kbeg
+	StrKeyCancel                            // kcan
+	StrKeyClose                             // kclo
+	StrKeyCommand                           // kcmd
+	StrKeyCopy                              // kcpy
+	StrKeyCreate                            // kcrt
+	StrKeyEnd                               // kend
+	StrKeyEnter                             // kent
+	StrKeyExit                              // kext
+	StrKeyFind                              // kfnd
+	StrKeyHelp                              // khlp
+	StrKeyMark                              // kmrk
+	StrKeyMessage                           // kmsg
+	StrKeyMove                              // kmov
+	StrKeyNext                              // knxt
+	StrKeyOpen                              // kopn
+	StrKeyOptions                           // kopt
+	StrKeyPrevious                          // kprv
+	StrKeyPrint                             // kprt
+	StrKeyRedo                              // krdo
+	StrKeyReference                         // kref
+	StrKeyRefresh                           // krfr
+	StrKeyReplace                           // krpl
+	StrKeyRestart                           // krst
+	StrKeyResume                            // kres
+	StrKeySave                              // ksav
+	StrKeySuspend                           // kspd
+	StrKeyUndo                              // kund
+	StrKeySbeg                              // kBEG
+	StrKeyScancel                           // kCAN
+	StrKeyScommand                          // kCMD
+	StrKeyScopy                             // kCPY
+	StrKeyScreate                           // kCRT
+	StrKeySdc                               // kDC
+	StrKeySdl                               // kDL
+	StrKeySelect                            // kslt
+	StrKeySend                              // kEND
+	StrKeySeol                              // kEOL
+	StrKeySexit                             // kEXT
+	StrKeySfind                             // kFND
+	StrKeyShelp                             // kHLP
+	StrKeyShome                             // kHOM
+	StrKeySic                               // kIC
+	StrKeySleft                             // kLFT
+	StrKeySmessage                          // kMSG
+	StrKeySmove                             // kMOV
+	StrKeySnext                             // kNXT
+	StrKeySoptions                          // kOPT
+	StrKeySprevious                         // kPRV
+	StrKeySprint                            // kPRT
+	StrKeySredo                             // kRDO
+	StrKeySreplace                          // kRPL
+	StrKeySright                            // kRIT
+	StrKeySrsume                            // kRES
+	StrKeySsave                             // kSAV
+	StrKeySsuspend                          // kSPD
+	StrKeySundo                             // kUND
+	StrReqForInput                          // rfi
+	StrKeyF11                               // kf11
+	StrKeyF12                               // kf12
+	StrKeyF13                               // kf13
+	StrKeyF14                               // kf14
+	StrKeyF15                               // kf15
+	StrKeyF16                               // kf16
+	StrKeyF17                               // kf17
+	StrKeyF18                               // kf18
+	StrKeyF19                               // kf19
+	StrKeyF20                               // kf20
+	StrKeyF21                               // kf21
+	StrKeyF22                               // kf22
+	StrKeyF23                               // kf23
+	StrKeyF24                               // kf24
+	StrKeyF25                               // kf25
+	StrKeyF26                               // kf26
+	StrKeyF27                               // kf27
+	StrKeyF28                               // kf28
+	StrKeyF29                               // kf29
+	StrKeyF30                               // kf30
+	StrKeyF31                               // kf31
+	StrKeyF32                               // kf32
+	StrKeyF33                               // kf33
+	StrKeyF34                               // kf34
+	StrKeyF35                               // kf35
+	StrKeyF36                               // kf36
+	StrKeyF37                               // kf37
+	StrKeyF38                               // kf38
+	StrKeyF39                               // kf39
+	StrKeyF40                               // kf40
+	StrKeyF41                               // kf41
+	StrKeyF42                               // kf42
+	StrKeyF43                               // kf43
+	StrKeyF44                               // kf44
+	StrKeyF45                               // kf45
+	StrKeyF46                               // kf46
+	StrKeyF47                               // kf47
+	StrKeyF48                               // kf48
+	StrKeyF49                               // kf49
+	StrKeyF50                               // kf50
+	StrKeyF51                               // kf51
+	StrKeyF52                               // kf52
+	StrKeyF53                               // kf53
+	StrKeyF54                               // kf54
+	StrKeyF55                               // kf55
+	StrKeyF56                               // kf56
+	StrKeyF57                               // kf57
+	StrKeyF58                               // kf58
+	StrKeyF59                               // kf59
+	StrKeyF60                               // kf60
+	StrKeyF61                               // kf61
+	StrKeyF62                               // kf62
+	StrKeyF63                               // kf63
+	StrClrBol                               // el1
+	StrClearMargins                         // mgc
+	StrSetLeftMargin                        // smgl
+	StrSetRightMargin                       // smgr
+	StrLabelFormat                          // fln
+	StrSetClock                             // sclk
+	StrDisplayClock                         // dclk
+	StrRemoveClock                          // rmclk
+	StrCreateWindow                         // cwin
+	StrGotoWindow                           // wingo
+	StrHangup                               // hup
+	StrDialPhone                            // dial
+	StrQuickDial                            // qdial
+	StrTone                                 // tone
+	StrPulse                                // pulse
+	StrFlashHook                            // hook
+	StrFixedPause                           // pause
+	StrWaitTone                             // wait
+	StrUser0                                // u0
+	StrUser1                                // u1
+	StrUser2                                // u2
+	StrUser3                                // u3
+	StrUser4                                // u4
+	StrUser5                                // u5
+	StrUser6                                // u6
+	StrUser7                                // u7
+	StrUser8                                // u8
+	StrUser9                                // u9
+	StrOrigPair                             // op
+	StrOrigColors                           // oc
+	StrInitializeColor                      // initc
+	StrInitializePair                       // initp
+	StrSetColorPair                         // scp
+	StrSetForeground                        // setf
+	StrSetBackground                        // setb
+	StrChangeCharPitch                      // cpi
+	StrChangeLinePitch                      // lpi
+	StrChangeResHorz                        // chr
+	StrChangeResVert                        // cvr
+	StrDefineChar                           // defc
+	StrEnterDoublewideMode                  // swidm
+	StrEnterDraftQuality                    // sdrfq
+	StrEnterItalicsMode                     // sitm
+	StrEnterLeftwardMode                    // slm
+	StrEnterMicroMode                       // smicm
+	StrEnterNearLetterQuality               // snlq
+	StrEnterNormalQuality                   // snrmq
+	StrEnterShadowMode                      // sshm
+	StrEnterSubscriptMode                   // ssubm
+	StrEnterSuperscriptMode                 // ssupm
+	StrEnterUpwardMode                      // sum
+	StrExitDoublewideMode                   // rwidm
+	StrExitItalicsMode                      // ritm
+	StrExitLeftwardMode                     // rlm
+	StrExitMicroMode                        // rmicm
+	StrExitShadowMode                       // rshm
+	StrExitSubscriptMode                    // rsubm
+	StrExitSuperscriptMode                  // rsupm
+	StrExitUpwardMode                       // rum
+	StrMicroColumnAddress                   // mhpa
+	StrMicroDown                            // mcud1
+	StrMicroLeft                            // mcub1
+	StrMicroRight                           // mcuf1
+	StrMicroRowAddress                      // mvpa
+	StrMicroUp                              // mcuu1
+	StrOrderOfPins                          // porder
+	StrParmDownMicro                        // mcud
+	StrParmLeftMicro                        // mcub
+	StrParmRightMicro                       // mcuf
+	StrParmUpMicro                          // mcuu
+	StrSelectCharSet                        // scs
+	StrSetBottomMargin                      // smgb
+	StrSetBottomMarginParm                  // smgbp
+	StrSetLeftMarginParm                    // smglp
+	StrSetRightMarginParm                   // smgrp
+	StrSetTopMargin                         // smgt
+	StrSetTopMarginParm                     // smgtp
+	StrStartBitImage                        // sbim
+	StrStartCharSetDef                      // scsd
+	StrStopBitImage                         // rbim
+	StrStopCharSetDef                       // rcsd
+	StrSubscriptCharacters                  // subcs
+	StrSuperscriptCharacters                // supcs
+	StrTheseCauseCR                         // docr
+	StrZeroMotion                           // zerom
+	StrCharSetNames                         // csnm
+	StrKeyMouse                             // kmous
+	StrMouseInfo                            // minfo
+	StrReqMousePos                          // reqmp
+	StrGetMouse                             // getm
+	StrSetAForeground                       // setaf
+	StrSetABackground                       // setab
+	StrPkeyPlab                             // pfxl
+	StrDeviceType                           // devt
+	StrCodeSetInit                          // csin
+	StrSet0DesSeq                           // s0ds
+	StrSet1DesSeq                           // s1ds
+	StrSet2DesSeq                           // s2ds
+	StrSet3DesSeq                           // s3ds
+	StrSetLrMargin                          // smglr
+	StrSetTbMargin                          // smgtb
+	StrBitImageRepeat                       // birep
+	StrBitImageNewline                      // binel
+	StrBitImageCarriageReturn               // bicr
+	StrColorNames                           // colornm
+	StrDefineBitImageRegion                 // defbi
+	StrEndBitImageRegion                    // endbi
+	StrSetColorBand                         // setcolor
+	StrSetPageLength                        // slines
+	StrDisplayPCChar                        // dispc
+	StrEnterPCCharsetMode                   // smpch
+	StrExitPCCharsetMode                    // rmpch
+	StrEnterScancodeMode                    // smsc
+	StrExitScancodeMode                     // rmsc
+	StrPCTermOptions                        // pctrm
+	StrScancodeEscape                       // scesc
+	StrAltScancodeEsc                       // scesa
+	StrEnterHorizontalHlMode                // ehhlm
+	StrEnterLeftHlMode                      // elhlm
+	StrEnterLowHlMode                       // elohlm
+	StrEnterRightHlMode                     // erhlm
+	StrEnterTopHlMode                       // ethlm
+	StrEnterVerticalHlMode                  // evhlm
+	StrSetAAttributes                       // sgr1
+	StrSetPglenInch                         // slength
+	StrTermcapInit2                         // OTi2
+	StrTermcapReset                         // OTrs
+	StrLinefeedIfNotLF                      // OTnl
+	StrBackspaceIfNotBS                     // OTbc
+	StrOtherNonFunctionKeys                 // OTko
+	StrArrowKeyMap                          // OTma
+	StrACSUlcorner                          // OTG2
+	StrACSLlcorner                          // OTG3
+	StrACSUrcorner                          // OTG1
+	StrACSLrcorner                          // OTG4
+	StrACSLtee                              // OTGR
+	StrACSRtee                              // OTGL
+	StrACSBtee                              // OTGU
+	StrACSTtee                              // OTGD
+	StrACSHline                             // OTGH
+	StrACSVline                             // OTGV
+	StrACSPlus                              // OTGC
+	StrMemoryLock                           // meml
+	StrMemoryUnlock                         // memu
+	StrBoxChars1                            // box1
+
+	numStrCaps
+)
+
+var strCapNames = [numStrCaps]capNames{
+	{"cbt", "back_tab"},
+	{"bel", "bell"},
+	{"cr", "carriage_return"},
+	{"csr", "change_scroll_region"},
+	{"tbc", "clear_all_tabs"},
+	{"clear", "clear_screen"},
+	{"el", "clr_eol"},
+	{"ed", "clr_eos"},
+	{"hpa", "column_address"},
+	{"cmdch", "command_character"},
+	{"cup", "cursor_address"},
+	{"cud1", "cursor_down"},
+	{"home", "cursor_home"},
+	{"civis", "cursor_invisible"},
+	{"cub1", "cursor_left"},
+	{"mrcup", "cursor_mem_address"},
+	{"cnorm", "cursor_normal"},
+	{"cuf1", "cursor_right"},
+	{"ll", "cursor_to_ll"},
+	{"cuu1", "cursor_up"},
+	{"cvvis", "cursor_visible"},
+	{"dch1", "delete_character"},
+	{"dl1", "delete_line"},
+	{"dsl", "dis_status_line"},
+	{"hd", "down_half_line"},
+	{"smacs", "enter_alt_charset_mode"},
+	{"blink", "enter_blink_mode"},
+	{"bold", "enter_bold_mode"},
+	{"smcup", "enter_ca_mode"},
+	{"smdc", "enter_delete_mode"},
+	{"dim", "enter_dim_mode"},
+	{"smir", "enter_insert_mode"},
+	{"invis", "enter_secure_mode"},
+	{"prot", "enter_protected_mode"},
+	{"rev", "enter_reverse_mode"},
+	{"smso", "enter_standout_mode"},
+	{"smul", "enter_underline_mode"},
+	{"ech", "erase_chars"},
+	{"rmacs", "exit_alt_charset_mode"},
+	{"sgr0", "exit_attribute_mode"},
+	{"rmcup", "exit_ca_mode"},
+	{"rmdc", "exit_delete_mode"},
+	{"rmir", "exit_insert_mode"},
+	{"rmso", "exit_standout_mode"},
+	{"rmul", "exit_underline_mode"},
+	{"flash", "flash_screen"},
+	{"ff", "form_feed"},
+	{"fsl", "from_status_line"},
+	{"is1", "init_1string"},
+	{"is2", "init_2string"},
+	{"is3", "init_3string"},
+	{"if", "init_file"},
+	{"ich1", "insert_character"},
+	{"il1", "insert_line"},
+	{"ip", "insert_padding"},
+	{"kbs", "key_backspace"},
+	{"ktbc", "key_catab"},
+	{"kclr", "key_clear"},
+	{"kctab", "key_ctab"},
+	{"kdch1", "key_dc"},
+	{"kdl1", "key_dl"},
+	{"kcud1", "key_down"},
+	{"krmir", "key_eic"},
+	{"kel", "key_eol"},
+	{"ked", "key_eos"},
+	{"kf0", "key_f0"},
+	{"kf1", "key_f1"},
+	{"kf10", "key_f10"},
+	{"kf2", "key_f2"},
+	{"kf3", "key_f3"},
+	{"kf4", "key_f4"},
+	{"kf5", "key_f5"},
+	{"kf6", "key_f6"},
+	{"kf7", "key_f7"},
+	{"kf8", "key_f8"},
+	{"kf9", "key_f9"},
+	{"khome", "key_home"},
+	{"kich1", "key_ic"},
+	{"kil1", "key_il"},
+	{"kcub1", "key_left"},
+	{"kll", "key_ll"},
+	{"knp", "key_npage"},
+	{"kpp", "key_ppage"},
+	{"kcuf1", "key_right"},
+	{"kind", "key_sf"},
+	{"kri", "key_sr"},
+	{"khts", "key_stab"},
+	{"kcuu1", "key_up"},
+	{"rmkx", "keypad_local"},
+	{"smkx", "keypad_xmit"},
+	{"lf0", "lab_f0"},
+	{"lf1", "lab_f1"},
+	{"lf10", "lab_f10"},
+	{"lf2", "lab_f2"},
+	{"lf3", "lab_f3"},
+	{"lf4", "lab_f4"},
+	{"lf5", "lab_f5"},
+	{"lf6", "lab_f6"},
+	{"lf7", "lab_f7"},
+	{"lf8", "lab_f8"},
+	{"lf9", "lab_f9"},
+	{"rmm", "meta_off"},
+	{"smm", "meta_on"},
+	{"nel", "newline"},
+	{"pad", "pad_char"},
+	{"dch", "parm_dch"},
+	{"dl", "parm_delete_line"},
+	{"cud", "parm_down_cursor"},
+	{"ich", "parm_ich"},
+	{"indn", "parm_index"},
+	{"il", "parm_insert_line"},
+	{"cub", "parm_left_cursor"},
+	{"cuf", "parm_right_cursor"},
+	{"rin", "parm_rindex"},
+	{"cuu", "parm_up_cursor"},
+	{"pfkey", "pkey_key"},
+	{"pfloc", "pkey_local"},
+	{"pfx", "pkey_xmit"},
+	{"mc0", "print_screen"},
+	{"mc4", "prtr_off"},
+	{"mc5", "prtr_on"},
+	{"rep", "repeat_char"},
+	{"rs1", "reset_1string"},
+	{"rs2", "reset_2string"},
+	{"rs3", "reset_3string"},
+	{"rf", "reset_file"},
+	{"rc", "restore_cursor"},
+	{"vpa", "row_address"},
+	{"sc", "save_cursor"},
+	{"ind", "scroll_forward"},
+	{"ri", "scroll_reverse"},
+	{"sgr", "set_attributes"},
+	{"hts", "set_tab"},
+	{"wind", "set_window"},
+	{"ht", "tab"},
+	{"tsl", "to_status_line"},
+	{"uc", "underline_char"},
+	{"hu", "up_half_line"},
+	{"iprog", "init_prog"},
+	{"ka1", "key_a1"},
+	{"ka3", "key_a3"},
+	{"kb2", "key_b2"},
+	{"kc1", "key_c1"},
+	{"kc3", "key_c3"},
+	{"mc5p", "prtr_non"},
+	{"rmp", "char_padding"},
+	{"acsc", "acs_chars"},
+	{"pln", "plab_norm"},
+	{"kcbt", "key_btab"},
+	{"smxon", "enter_xon_mode"},
+	{"rmxon", "exit_xon_mode"},
+	{"smam", "enter_am_mode"},
+	{"rmam", "exit_am_mode"},
+	{"xonc", "xon_character"},
+	{"xoffc", "xoff_character"},
+	{"enacs", "ena_acs"},
+	{"smln", "label_on"},
+	{"rmln", "label_off"},
+	{"kbeg", "key_beg"},
+	{"kcan", "key_cancel"},
+	{"kclo", "key_close"},
+	{"kcmd", "key_command"},
+	{"kcpy", "key_copy"},
+	{"kcrt", "key_create"},
+	{"kend", "key_end"},
+	{"kent", "key_enter"},
+	{"kext", "key_exit"},
+	{"kfnd", "key_find"},
+	{"khlp", "key_help"},
+	{"kmrk", "key_mark"},
+	{"kmsg", "key_message"},
+	{"kmov", "key_move"},
+	{"knxt", "key_next"},
+	{"kopn", "key_open"},
+	{"kopt", "key_options"},
+	{"kprv", "key_previous"},
+	{"kprt", "key_print"},
+	{"krdo", "key_redo"},
+	{"kref", "key_reference"},
+	{"krfr", "key_refresh"},
+	{"krpl", "key_replace"},
+	{"krst", "key_restart"},
+	{"kres", "key_resume"},
+	{"ksav", "key_save"},
+	{"kspd", "key_suspend"},
+	{"kund", "key_undo"},
+	{"kBEG", "key_sbeg"},
+	{"kCAN", "key_scancel"},
+	{"kCMD", "key_scommand"},
+	{"kCPY", "key_scopy"},
+	{"kCRT", "key_screate"},
+	{"kDC", "key_sdc"},
+	{"kDL", "key_sdl"},
+	{"kslt", "key_select"},
+	{"kEND", "key_send"},
+	{"kEOL", "key_seol"},
+	{"kEXT", "key_sexit"},
+	{"kFND", "key_sfind"},
+	{"kHLP", "key_shelp"},
+	{"kHOM", "key_shome"},
+	{"kIC", "key_sic"},
+	{"kLFT", "key_sleft"},
+	{"kMSG", "key_smessage"},
+	{"kMOV", "key_smove"},
+	{"kNXT", "key_snext"},
+	{"kOPT", "key_soptions"},
+	{"kPRV", "key_sprevious"},
+	{"kPRT", "key_sprint"},
+	{"kRDO", "key_sredo"},
+	{"kRPL", "key_sreplace"},
+	{"kRIT", "key_sright"},
+	{"kRES", "key_srsume"},
+	{"kSAV", "key_ssave"},
+	{"kSPD", "key_ssuspend"},
+	{"kUND", "key_sundo"},
+	{"rfi", "req_for_input"},
+	{"kf11", "key_f11"},
+	{"kf12", "key_f12"},
+	{"kf13", "key_f13"},
+	{"kf14", "key_f14"},
+	{"kf15", "key_f15"},
+	{"kf16", "key_f16"},
+	{"kf17", "key_f17"},
+	{"kf18", "key_f18"},
+	{"kf19", "key_f19"},
+	{"kf20", "key_f20"},
+	{"kf21", "key_f21"},
+	{"kf22", "key_f22"},
+	{"kf23", "key_f23"},
+	{"kf24", "key_f24"},
+	{"kf25", "key_f25"},
+	{"kf26", "key_f26"},
+	{"kf27", "key_f27"},
+	{"kf28", "key_f28"},
+	{"kf29", "key_f29"},
+	{"kf30", "key_f30"},
+	{"kf31", "key_f31"},
+	{"kf32", "key_f32"},
+	{"kf33", "key_f33"},
+	{"kf34", "key_f34"},
+	{"kf35", "key_f35"},
+	{"kf36", "key_f36"},
+	{"kf37", "key_f37"},
+	{"kf38", "key_f38"},
+	{"kf39", "key_f39"},
+	{"kf40", "key_f40"},
+	{"kf41", "key_f41"},
+	{"kf42", "key_f42"},
+	{"kf43", "key_f43"},
+	{"kf44", "key_f44"},
+	{"kf45", "key_f45"},
+	{"kf46", "key_f46"},
+	{"kf47", "key_f47"},
+	{"kf48", "key_f48"},
+	{"kf49", "key_f49"},
+	{"kf50", "key_f50"},
+	{"kf51", "key_f51"},
+	{"kf52", "key_f52"},
+	{"kf53", "key_f53"},
+	{"kf54", "key_f54"},
+	{"kf55", "key_f55"},
+	{"kf56", "key_f56"},
+	{"kf57", "key_f57"},
+	{"kf58", "key_f58"},
+	{"kf59", "key_f59"},
+	{"kf60", "key_f60"},
+	{"kf61", "key_f61"},
+	{"kf62", "key_f62"},
+	{"kf63", "key_f63"},
+	{"el1", "clr_bol"},
+	{"mgc", "clear_margins"},
+	{"smgl", "set_left_margin"},
+	{"smgr", "set_right_margin"},
+	{"fln", "label_format"},
+	{"sclk", "set_clock"},
+	{"dclk", "display_clock"},
+	{"rmclk", "remove_clock"},
+	{"cwin", "create_window"},
+	{"wingo", "goto_window"},
+	{"hup", "hangup"},
+	{"dial", "dial_phone"},
+	{"qdial", "quick_dial"},
+	{"tone", "tone"},
+	{"pulse", "pulse"},
+	{"hook", "flash_hook"},
+	{"pause", "fixed_pause"},
+	{"wait", "wait_tone"},
+	{"u0", "user0"},
+	{"u1", "user1"},
+	{"u2", "user2"},
+	{"u3", "user3"},
+	{"u4", "user4"},
+	{"u5", "user5"},
+	{"u6", "user6"},
+	{"u7", "user7"},
+	{"u8", "user8"},
+	{"u9", "user9"},
+	{"op", "orig_pair"},
+	{"oc", "orig_colors"},
+	{"initc", "initialize_color"},
+	{"initp", "initialize_pair"},
+	{"scp", "set_color_pair"},
+	{"setf", "set_foreground"},
+	{"setb", "set_background"},
+	{"cpi", "change_char_pitch"},
+	{"lpi", "change_line_pitch"},
+	{"chr", "change_res_horz"},
+	{"cvr", "change_res_vert"},
+	{"defc", "define_char"},
+	{"swidm", "enter_doublewide_mode"},
+	{"sdrfq", "enter_draft_quality"},
+	{"sitm", "enter_italics_mode"},
+	{"slm", "enter_leftward_mode"},
+	{"smicm", "enter_micro_mode"},
+	{"snlq", "enter_near_letter_quality"},
+	{"snrmq", "enter_normal_quality"},
+	{"sshm", "enter_shadow_mode"},
+	{"ssubm", "enter_subscript_mode"},
+	{"ssupm", "enter_superscript_mode"},
+	{"sum", "enter_upward_mode"},
+	{"rwidm", "exit_doublewide_mode"},
+	{"ritm", "exit_italics_mode"},
+	{"rlm", "exit_leftward_mode"},
+	{"rmicm", "exit_micro_mode"},
+	{"rshm", "exit_shadow_mode"},
+	{"rsubm", "exit_subscript_mode"},
+	{"rsupm", "exit_superscript_mode"},
+	{"rum", "exit_upward_mode"},
+	{"mhpa", "micro_column_address"},
+	{"mcud1", "micro_down"},
+	{"mcub1", "micro_left"},
+	{"mcuf1", "micro_right"},
+	{"mvpa", "micro_row_address"},
+	{"mcuu1", "micro_up"},
+	{"porder", "order_of_pins"},
+	{"mcud", "parm_down_micro"},
+	{"mcub", "parm_left_micro"},
+	{"mcuf", "parm_right_micro"},
+	{"mcuu", "parm_up_micro"},
+	{"scs", "select_char_set"},
+	{"smgb", "set_bottom_margin"},
+	{"smgbp", "set_bottom_margin_parm"},
+	{"smglp", "set_left_margin_parm"},
+	{"smgrp", "set_right_margin_parm"},
+	{"smgt", "set_top_margin"},
+	{"smgtp", "set_top_margin_parm"},
+	{"sbim", "start_bit_image"},
+	{"scsd", "start_char_set_def"},
+	{"rbim", "stop_bit_image"},
+	{"rcsd", "stop_char_set_def"},
+	{"subcs", "subscript_characters"},
+	{"supcs", "superscript_characters"},
+	{"docr", "these_cause_cr"},
+	{"zerom", "zero_motion"},
+	{"csnm", "char_set_names"},
+	{"kmous", "key_mouse"},
+	{"minfo", "mouse_info"},
+	{"reqmp", "req_mouse_pos"},
+	{"getm", "get_mouse"},
+	{"setaf", "set_a_foreground"},
+	{"setab", "set_a_background"},
+	{"pfxl", "pkey_plab"},
+	{"devt", "device_type"},
+	{"csin", "code_set_init"},
+	{"s0ds", "set0_des_seq"},
+	{"s1ds", "set1_des_seq"},
+	{"s2ds", "set2_des_seq"},
+	{"s3ds", "set3_des_seq"},
+	{"smglr", "set_lr_margin"},
+	{"smgtb", "set_tb_margin"},
+	{"birep", "bit_image_repeat"},
+	{"binel", "bit_image_newline"},
+	{"bicr", "bit_image_carriage_return"},
+	{"colornm", "color_names"},
+	{"defbi", "define_bit_image_region"},
+	{"endbi", "end_bit_image_region"},
+	{"setcolor", "set_color_band"},
+	{"slines", "set_page_length"},
+	{"dispc", "display_pc_char"},
+	{"smpch", "enter_pc_charset_mode"},
+	{"rmpch", "exit_pc_charset_mode"},
+	{"smsc", "enter_scancode_mode"},
+	{"rmsc", "exit_scancode_mode"},
+	{"pctrm", "pc_term_options"},
+	{"scesc", "scancode_escape"},
+	{"scesa", "alt_scancode_esc"},
+	{"ehhlm", "enter_horizontal_hl_mode"},
+	{"elhlm", "enter_left_hl_mode"},
+	{"elohlm", "enter_low_hl_mode"},
+	{"erhlm", "enter_right_hl_mode"},
+	{"ethlm", "enter_top_hl_mode"},
+	{"evhlm", "enter_vertical_hl_mode"},
+	{"sgr1", "set_a_attributes"},
+	{"slength", "set_pglen_inch"},
+	{"OTi2", "termcap_init2"},
+	{"OTrs", "termcap_reset"},
+	{"OTnl", "linefeed_if_not_lf"},
+	{"OTbc", "backspace_if_not_bs"},
+	{"OTko", "other_non_function_keys"},
+	{"OTma", "arrow_key_map"},
+	{"OTG2", "acs_ulcorner"},
+	{"OTG3", "acs_llcorner"},
+	{"OTG1", "acs_urcorner"},
+	{"OTG4", "acs_lrcorner"},
+	{"OTGR", "acs_ltee"},
+	{"OTGL", "acs_rtee"},
+	{"OTGU", "acs_btee"},
+	{"OTGD", "acs_ttee"},
+	{"OTGH", "acs_hline"},
+	{"OTGV", "acs_vline"},
+	{"OTGC", "acs_plus"},
+	{"meml", "memory_lock"},
+	{"memu", "memory_unlock"},
+	{"box1", "box_chars_1"},
+}
+
+// Name returns the capability's terminfo name, e.g. "bel".
+func (c StrCap) Name() string { return strCapNames[c].name }
+
+// LongName returns the capability's long (C variable) name, e.g. "bell".
+func (c StrCap) LongName() string { return strCapNames[c].longName }
+
+func (c StrCap) String() string { return c.Name() }
@@ -1,13 +1,21 @@
-/*Package terminfo contains a simple and incomplete implementation of the
-terminfo database. Information was taken from the ncurses manpages term(5) and
-terminfo(5). Currently, only the string capabilities for special keys and for
-functions without parameters are actually used. Colors are still done with ANSI
-escape sequences. Other special features that are not (yet?) supported are
-reading from ~/.terminfo, the TERMINFO_DIRS variable, Berkeley database format
-and extended capabilities.
+/*Package terminfo contains an implementation of the terminfo database.
+Information was taken from the ncurses manpages term(5) and terminfo(5).
+
+Compiled terminfo entries are loaded from ~/.terminfo, the TERMINFO_DIRS
+variable, or the default system directory; every standard boolean, numeric,
+and string capability is read, and made available by typed accessors (e.g.
+Terminfo.Str(StrCursorAddress)) or by name (e.g. Terminfo.StrNamed("cup")).
+Extended capabilities, like "Tc" or "Ss", are also read, and available by name.
+Both the legacy format and the ncurses 32-bit number format are supported;
+Berkeley database format is not (yet?) supported.
+The Keys and Funcs arrays provide quick access to commonly used strings.
+Parameterized strings, like cup or setaf, may be evaluated by Terminfo.Param.
+
+Entries may also be loaded from terminfo source files, as written by infocmp(1),
+with LoadSource or ReadSource, and compiled by Terminfo.WriteTo; this allows
+custom entries to be maintained without depending on tic(1).
 
 It is currently in the process of evolving out of termbox, and will become more
 complete over time.
-
 */
 package terminfo
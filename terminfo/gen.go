@@ -0,0 +1,21 @@
+package terminfo
+
+// The builtin_*_terminfo.go files are generated from entries in builtins.src,
+// which may be refreshed by dumping entries with ncurses infocmp -x; the
+// arguments after each entry name are partial names added to the compat
+// table, see GetBuiltin.
+
+//go:generate go run ../cmd/mkterminfo -src builtins.src Eterm Eterm
+//go:generate go run ../cmd/mkterminfo -src builtins.src linux linux
+//go:generate go run ../cmd/mkterminfo -src builtins.src rxvt-256color
+//go:generate go run ../cmd/mkterminfo -src builtins.src rxvt-unicode rxvt
+//go:generate go run ../cmd/mkterminfo -src builtins.src screen screen
+//go:generate go run ../cmd/mkterminfo -src builtins.src screen-256color
+//go:generate go run ../cmd/mkterminfo -src builtins.src xterm xterm cygwin st
+//go:generate go run ../cmd/mkterminfo -src builtins.src xterm-256color
+//go:generate go run ../cmd/mkterminfo -src builtins.src tmux-256color tmux
+//go:generate go run ../cmd/mkterminfo -src builtins.src alacritty alacritty
+//go:generate go run ../cmd/mkterminfo -src builtins.src kitty kitty
+//go:generate go run ../cmd/mkterminfo -src builtins.src foot foot
+//go:generate go run ../cmd/mkterminfo -src builtins.src wezterm wezterm
+//go:generate go run ../cmd/mkterminfo -src builtins.src vte-256color vte
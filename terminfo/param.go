@@ -0,0 +1,539 @@
+package terminfo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var errNoCap = errors.New("no such string capability")
+
+// Param evaluates the named string capability (standard or extended), e.g.
+// "cup" or "Ss", with the given parameters; see Tparm.
+func (info *Terminfo) Param(name string, args ...interface{}) (string, error) {
+	s, ok := info.StrNamed(name)
+	if !ok {
+		return "", fmt.Errorf("%w %q", errNoCap, name)
+	}
+	return Tparm(s, args...)
+}
+
+// Tparm evaluates a parameterized string, like those found in cup or setaf
+// capabilities, implementing the stack language described in terminfo(5).
+//
+// Parameters may be given as any integer type or as a string; up to 9 are
+// used, with any missing ones taken to be 0. Static variables (%P[A-Z]) start
+// out as 0 on every call, since Tparm retains no state between calls.
+//
+// Evaluation follows ncurses tiparm(3) quirk for quirk: malformed or unknown
+// directives are skipped rather than failing, numbers wrap like C ints, and
+// the stack only holds 20 values. In particular, a string that never pushes a
+// parameter with %p (e.g. a termcap-style "\E[%i%d;%dR") starts with the
+// parameters it pops already pushed (up to two, the first one on top; see
+// implicitParams); %i then increments the bottom two stack entries too (the
+// first parameter into the bottom one, the second into the one above).
+func Tparm(s string, args ...interface{}) (string, error) {
+	var ev paramEval
+	for i := 0; i < len(args) && i < len(ev.params); i++ {
+		v, err := makeParamValue(args[i])
+		if err != nil {
+			return "", err
+		}
+		ev.params[i] = v
+	}
+	if n, ok := implicitParams(s); ok {
+		ev.implicit = true
+		for i := n; i < len(ev.params); i++ {
+			ev.params[i] = paramValue{}
+		}
+		for i := n - 1; i >= 0; i-- {
+			ev.pushParam(i)
+		}
+	}
+	ev.run(s)
+	return ev.out.String(), nil
+}
+
+// paramValue is a parameter, variable or stack value: either a number or a
+// string.
+type paramValue struct {
+	num   int
+	str   string
+	isStr bool
+}
+
+func makeParamValue(arg interface{}) (paramValue, error) {
+	switch v := arg.(type) {
+	case int:
+		return paramValue{num: v}, nil
+	case int8:
+		return paramValue{num: int(v)}, nil
+	case int16:
+		return paramValue{num: int(v)}, nil
+	case int32:
+		return paramValue{num: int(v)}, nil
+	case int64:
+		return paramValue{num: int(v)}, nil
+	case uint:
+		return paramValue{num: int(v)}, nil
+	case uint8:
+		return paramValue{num: int(v)}, nil
+	case uint16:
+		return paramValue{num: int(v)}, nil
+	case uint32:
+		return paramValue{num: int(v)}, nil
+	case uint64:
+		return paramValue{num: int(v)}, nil
+	case bool:
+		if v {
+			return paramValue{num: 1}, nil
+		}
+		return paramValue{}, nil
+	case string:
+		return paramValue{str: v, isStr: true}, nil
+	}
+	return paramValue{}, fmt.Errorf("unsupported parameter type %T", arg)
+}
+
+// int returns the value as a number; strings are taken to be 0.
+func (v paramValue) int() int {
+	if v.isStr {
+		return 0
+	}
+	return v.num
+}
+
+// string returns the value as a string; numbers are taken to be empty, as in
+// ncurses.
+func (v paramValue) string() string {
+	return v.str
+}
+
+// implicitParams analyzes s like ncurses does, returning how many parameters
+// it implicitly uses, or false if it pushes any with %p[1-9].
+//
+// ncurses counts pops (but not %P or %t) made while its estimate of the stack
+// depth is empty, up to 2 as for a termcap string; a binary operator only
+// counts once, while %s and %l only lower the estimate after a %p. It reads
+// only that many parameters, so any others are taken to be 0.
+func implicitParams(s string) (int, bool) {
+	n, level, lastPop, explicit := 0, -1, -1, false
+	count := func() {
+		if level < 0 && n < 2 {
+			n++
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		if i, _ = parseFormat(s, i+1); i >= len(s) {
+			break
+		}
+		switch s[i] {
+		case 'p':
+			if i++; i < len(s) && '0' <= s[i] && s[i] <= '9' {
+				level++
+				lastPop = int(s[i] - '0')
+				explicit = explicit || lastPop > 0
+			}
+		case 'P':
+			i++
+		case 'g':
+			i++
+			level++
+		case '\'':
+			i += 2
+			level++
+			lastPop = -1
+		case '{':
+			for i++; i < len(s) && '0' <= s[i] && s[i] <= '9'; i++ {
+			}
+			level++
+		case 'c', 'd', 'o', 'x', 'X':
+			if lastPop <= 0 {
+				count()
+			}
+			level--
+			lastPop = -1
+		case '+', '-', '*', '/', 'm', '&', '|', '^', '=', '>', '<', 'A', 'O':
+			count()
+			level--
+			lastPop = -1
+		case '!', '~':
+			count()
+			lastPop = -1
+		case 's', 'l':
+			if lastPop > 0 {
+				level--
+			}
+			count()
+		}
+	}
+	return n, !explicit
+}
+
+// maxParamStack is the depth of the ncurses stack; any further pushes are
+// dropped.
+const maxParamStack = 20
+
+type paramEval struct {
+	params   [9]paramValue
+	static   [26]paramValue
+	dynamic  [26]paramValue
+	stack    []paramValue
+	under    int  // string pops from an empty stack; see popString
+	implicit bool // parameters were pushed implicitly; see Tparm
+	out      strings.Builder
+}
+
+func (ev *paramEval) push(v paramValue) {
+	if ev.under > 0 {
+		ev.under--
+	} else if len(ev.stack) < maxParamStack {
+		ev.stack = append(ev.stack, v)
+	}
+}
+
+// pushInt pushes a number, truncated to 32-bits like a C int.
+func (ev *paramEval) pushInt(n int) { ev.push(paramValue{num: int(int32(n))}) }
+
+func (ev *paramEval) pushParam(i int) {
+	if v := ev.params[i]; v.isStr {
+		ev.push(v)
+	} else {
+		ev.pushInt(v.num)
+	}
+}
+
+// pop pops a value; popping one from an empty stack yields 0, and recovers
+// from any underflow (see popString).
+func (ev *paramEval) pop() paramValue {
+	i := len(ev.stack) - 1
+	if i < 0 {
+		ev.under = 0
+		return paramValue{}
+	}
+	v := ev.stack[i]
+	ev.stack = ev.stack[:i]
+	return v
+}
+
+func (ev *paramEval) popInt() int { return ev.pop().int() }
+
+// popString pops a string; as in ncurses, popping one from an empty stack
+// yields "", and underflows it, so that the next push is lost.
+func (ev *paramEval) popString() string {
+	if len(ev.stack) == 0 {
+		ev.under++
+		return ""
+	}
+	return ev.pop().string()
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (ev *paramEval) run(s string) {
+	incremented := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '%' {
+			ev.out.WriteByte(c)
+			continue
+		}
+		var spec string
+		if i, spec = parseFormat(s, i+1); i >= len(s) {
+			break
+		}
+		switch c = s[i]; c {
+		case '%':
+			ev.out.WriteByte('%')
+
+		case 'c':
+			n := ev.popInt()
+			if n == 0 {
+				// as in ncurses, since a NUL would terminate the string
+				// (though other multiples of 256 still produce one)
+				n = 0200
+			}
+			ev.out.WriteByte(byte(n))
+
+		case 'd', 'o', 'x', 'X', 's':
+			ev.format(spec, c)
+
+		case 'p':
+			// any following byte is taken, but only 1-9 push anything
+			if i++; i < len(s) && '1' <= s[i] && s[i] <= '9' {
+				ev.pushParam(int(s[i] - '1'))
+			}
+
+		case 'P', 'g':
+			if i++; i >= len(s) {
+				break
+			}
+			var v *paramValue
+			switch n := s[i]; {
+			case 'a' <= n && n <= 'z':
+				v = &ev.dynamic[n-'a']
+			case 'A' <= n && n <= 'Z':
+				v = &ev.static[n-'A']
+			default:
+				continue
+			}
+			if c == 'P' {
+				*v = paramValue{num: ev.popInt()}
+			} else {
+				ev.push(*v)
+			}
+
+		case '\'':
+			// the byte after the character is taken, whether or not it is
+			// the closing quote
+			if i++; i < len(s) {
+				ev.pushInt(int(s[i]))
+				i++
+			}
+
+		case '{':
+			// only unsigned digits are taken, along with the byte after them,
+			// whether or not it is the closing brace
+			var n int32
+			for i++; i < len(s) && '0' <= s[i] && s[i] <= '9'; i++ {
+				n = 10*n + int32(s[i]-'0')
+			}
+			ev.pushInt(int(n))
+
+		case 'l':
+			ev.pushInt(len(ev.popString()))
+
+		case '+', '-', '*', '/', 'm', '&', '|', '^', '=', '>', '<', 'A', 'O':
+			b, a := ev.popInt(), ev.popInt()
+			ev.pushInt(binaryOp(c, a, b))
+
+		case '!':
+			ev.pushInt(boolInt(ev.popInt() == 0))
+		case '~':
+			ev.pushInt(^ev.popInt())
+
+		case 'i':
+			// only once, and only to numeric parameters
+			if !incremented {
+				incremented = true
+				for j := 0; j < 2; j++ {
+					if !ev.params[j].isStr {
+						ev.params[j].num++
+						if ev.implicit && j < len(ev.stack) {
+							ev.stack[j] = paramValue{num: int(int32(ev.params[j].num))}
+						}
+					}
+				}
+			}
+
+		case 't':
+			if ev.popInt() == 0 {
+				// skip to the else part (if any) or end of the conditional
+				i = skipConditional(s, i+1, true)
+			}
+
+		case 'e':
+			// the then part of a conditional was taken, skip to its end
+			i = skipConditional(s, i+1, false)
+
+		default:
+			// conditionals begin (%?) and end (%;) without any effect, and
+			// unknown directives are ignored
+		}
+	}
+}
+
+func binaryOp(op byte, a, b int) int {
+	switch op {
+	case '+':
+		return a + b
+	case '-':
+		return a - b
+	case '*':
+		return a * b
+	case '/':
+		if b == 0 {
+			return 0
+		}
+		return a / b
+	case 'm':
+		if b == 0 {
+			return 0
+		}
+		return a % b
+	case '&':
+		return a & b
+	case '|':
+		return a | b
+	case '^':
+		return a ^ b
+	case '=':
+		return boolInt(a == b)
+	case '>':
+		return boolInt(a > b)
+	case '<':
+		return boolInt(a < b)
+	case 'A':
+		return boolInt(a != 0 && b != 0)
+	case 'O':
+		return boolInt(a != 0 || b != 0)
+	}
+	panic("inconceivable: unhandled binary op")
+}
+
+// skipConditional scans forward from s[i] past any nested conditionals,
+// returning the index of the final byte of the "%;" that ends the current
+// one; if orElse is true, an "%e" at the current level also ends the scan.
+// As in ncurses, an unterminated conditional simply runs to the end of s.
+func skipConditional(s string, i int, orElse bool) int {
+	depth := 0
+	for ; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		if i++; i >= len(s) {
+			break
+		}
+		switch s[i] {
+		case '?':
+			depth++
+		case ';':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		case 'e':
+			if depth == 0 && orElse {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
+
+// maxParamWidth limits format directive widths and precisions, like ncurses,
+// so that a malformed string can't cause an arbitrarily large allocation.
+const maxParamWidth = 10000
+
+// parseFormat scans any printf-style "[[:]flags][width[.precision]]" that
+// starts a directive at s[i], just after the '%', returning the index of the
+// directive's final byte (len(s) if there's none) and a fmt spec for it.
+//
+// As in ncurses, '-' is only taken as a flag after a ':', '+' is never taken
+// (it's the addition operator), and any invalid width or precision discards
+// the flags entirely.
+func parseFormat(s string, i int) (int, string) {
+	spec := []byte{'%'}
+	allowMinus, dot, invalid := false, false, false
+	n := 0
+scan:
+	for ; i < len(s); i++ {
+		switch c := s[i]; {
+		case strings.IndexByte("cdoxXs", c) >= 0:
+			break scan
+		case c == '.':
+			invalid = invalid || dot
+			dot, n = true, 0
+		case c == '#', c == ' ':
+		case c == ':':
+			allowMinus = true
+			continue
+		case c == '-':
+			if !allowMinus {
+				break scan
+			}
+		case '0' <= c && c <= '9':
+			if n <= maxParamWidth {
+				n = 10*n + int(c-'0')
+			}
+			invalid = invalid || n > maxParamWidth
+		default:
+			break scan
+		}
+		spec = append(spec, s[i])
+	}
+	if invalid {
+		return i, "%"
+	}
+	return i, string(spec)
+}
+
+// format handles a %[doxXs] directive with the given fmt spec.
+func (ev *paramEval) format(spec string, verb byte) {
+	var arg interface{}
+	switch verb {
+	case 'd':
+		arg = ev.popInt()
+	case 'o', 'x', 'X':
+		// as in C, the number is formatted as unsigned
+		arg = uint32(ev.popInt())
+	case 's':
+		arg = ev.popString()
+	}
+	if unknown, ok := unknownSpec(spec); ok {
+		ev.out.WriteString(unknown)
+		ev.out.WriteByte(verb)
+		return
+	}
+	switch verb {
+	case 'o', 'x', 'X':
+		// as in C, a space flag has no effect on unsigned numbers, and the
+		// alternate form adds no prefix to 0
+		spec = strings.Replace(spec, " ", "", -1)
+		if arg == uint32(0) {
+			spec = strings.Replace(spec, "#", "", -1)
+		}
+	}
+	fmt.Fprintf(&ev.out, spec+string(verb), arg)
+}
+
+// unknownSpec returns true if a fmt spec (without its verb) isn't valid for C
+// printf, e.g. when flags follow the width; glibc then writes the spec back
+// out in its own form (flags first, in its order), along with the rest of it,
+// and so does unknownSpec.
+func unknownSpec(spec string) (string, bool) {
+	var flags [4]bool
+	i := 1
+	for ; i < len(spec); i++ {
+		j := strings.IndexByte("# -0", spec[i])
+		if j < 0 {
+			break
+		}
+		flags[j] = true
+	}
+	width, prec := 0, -1
+	for ; i < len(spec) && '0' <= spec[i] && spec[i] <= '9'; i++ {
+		width = 10*width + int(spec[i]-'0')
+	}
+	if i < len(spec) && spec[i] == '.' {
+		for prec, i = 0, i+1; i < len(spec) && '0' <= spec[i] && spec[i] <= '9'; i++ {
+			prec = 10*prec + int(spec[i]-'0')
+		}
+	}
+	if i == len(spec) {
+		return "", false
+	}
+	var sb strings.Builder
+	sb.WriteByte('%')
+	for j, set := range flags {
+		if set {
+			sb.WriteByte("# -0"[j])
+		}
+	}
+	if width != 0 {
+		fmt.Fprint(&sb, width)
+	}
+	if prec >= 0 {
+		fmt.Fprintf(&sb, ".%d", prec)
+	}
+	sb.WriteString(spec[i:])
+	return sb.String(), true
+}
@@ -0,0 +1,263 @@
+package terminfo_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi/terminfo"
+)
+
+// expected outputs below were produced by ncurses tiparm(3)
+
+func TestTerminfo_Param(t *testing.T) {
+	for _, tc := range []struct {
+		term string
+		name string
+		args []interface{}
+		out  string
+	}{
+		{"xterm", "cup", []interface{}{5, 10}, "\x1b[6;11H"},
+		{"xterm", "setaf", []interface{}{1}, "\x1b[31m"},
+		{"xterm", "setab", []interface{}{3}, "\x1b[43m"},
+		{"xterm", "csr", []interface{}{0, 23}, "\x1b[1;24r"},
+		{"xterm", "cub", []interface{}{3}, "\x1b[3D"},
+		{"xterm", "ech", []interface{}{4}, "\x1b[4X"},
+		{"xterm", "sgr", []interface{}{0, 1, 0, 0, 0, 1, 0, 0, 0}, "\x1b(B\x1b[0;1;4m"},
+		{"xterm", "sgr", []interface{}{1, 0, 1, 1, 1, 0, 1, 0, 1}, "\x1b(0\x1b[0;2;7;5;8m"},
+		{"xterm", "sgr", []interface{}{0, 0, 0, 0, 0, 0, 0, 0, 0}, "\x1b(B\x1b[0m"},
+		{"xterm", "XM", []interface{}{1}, "\x1b[?1006;1000h"},
+		{"xterm", "XM", []interface{}{0}, "\x1b[?1006;1000l"},
+		{"xterm", "Ss", []interface{}{4}, "\x1b[4 q"},
+		{"xterm", "Ms", []interface{}{"c", "aGk="}, "\x1b]52;c;aGk=\a"},
+		{"xterm-256color", "setaf", []interface{}{9}, "\x1b[91m"},
+		{"xterm-256color", "setaf", []interface{}{200}, "\x1b[38;5;200m"},
+		{"xterm-256color", "setab", []interface{}{15}, "\x1b[107m"},
+		{"xterm-256color", "setab", []interface{}{3}, "\x1b[43m"},
+		{"xterm-256color", "initc", []interface{}{1, 500, 250, 1000}, "\x1b]4;1;rgb:7F/3F/FF\x1b\x5c"},
+		{"tmux-256color", "Smulx", []interface{}{3}, "\x1b[4:3m"},
+		{"tmux-256color", "setaf", []interface{}{100}, "\x1b[38;5;100m"},
+	} {
+		t.Run(tc.term+"/"+tc.name, func(t *testing.T) {
+			ti := readTestdata(t, tc.term)
+			out, err := ti.Param(tc.name, tc.args...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.out, out)
+		})
+	}
+
+	ti := readTestdata(t, "xterm")
+	_, err := ti.Param("Smulx", 3)
+	assert.Error(t, err, "expected absent capability error")
+}
+
+var tparmTestCases = []struct {
+	s    string
+	args []interface{}
+	out  string
+}{
+	{"%p1%p2%+%d", []interface{}{3, 4}, "7"},
+	{"%p1%p2%-%d", []interface{}{3, 4}, "-1"},
+	{"%p1%p2%*%d", []interface{}{3, 4}, "12"},
+	{"%p1%p2%/%d", []interface{}{7, 2}, "3"},
+	{"%p1%p2%/%d", []interface{}{7, 0}, "0"},
+	{"%p1%p2%m%d", []interface{}{7, 3}, "1"},
+	{"%p1%p2%&%d", []interface{}{6, 3}, "2"},
+	{"%p1%p2%|%d", []interface{}{6, 3}, "7"},
+	{"%p1%p2%^%d", []interface{}{6, 3}, "5"},
+	{"%p1%p2%=%d", []interface{}{3, 3}, "1"},
+	{"%p1%p2%>%d", []interface{}{4, 3}, "1"},
+	{"%p1%p2%<%d", []interface{}{4, 3}, "0"},
+	{"%p1%p2%A%d", []interface{}{4, 0}, "0"},
+	{"%p1%p2%O%d", []interface{}{4, 0}, "1"},
+	{"%p1%!%d", []interface{}{0}, "1"},
+	{"%p1%~%d", []interface{}{5}, "-6"},
+	{"%{65}%c%'B'%c", []interface{}{}, "AB"},
+	{"%'A'%d", []interface{}{}, "65"},
+	{"%p1%PA%gA%gA%+%d", []interface{}{21}, "42"},
+	{"%p1%Pz%gz%d%gy%d", []interface{}{9}, "90"},
+	{"%?%p1%{1}%=%tone%e%p1%{2}%=%ttwo%eother%;", []interface{}{1}, "one"},
+	{"%?%p1%{1}%=%tone%e%p1%{2}%=%ttwo%eother%;", []interface{}{2}, "two"},
+	{"%?%p1%{1}%=%tone%e%p1%{2}%=%ttwo%eother%;", []interface{}{3}, "other"},
+	{"%?%p1%t%?%p2%tA%eB%;%eC%;", []interface{}{1, 0}, "B"},
+	{"%?%p1%t%?%p2%tA%eB%;%eC%;", []interface{}{0, 1}, "C"},
+	{"[%p1%:-5d]", []interface{}{42}, "[42   ]"},
+	{"[%p1%05d]", []interface{}{42}, "[00042]"},
+	{"[%p1%5.3d]", []interface{}{7}, "[  007]"},
+	{"%p1%x %p1%X %p1%o %p1%#x %p1%#o", []interface{}{255}, "ff FF 377 0xff 0377"},
+	{"%p1%x", []interface{}{-1}, "ffffffff"},
+	{"%p1%#x", []interface{}{0}, "0"},
+	{"%i%p1%d;%p2%d", []interface{}{0, 0}, "1;1"},
+	{"100%%", []interface{}{}, "100%"},
+	{"%p1%d%d", []interface{}{5}, "50"},
+
+	// malformed strings are evaluated as well as ncurses can manage
+	{"%", []interface{}{1}, ""},
+	{"%p", []interface{}{1}, ""},
+	{"%p0", []interface{}{1}, ""},
+	{"%Q", []interface{}{1}, ""},
+	{"%{12", []interface{}{1}, ""},
+	{"%'a", []interface{}{1}, ""},
+	{"%99999d", []interface{}{1}, "1"},
+	{"x%y%[z", []interface{}{1}, "xz"},
+	{"%'\x80'%'\x80'%*%c", []interface{}{}, "\x00"},
+	{"%p1%1#x %p1%. d", []interface{}{5}, "%1#x %.0 d"},
+	{"%s%s%p1%d%p1%d", []interface{}{7}, "07"},
+	{"%s%p1%p1%d%d", []interface{}{7}, "70"},
+}
+
+func TestTparm(t *testing.T) {
+	for _, tc := range tparmTestCases {
+		t.Run(tc.s, func(t *testing.T) {
+			out, err := terminfo.Tparm(tc.s, tc.args...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.out, out)
+		})
+	}
+
+	_, err := terminfo.Tparm("%p1%d", 1.5)
+	assert.Error(t, err, "expected unsupported parameter error")
+}
+
+var tiparmFlag = flag.String("tiparm", "",
+	"path to a built testdata/tiparm.c, to compare fuzzed Tparm results against")
+
+type tiparmCase struct {
+	s    string
+	args [9]int
+	out  string
+	ok   bool
+}
+
+// readTiparmCases reads testdata/tiparm.txt, keyed by string and parameters.
+func readTiparmCases(tb testing.TB) (cases []tiparmCase) {
+	f, err := os.Open("testdata/tiparm.txt")
+	require.NoError(tb, err)
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		if sc.Text() == "" || strings.HasPrefix(sc.Text(), "#") {
+			continue
+		}
+		fields := strings.Split(sc.Text(), "\t")
+		require.Len(tb, fields, 3, "tiparm.txt:%v", line)
+		var tc tiparmCase
+		tc.s, err = strconv.Unquote(fields[0])
+		require.NoError(tb, err, "tiparm.txt:%v", line)
+		for i, arg := range strings.Fields(fields[1]) {
+			tc.args[i], err = strconv.Atoi(arg)
+			require.NoError(tb, err, "tiparm.txt:%v", line)
+		}
+		if fields[2] != "NULL" {
+			tc.out, err = strconv.Unquote(fields[2])
+			require.NoError(tb, err, "tiparm.txt:%v", line)
+			tc.ok = true
+		}
+		cases = append(cases, tc)
+	}
+	require.NoError(tb, sc.Err())
+	return cases
+}
+
+func (tc tiparmCase) tparm() (string, error) {
+	args := make([]interface{}, len(tc.args))
+	for i, arg := range tc.args {
+		args[i] = arg
+	}
+	return terminfo.Tparm(tc.s, args...)
+}
+
+func (tc tiparmCase) check(t *testing.T) {
+	out, err := tc.tparm()
+	if i := strings.IndexByte(out, 0); i >= 0 {
+		out = out[:i] // tiparm returns a C string
+	}
+	if tc.ok {
+		if assert.NoError(t, err, "%q %v", tc.s, tc.args) {
+			assert.Equal(t, tc.out, out, "%q %v", tc.s, tc.args)
+		}
+	} else {
+		assert.Error(t, err, "%q %v expected error, like ncurses", tc.s, tc.args)
+	}
+}
+
+func TestTparm_ncurses(t *testing.T) {
+	for _, tc := range readTiparmCases(t) {
+		tc.check(t)
+	}
+}
+
+// runTiparm evaluates a case with a tiparm.c program.
+func runTiparm(t *testing.T, path string, tc *tiparmCase) {
+	var in bytes.Buffer
+	in.WriteString(hex.EncodeToString([]byte(tc.s)))
+	for _, arg := range tc.args {
+		fmt.Fprintf(&in, " %d", arg)
+	}
+	in.WriteByte('\n')
+	cmd := exec.Command(path)
+	cmd.Stdin = &in
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	tc.out, tc.ok = "", false
+	if res := strings.TrimSpace(string(out)); res != "NULL" {
+		b, err := hex.DecodeString(res)
+		require.NoError(t, err)
+		tc.out, tc.ok = string(b), true
+	}
+}
+
+func FuzzTparm(f *testing.F) {
+	known := make(map[[2]interface{}]tiparmCase)
+	for _, tc := range readTiparmCases(f) {
+		known[[2]interface{}{tc.s, tc.args}] = tc
+		a := tc.args
+		f.Add(tc.s, a[0], a[1], a[2], a[3], a[4], a[5], a[6], a[7], a[8])
+	}
+	f.Fuzz(func(t *testing.T, s string, a1, a2, a3, a4, a5, a6, a7, a8, a9 int) {
+		tc := tiparmCase{s: s, args: [9]int{a1, a2, a3, a4, a5, a6, a7, a8, a9}}
+		if rec, isKnown := known[[2]interface{}{tc.s, tc.args}]; isKnown {
+			rec.check(t)
+		} else if *tiparmFlag != "" && len(s) < 4096 && !strings.ContainsRune(s, 0) && !tiparmStrParam(s) {
+			runTiparm(t, *tiparmFlag, &tc)
+			tc.check(t)
+		} else if _, err := tc.tparm(); err != nil {
+			t.Skip("invalid, with nothing to compare against")
+		}
+	})
+}
+
+// tiparmStrParam returns true if tiparm would read a string parameter for s,
+// which tiparm.c can't provide: that's any parameter pushed with %p that a
+// later %s or %l pops, if no conversion, quote or arithmetic comes between.
+func tiparmStrParam(s string) bool {
+	pushed := false
+	for i := 0; i < len(s)-1; i++ {
+		if s[i] != '%' {
+			continue
+		}
+		for i++; i < len(s)-1 && strings.IndexByte(":-# .0123456789", s[i]) >= 0; i++ {
+		}
+		switch c := s[i]; {
+		case c == 'p':
+			pushed = true
+		case c == 's' || c == 'l':
+			if pushed {
+				return true
+			}
+		case strings.IndexByte("doxXc'+-*/m&|^=<>AO", c) >= 0:
+			pushed = false
+		}
+	}
+	return false
+}
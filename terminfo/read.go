@@ -1,71 +1,154 @@
 package terminfo
 
 import (
+	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 )
 
 var (
 	tiMouseEnter = "\x1b[?1000h\x1b[?1002h\x1b[?1015h\x1b[?1006h"
 	tiMouseLeave = "\x1b[?1006l\x1b[?1015l\x1b[?1002l\x1b[?1000l"
 
-	// "Maps" the Key* constants from terminfo.go to the number of the
-	// respective string capability in the terminfo file.
-	tiKeys = [maxKeys]uint16{
-		0,   // XXX invalid
-		66,  // KeyF1
-		68,  // KeyF2 NOTE not a typo; 67 is F10
-		69,  // KeyF3
-		70,  // KeyF4
-		71,  // KeyF5
-		72,  // KeyF6
-		73,  // KeyF7
-		74,  // KeyF8
-		75,  // KeyF9
-		67,  // KeyF10
-		216, // KeyF11
-		217, // KeyF12
-		77,  // KeyInsert
-		59,  // KeyDelete
-		76,  // KeyHome
-		164, // KeyEnd
-		82,  // KeyPgup
-		81,  // KeyPgdn
-		87,  // KeyArrowUp
-		61,  // KeyArrowDown
-		79,  // KeyArrowLeft
-		83,  // KeyArrowRight
-	}
-
-	// "Maps" the Func* constants from terminfo.go to the number of the
-	// respective string capability in the terminfo file.
-	tiFuncs = [maxFuncs - 2]uint16{
-		0,  // XXX invalid
-		28, // FuncEnterCA
-		40, // FuncExitCA
-		16, // FuncShowCursor
-		13, // FuncHideCursor
-		5,  // FuncClearScreen
-		39, // FuncSGR0
-		36, // FuncUnderline
-		27, // FuncBold
-		26, // FuncBlink
-		34, // FuncReverse
-		89, // FuncEnterKeypad
-		88, // FuncExitKeypad
+	// Maps the Key* constants from terminfo.go to their respective string
+	// capability.
+	tiKeys = [maxKeys]StrCap{
+		-1,          // XXX invalid
+		StrKeyF1,    // KeyF1
+		StrKeyF2,    // KeyF2
+		StrKeyF3,    // KeyF3
+		StrKeyF4,    // KeyF4
+		StrKeyF5,    // KeyF5
+		StrKeyF6,    // KeyF6
+		StrKeyF7,    // KeyF7
+		StrKeyF8,    // KeyF8
+		StrKeyF9,    // KeyF9
+		StrKeyF10,   // KeyF10
+		StrKeyF11,   // KeyF11
+		StrKeyF12,   // KeyF12
+		StrKeyIc,    // KeyInsert
+		StrKeyDc,    // KeyDelete
+		StrKeyHome,  // KeyHome
+		StrKeyEnd,   // KeyEnd
+		StrKeyPpage, // KeyPageUp
+		StrKeyNpage, // KeyPageDown
+		StrKeyUp,    // KeyUp
+		StrKeyDown,  // KeyDown
+		StrKeyLeft,  // KeyLeft
+		StrKeyRight, // KeyRight
+	}
+
+	// Maps the Func* constants from terminfo.go to their respective string
+	// capability; the mouse functions have no terminfo counterpart.
+	tiFuncs = [maxFuncs - 2]StrCap{
+		-1,                    // XXX invalid
+		StrEnterCAMode,        // FuncEnterCA
+		StrExitCAMode,         // FuncExitCA
+		StrCursorNormal,       // FuncShowCursor
+		StrCursorInvisible,    // FuncHideCursor
+		StrClearScreen,        // FuncClearScreen
+		StrExitAttributeMode,  // FuncSGR0
+		StrEnterUnderlineMode, // FuncUnderline
+		StrEnterBoldMode,      // FuncBold
+		StrEnterBlinkMode,     // FuncBlink
+		StrEnterReverseMode,   // FuncReverse
+		StrKeypadXmit,         // FuncEnterKeypad
+		StrKeypadLocal,        // FuncExitKeypad
 	}
 )
 
-// ReadFrom reads terminfo data from the given io.ReadSeeker, returning any
-// read error.
+const (
+	magicLegacy = 0432
+	magic32bit  = 01036 // ncurses 6.1+ format with 32-bit numbers
+
+	// absent numeric capabilities are reported as this value
+	capAbsent = -1
+
+	// cancelled capabilities are stored as this value, or as its byte or
+	// 16-bit string offset encoding
+	capCancelled = -2
+)
+
+var errTruncated = errors.New("truncated terminfo data")
+
+// ReadFrom reads compiled terminfo data, in the format described by term(5),
+// from the given io.ReadSeeker, returning any read error.
 //
 // TODO should we return an `nRead int` too so that TermInfo implements io.ReaderFrom?
 func (ti *Terminfo) ReadFrom(rs io.ReadSeeker) error {
-	const (
-		magic        = 0432
-		headerLength = 12
-	)
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	data, err := io.ReadAll(rs)
+	if err != nil {
+		return err
+	}
+	return ti.unmarshal(data)
+}
+
+// terminfoReader decodes successive sections of compiled terminfo data.
+type terminfoReader struct {
+	data []byte
+	off  int
+}
+
+func (tr *terminfoReader) next(n int) ([]byte, error) {
+	if n < 0 || tr.off+n > len(tr.data) {
+		return nil, errTruncated
+	}
+	p := tr.data[tr.off : tr.off+n]
+	tr.off += n
+	return p, nil
+}
+
+// align skips a padding byte, if needed, so that the next section starts on
+// an even (word) boundary.
+func (tr *terminfoReader) align() {
+	if tr.off%2 != 0 && tr.off < len(tr.data) {
+		tr.off++
+	}
+}
+
+func (tr *terminfoReader) shorts(n int) ([]int16, error) {
+	p, err := tr.next(2 * n)
+	if err != nil {
+		return nil, err
+	}
+	vs := make([]int16, n)
+	for i := range vs {
+		vs[i] = int16(binary.LittleEndian.Uint16(p[2*i:]))
+	}
+	return vs, nil
+}
+
+// numbers reads n numbers of the given size (2 or 4 bytes); any negative
+// number other than capCancelled is returned as capAbsent.
+func (tr *terminfoReader) numbers(n, size int) ([]int, error) {
+	p, err := tr.next(size * n)
+	if err != nil {
+		return nil, err
+	}
+	vs := make([]int, n)
+	for i := range vs {
+		var v int
+		if size == 4 {
+			v = int(int32(binary.LittleEndian.Uint32(p[4*i:])))
+		} else {
+			v = int(int16(binary.LittleEndian.Uint16(p[2*i:])))
+		}
+		if v < 0 && v != capCancelled {
+			v = capAbsent
+		}
+		vs[i] = v
+	}
+	return vs, nil
+}
+
+func (ti *Terminfo) unmarshal(data []byte) error {
+	tr := terminfoReader{data: data}
 
 	// 0: magic number
 	// 1: size of names section
@@ -73,82 +156,224 @@ func (ti *Terminfo) ReadFrom(rs io.ReadSeeker) error {
 	// 3: size of numbers section (in integers)
 	// 4: size of the strings section (in integers)
 	// 5: size of the string table
-
-	var header [6]int16
-
-	if err := binary.Read(rs, binary.LittleEndian, header[:]); err != nil {
+	header, err := tr.shorts(6)
+	if err != nil {
 		return err
 	}
+	var numSize int
+	switch header[0] {
+	case magicLegacy:
+		numSize = 2
+	case magic32bit:
+		numSize = 4
+	default:
+		return fmt.Errorf("invalid magic number %04o", uint16(header[0]))
+	}
+	for _, n := range header[1:] {
+		if n < 0 {
+			return fmt.Errorf("invalid terminfo header %v", header)
+		}
+	}
 
-	if header[0] != magic {
-		return fmt.Errorf("invalid magic number %07o", header[0])
+	names, err := tr.next(int(header[1]))
+	if err != nil {
+		return err
 	}
+	if i := bytes.IndexByte(names, 0); i >= 0 {
+		names = names[:i]
+	}
+	ti.Names = strings.Split(string(names), "|")
+	ti.Name = ti.Names[0]
 
-	if (header[1]+header[2])%2 != 0 {
-		// old quirk to align everything on word boundaries
-		header[2]++
+	bools, err := tr.next(int(header[2]))
+	if err != nil {
+		return err
+	}
+	ti.Bools = make([]bool, numBoolCaps)
+	ti.Cancelled = nil
+	for i, b := range bools {
+		if i < len(ti.Bools) {
+			ti.Bools[i] = b == 1
+			if int8(b) == capCancelled {
+				ti.cancel(BoolCap(i).Name())
+			}
+		}
 	}
 
-	strOffset := headerLength + uint16(header[1]+header[2]+2*header[3])
-	tableOffset := strOffset + 2*uint16(header[4])
+	tr.align()
 
-	for i := 1; i < len(tiKeys); i++ {
-		key, err := readTableString(rs, strOffset+2*tiKeys[i], tableOffset)
-		if err != nil {
-			return err
+	nums, err := tr.numbers(int(header[3]), numSize)
+	if err != nil {
+		return err
+	}
+	ti.Numbers = make([]int, numNumCaps)
+	for i := range ti.Numbers {
+		ti.Numbers[i] = capAbsent
+	}
+	for i, n := range nums {
+		if i >= len(ti.Numbers) {
+			break
+		} else if n == capCancelled {
+			ti.cancel(NumCap(i).Name())
+		} else {
+			ti.Numbers[i] = n
 		}
-		ti.Keys[i] = key
 	}
 
-	for i := 1; i < len(tiFuncs); i++ {
-		fnc, err := readTableString(rs, strOffset+2*tiFuncs[i], tableOffset)
-		if err != nil {
-			return err
+	offs, err := tr.shorts(int(header[4]))
+	if err != nil {
+		return err
+	}
+	table, err := tr.next(int(header[5]))
+	if err != nil {
+		return err
+	}
+	strs, err := readStringTable(offs, table)
+	if err != nil {
+		return err
+	}
+	ti.Strings = make([]string, numStrCaps)
+	ti.EmptyStrings = nil
+	for i, s := range strs {
+		if i >= len(ti.Strings) {
+			break
+		} else if offs[i] >= 0 {
+			ti.setStr(StrCap(i), s)
+		} else if offs[i] == capCancelled {
+			ti.cancel(StrCap(i).Name())
 		}
-		ti.Funcs[i] = fnc
 	}
 
-	ti.Funcs[FuncEnterMouse] = tiMouseEnter
-	ti.Funcs[FuncExitMouse] = tiMouseLeave
+	ti.setKeysFuncs()
 
+	ti.ExtBools, ti.ExtNumbers, ti.ExtStrings = nil, nil, nil
+	if tr.align(); tr.off < len(tr.data) {
+		return ti.unmarshalExtended(&tr, numSize)
+	}
 	return nil
 }
 
-func readTableString(rs io.ReadSeeker, off, table uint16) (s string, err error) {
-	index, err := readUint16(rs, off)
-	if err != nil {
-		return "", err
+// setKeysFuncs fills in the Keys and Funcs arrays from Strings.
+func (ti *Terminfo) setKeysFuncs() {
+	for i := 1; i < len(tiKeys); i++ {
+		ti.Keys[i] = ti.Strings[tiKeys[i]]
 	}
-	if _, err := rs.Seek(int64(table+index), 0); err != nil {
-		return "", err
+	for i := 1; i < len(tiFuncs); i++ {
+		ti.Funcs[i] = ti.Strings[tiFuncs[i]]
 	}
-	return readNullString(rs)
+	ti.Funcs[FuncEnterMouse] = tiMouseEnter
+	ti.Funcs[FuncExitMouse] = tiMouseLeave
 }
 
-func readUint16(rs io.ReadSeeker, off uint16) (uint16, error) {
-	if _, err := rs.Seek(int64(off), 0); err != nil {
-		return 0, err
+// unmarshalExtended reads the extended capabilities section that may follow
+// the standard string table, as written by ncurses for user-defined
+// capabilities like "Tc", "Smulx", or "Ss".
+func (ti *Terminfo) unmarshalExtended(tr *terminfoReader, numSize int) error {
+	// 0: count of extended booleans
+	// 1: count of extended numbers
+	// 2: count of extended strings
+	// 3: count of string table items (values and names)
+	// 4: size of the string table
+	header, err := tr.shorts(5)
+	if err != nil {
+		return err
+	}
+	for _, n := range header {
+		if n < 0 {
+			return fmt.Errorf("invalid extended terminfo header %v", header)
+		}
+	}
+	nb, nn, ns := int(header[0]), int(header[1]), int(header[2])
+
+	bools, err := tr.next(nb)
+	if err != nil {
+		return err
 	}
-	if err := binary.Read(rs, binary.LittleEndian, &off); err != nil {
-		return 0, err
+	tr.align()
+	nums, err := tr.numbers(nn, numSize)
+	if err != nil {
+		return err
+	}
+	offs, err := tr.shorts(ns)
+	if err != nil {
+		return err
 	}
-	return off, nil
+	nameOffs, err := tr.shorts(nb + nn + ns)
+	if err != nil {
+		return err
+	}
+	table, err := tr.next(int(header[4]))
+	if err != nil {
+		return err
+	}
+
+	strs, err := readStringTable(offs, table)
+	if err != nil {
+		return err
+	}
+
+	// names follow the string values in the table, and are offset relative
+	// to the end of the last one
+	base := 0
+	for i, off := range offs {
+		if off >= 0 {
+			if end := int(off) + len(strs[i]) + 1; end > base {
+				base = end
+			}
+		}
+	}
+	names, err := readStringTable(nameOffs, table[base:])
+	if err != nil {
+		return err
+	}
+
+	ti.ExtBools = make(map[string]bool, nb)
+	for i, b := range bools {
+		if b == 1 {
+			ti.ExtBools[names[i]] = true
+		} else if int8(b) == capCancelled {
+			ti.cancel(names[i])
+		}
+	}
+	names = names[nb:]
+	ti.ExtNumbers = make(map[string]int, nn)
+	for i, n := range nums {
+		if n >= 0 {
+			ti.ExtNumbers[names[i]] = n
+		} else if n == capCancelled {
+			ti.cancel(names[i])
+		}
+	}
+	names = names[nn:]
+	ti.ExtStrings = make(map[string]string, ns)
+	for i, off := range offs {
+		if off >= 0 {
+			ti.ExtStrings[names[i]] = strs[i]
+		} else if off == capCancelled {
+			ti.cancel(names[i])
+		}
+	}
+	return nil
 }
 
-func readNullString(r io.Reader) (s string, err error) {
-	var bs []byte
-	var buf [8]byte
-	for {
-		n, err := r.Read(buf[:])
-		if err != nil {
-			return "", err
+// readStringTable resolves string capability offsets into the given string
+// table; absent and cancelled capabilities are returned as empty strings, as
+// are present but empty ones, which callers tell apart by their offset.
+func readStringTable(offs []int16, table []byte) ([]string, error) {
+	strs := make([]string, len(offs))
+	for i, off := range offs {
+		if off < 0 {
+			continue
 		}
-		for i, b := range buf[:n] {
-			if b == 0 {
-				bs = append(bs, buf[:i]...)
-				return string(bs), nil
-			}
+		if int(off) >= len(table) {
+			return nil, errTruncated
+		}
+		s := table[off:]
+		end := bytes.IndexByte(s, 0)
+		if end < 0 {
+			return nil, errTruncated
 		}
-		bs = append(bs, buf[:n]...)
+		strs[i] = string(s[:end])
 	}
+	return strs, nil
 }
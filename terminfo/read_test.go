@@ -0,0 +1,186 @@
+package terminfo_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi/terminfo"
+)
+
+func readTestdata(t *testing.T, name string) *terminfo.Terminfo {
+	f, err := os.Open("testdata/" + name[:1] + "/" + name)
+	require.NoError(t, err)
+	defer f.Close()
+	var ti terminfo.Terminfo
+	require.NoError(t, ti.ReadFrom(f))
+	return &ti
+}
+
+func TestTerminfo_ReadFrom(t *testing.T) {
+	ti := readTestdata(t, "xterm")
+
+	assert.Equal(t, "xterm", ti.Name)
+	assert.Equal(t, []string{"xterm", "xterm-debian", "xterm terminal emulator (X Window System)"}, ti.Names)
+
+	assert.True(t, ti.Bool(terminfo.BoolAutoRightMargin), "am")
+	assert.True(t, ti.BoolNamed("bce"), "bce")
+	assert.False(t, ti.BoolNamed("hc"), "hc")
+	assert.False(t, ti.BoolNamed("nonesuch"), "nonesuch")
+
+	for _, tc := range []struct {
+		name  string
+		cap   terminfo.NumCap
+		value int
+		ok    bool
+	}{
+		{"colors", terminfo.NumMaxColors, 8, true},
+		{"it", terminfo.NumInitTabs, 8, true},
+		{"lines", terminfo.NumLines, 24, true},
+		{"xmc", terminfo.NumMagicCookieGlitch, -1, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.name, tc.cap.Name())
+			value, ok := ti.Num(tc.cap)
+			assert.Equal(t, tc.value, value)
+			assert.Equal(t, tc.ok, ok)
+			value, ok = ti.NumNamed(tc.name)
+			assert.Equal(t, tc.value, value)
+			assert.Equal(t, tc.ok, ok)
+		})
+	}
+
+	for _, tc := range []struct {
+		name  string
+		cap   terminfo.StrCap
+		value string
+	}{
+		{"cup", terminfo.StrCursorAddress, "\x1b[%i%p1%d;%p2%dH"},
+		{"setaf", terminfo.StrSetAForeground, "\x1b[3%p1%dm"},
+		{"smcup", terminfo.StrEnterCAMode, "\x1b[?1049h\x1b[22;0;0t"},
+		{"rmcup", terminfo.StrExitCAMode, "\x1b[?1049l\x1b[23;0;0t"},
+		{"kf1", terminfo.StrKeyF1, "\x1bOP"},
+		{"kend", terminfo.StrKeyEnd, "\x1bOF"},
+		{"hd", terminfo.StrDownHalfLine, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.name, tc.cap.Name())
+			value, ok := ti.Str(tc.cap)
+			assert.Equal(t, tc.value, value)
+			assert.Equal(t, tc.value != "", ok)
+			value, ok = ti.StrNamed(tc.name)
+			assert.Equal(t, tc.value, value)
+			assert.Equal(t, tc.value != "", ok)
+		})
+	}
+
+	assert.Equal(t, "\x1bOP", ti.Keys[terminfo.KeyF1])
+	assert.Equal(t, "\x1bOF", ti.Keys[terminfo.KeyEnd])
+	assert.Equal(t, "\x1b[?1049h\x1b[22;0;0t", ti.Funcs[terminfo.FuncEnterCA])
+}
+
+func TestTerminfo_ReadFrom_extended(t *testing.T) {
+	for _, tc := range []struct {
+		term   string
+		bools  []string
+		nums   map[string]int
+		strs   map[string]string
+		absent []string
+		colors int
+		pairs  int
+	}{
+		{
+			term:  "xterm",
+			bools: []string{"AX", "XT"},
+			strs: map[string]string{
+				"Ss":  "\x1b[%p1%d q",
+				"Se":  "\x1b[2 q",
+				"E3":  "\x1b[3J",
+				"kDC": "\x1b[3;2~",
+			},
+			absent: []string{"Tc", "Smulx", "U8"},
+			colors: 8,
+			pairs:  64,
+		},
+		{
+			term:  "xterm-256color",
+			bools: []string{"AX", "XT"},
+			strs: map[string]string{
+				"Ms": "\x1b]52;%p1%s;%p2%s\a",
+			},
+			absent: []string{"Tc"},
+			colors: 256,
+			pairs:  0x10000,
+		},
+		{
+			term:  "tmux-256color",
+			bools: []string{"AX", "G0"},
+			nums:  map[string]int{"U8": 1},
+			strs: map[string]string{
+				"Smulx": "\x1b[4:%p1%dm",
+				"Ss":    "\x1b[%p1%d q",
+				"TS":    "\x1b]0;",
+			},
+			absent: []string{"XT"},
+			colors: 256,
+			pairs:  0x10000,
+		},
+	} {
+		t.Run(tc.term, func(t *testing.T) {
+			ti := readTestdata(t, tc.term)
+			assert.Equal(t, tc.term, ti.Name)
+
+			colors, _ := ti.Num(terminfo.NumMaxColors)
+			assert.Equal(t, tc.colors, colors, "colors")
+			pairs, _ := ti.NumNamed("pairs")
+			assert.Equal(t, tc.pairs, pairs, "pairs")
+
+			for _, name := range tc.bools {
+				assert.True(t, ti.BoolNamed(name), "expected %v", name)
+			}
+			for name, value := range tc.nums {
+				n, ok := ti.NumNamed(name)
+				assert.True(t, ok, "expected %v", name)
+				assert.Equal(t, value, n, "expected %v", name)
+			}
+			for name, value := range tc.strs {
+				s, ok := ti.StrNamed(name)
+				assert.True(t, ok, "expected %v", name)
+				assert.Equal(t, value, s, "expected %v", name)
+			}
+			for _, name := range tc.absent {
+				assert.False(t, ti.BoolNamed(name), "unexpected %v", name)
+				_, ok := ti.NumNamed(name)
+				assert.False(t, ok, "unexpected %v", name)
+				_, ok = ti.StrNamed(name)
+				assert.False(t, ok, "unexpected %v", name)
+			}
+		})
+	}
+}
+
+func TestTerminfo_ReadFrom_invalid(t *testing.T) {
+	f, err := os.Open("testdata/x/xterm")
+	require.NoError(t, err)
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"bad magic", append([]byte{0x1b, 0x01}, data[2:]...)},
+		{"truncated", data[:40]},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var ti terminfo.Terminfo
+			assert.Error(t, ti.ReadFrom(bytes.NewReader(tc.data)))
+		})
+	}
+}
@@ -0,0 +1,587 @@
+package terminfo
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LoadSource loads the named entry from a terminfo source file, in the format
+// described by terminfo(5) and written by infocmp(1). If name is empty, the
+// file's first entry is returned. Any use= capabilities are resolved first
+// against other entries in the same file, and then by Load.
+//
+// Source files may also be loaded by Load and Locate, when named in their
+// SearchPath.
+func LoadSource(path, name string) (*Terminfo, error) {
+	ti, _, err := LocateSource(path, name)
+	return ti, err
+}
+
+// LocateSource loads an entry like LoadSource, but also reports where any
+// capabilities inherited by use= were found.
+func LocateSource(path, name string) (*Terminfo, Origin, error) {
+	ti, origin, err := loadSource(path, name)
+	if ti == nil && err == nil {
+		err = fmt.Errorf("%v: no entry for %q", path, name)
+	}
+	return ti, origin, err
+}
+
+// loadSource implements LocateSource, returning a nil Terminfo without error
+// if the file has no such entry. Only the named entry (and any it uses) is
+// resolved, so that a use= lookup falling through to Locate, and so back to
+// this file, never re-enters it.
+func loadSource(path, name string) (*Terminfo, Origin, error) {
+	origin := Origin{Path: path}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, origin, err
+	}
+	defer f.Close()
+	ents, err := parseSource(f)
+	if err != nil {
+		return nil, origin, fmt.Errorf("%v: %w", path, err)
+	}
+	sr := newSourceResolver(ents, Locate)
+	sr.path = path
+	ent := sr.entries[name]
+	if name == "" && len(ents) > 0 {
+		ent = ents[0]
+	}
+	if ent == nil {
+		return nil, origin, nil
+	}
+	ti, err := sr.resolve(ent)
+	if err != nil {
+		return nil, origin, fmt.Errorf("%v: %w", path, err)
+	}
+	origin.Caps = sr.caps[ent]
+	return ti, origin, nil
+}
+
+// ReadSource parses all entries from terminfo source, in the format described
+// by terminfo(5). Any use= capabilities are resolved against other entries in
+// the same source, or by the given lookup function (Load if nil).
+//
+// Capability names not known to the standard tables are read as extended
+// capabilities, with their type given by their syntax.
+func ReadSource(r io.Reader, lookup func(name string) (*Terminfo, error)) ([]*Terminfo, error) {
+	if lookup == nil {
+		lookup = Load
+	}
+	ents, err := parseSource(r)
+	if err != nil {
+		return nil, err
+	}
+	sr := newSourceResolver(ents, func(name string) (*Terminfo, Origin, error) {
+		ti, err := lookup(name)
+		return ti, Origin{}, err
+	})
+	tis := make([]*Terminfo, len(ents))
+	for i, ent := range ents {
+		if tis[i], err = sr.resolve(ent); err != nil {
+			return nil, err
+		}
+	}
+	return tis, nil
+}
+
+type sourceEntry struct {
+	line  int
+	names []string
+	caps  []sourceCap
+}
+
+// sourceCap is a single parsed capability; kind is one of: 0 for booleans,
+// '#' for numbers, '=' for strings, or '@' for cancellation.
+type sourceCap struct {
+	name string
+	kind byte
+	num  int
+	str  string
+}
+
+var errTruncatedEscape = errors.New("truncated escape")
+
+func parseSource(r io.Reader) (ents []*sourceEntry, _ error) {
+	var (
+		sc   = bufio.NewScanner(r)
+		ent  *sourceEntry
+		body strings.Builder
+		line int
+	)
+	flush := func() error {
+		if ent == nil {
+			return nil
+		}
+		fields := splitSourceFields(body.String())
+		if len(fields) == 0 {
+			return fmt.Errorf("line %v: missing entry names", ent.line)
+		}
+		ent.names = strings.Split(fields[0], "|")
+		for _, field := range fields[1:] {
+			c, err := parseSourceCap(field)
+			if err != nil {
+				return fmt.Errorf("line %v: %w", ent.line, err)
+			}
+			ent.caps = append(ent.caps, c)
+		}
+		ents = append(ents, ent)
+		ent = nil
+		body.Reset()
+		return nil
+	}
+	for sc.Scan() {
+		line++
+		s := sc.Text()
+		switch {
+		case strings.TrimSpace(s) == "", strings.HasPrefix(s, "#"):
+			continue
+		case s[0] != ' ' && s[0] != '\t':
+			// entries start at the beginning of a line
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			ent = &sourceEntry{line: line}
+		case ent == nil:
+			return nil, fmt.Errorf("line %v: capabilities outside of any entry", line)
+		}
+		body.WriteString(s)
+		body.WriteByte('\n')
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return ents, nil
+}
+
+// splitSourceFields splits an entry into its comma separated fields, skipping
+// escaped commas, and trimming surrounding whitespace.
+func splitSourceFields(s string) (fields []string) {
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\', '^':
+			i++
+		case ',':
+			if field := strings.TrimSpace(s[start:i]); field != "" {
+				fields = append(fields, field)
+			}
+			start = i + 1
+		}
+	}
+	if field := strings.TrimSpace(s[start:]); field != "" {
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func parseSourceCap(field string) (c sourceCap, err error) {
+	i := strings.IndexAny(field, "#=@")
+	if i < 0 {
+		c.name = field
+		return c, nil
+	}
+	c.name, c.kind = field[:i], field[i]
+	switch c.kind {
+	case '#':
+		n, err := strconv.ParseInt(field[i+1:], 0, 32)
+		if err != nil || n < 0 {
+			return c, fmt.Errorf("invalid number capability %q", field)
+		}
+		c.num = int(n)
+	case '=':
+		if c.str, err = unescapeSource(field[i+1:]); err != nil {
+			return c, fmt.Errorf("invalid string capability %q: %w", field, err)
+		}
+	case '@':
+		if i+1 != len(field) {
+			return c, fmt.Errorf("invalid cancelled capability %q", field)
+		}
+	}
+	if c.name == "" {
+		return c, fmt.Errorf("invalid capability %q", field)
+	}
+	return c, nil
+}
+
+// unescapeSource decodes the string escapes described in terminfo(5).
+func unescapeSource(s string) (string, error) {
+	if strings.IndexAny(s, "\\^") < 0 {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '^':
+			if i++; i >= len(s) {
+				return "", errTruncatedEscape
+			}
+			if s[i] == '?' {
+				b.WriteByte(0x7F)
+			} else {
+				b.WriteByte(s[i] & 0x1F)
+			}
+			continue
+		case '\\':
+		default:
+			b.WriteByte(c)
+			continue
+		}
+		if i++; i >= len(s) {
+			return "", errTruncatedEscape
+		}
+		switch c = s[i]; c {
+		case 'E', 'e':
+			b.WriteByte(0x1B)
+		case 'a':
+			b.WriteByte(0x07)
+		case 'n', 'l':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 's':
+			b.WriteByte(' ')
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			n, j := 0, i
+			for ; j < len(s) && j < i+3 && '0' <= s[j] && s[j] <= '7'; j++ {
+				n = 8*n + int(s[j]-'0')
+			}
+			i = j - 1
+			if n == 0 {
+				// NUL can't be represented, so it's encoded as 0200 instead
+				n = 0200
+			}
+			b.WriteByte(byte(n))
+		default:
+			// \\ \^ \, \: and any other escaped character stand for
+			// themselves
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+// sourceResolver resolves use= capabilities, against other entries from the
+// same source, or by lookup; caps records the origin of each capability so
+// inherited, as described by Origin.
+type sourceResolver struct {
+	path     string
+	lookup   func(name string) (*Terminfo, Origin, error)
+	entries  map[string]*sourceEntry
+	resolved map[*sourceEntry]*Terminfo
+	caps     map[*sourceEntry]map[string]Origin
+}
+
+func newSourceResolver(ents []*sourceEntry, lookup func(name string) (*Terminfo, Origin, error)) *sourceResolver {
+	sr := &sourceResolver{
+		lookup:   lookup,
+		entries:  make(map[string]*sourceEntry, len(ents)),
+		resolved: make(map[*sourceEntry]*Terminfo, len(ents)),
+		caps:     make(map[*sourceEntry]map[string]Origin),
+	}
+	for _, ent := range ents {
+		for _, name := range ent.names {
+			if _, def := sr.entries[name]; !def {
+				sr.entries[name] = ent
+			}
+		}
+	}
+	return sr
+}
+
+func (sr *sourceResolver) resolve(ent *sourceEntry) (*Terminfo, error) {
+	if ti, done := sr.resolved[ent]; done {
+		if ti == nil {
+			return nil, fmt.Errorf("line %v: use= cycle in %q", ent.line, ent.names[0])
+		}
+		return ti, nil
+	}
+	sr.resolved[ent] = nil
+
+	ti := newTerminfo(ent.names)
+	defined := make(map[string]bool, len(ent.caps))
+	var uses []string
+	for _, c := range ent.caps {
+		if c.name == "use" && c.kind == '=' {
+			uses = append(uses, c.str)
+			continue
+		}
+		if defined[c.name] {
+			continue
+		}
+		defined[c.name] = true
+		ti.setCap(c)
+	}
+
+	for _, name := range uses {
+		var (
+			u      *Terminfo
+			origin Origin
+			err    error
+		)
+		if uent, def := sr.entries[name]; def {
+			u, err = sr.resolve(uent)
+			origin = Origin{Path: sr.path, Caps: sr.caps[uent]}
+		} else {
+			u, origin, err = sr.lookup(name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %v: use=%v: %w", ent.line, name, err)
+		}
+		inherited := origin.Caps
+		origin.Use, origin.Caps = name, nil
+		for _, c := range ti.inherit(u, defined) {
+			if sr.caps[ent] == nil {
+				sr.caps[ent] = make(map[string]Origin)
+			}
+			if o, def := inherited[c]; def {
+				sr.caps[ent][c] = o
+			} else {
+				sr.caps[ent][c] = origin
+			}
+		}
+	}
+
+	ti.setKeysFuncs()
+	sr.resolved[ent] = ti
+	return ti, nil
+}
+
+// newTerminfo returns a Terminfo with the given names, and all standard
+// capabilities absent.
+func newTerminfo(names []string) *Terminfo {
+	ti := &Terminfo{
+		Names:      names,
+		Bools:      make([]bool, numBoolCaps),
+		Numbers:    make([]int, numNumCaps),
+		Strings:    make([]string, numStrCaps),
+		ExtBools:   make(map[string]bool),
+		ExtNumbers: make(map[string]int),
+		ExtStrings: make(map[string]string),
+	}
+	if len(names) > 0 {
+		ti.Name = names[0]
+	}
+	for i := range ti.Numbers {
+		ti.Numbers[i] = capAbsent
+	}
+	return ti
+}
+
+func (ti *Terminfo) setCap(c sourceCap) {
+	switch c.kind {
+	case 0:
+		if i, def := boolCapsByName[c.name]; def {
+			ti.Bools[i] = true
+		} else {
+			ti.ExtBools[c.name] = true
+		}
+	case '#':
+		if i, def := numCapsByName[c.name]; def {
+			ti.Numbers[i] = c.num
+		} else {
+			ti.ExtNumbers[c.name] = c.num
+		}
+	case '=':
+		if i, def := strCapsByName[c.name]; def {
+			ti.setStr(i, c.str)
+		} else {
+			ti.ExtStrings[c.name] = c.str
+		}
+	case '@':
+		ti.cancel(c.name)
+	}
+}
+
+// inherit copies any capabilities from u that haven't yet been defined (or
+// cancelled), marking them defined, and returning their names.
+func (ti *Terminfo) inherit(u *Terminfo, defined map[string]bool) (names []string) {
+	for i, b := range u.Bools {
+		if name := BoolCap(i).Name(); b && !defined[name] {
+			ti.Bools[i], defined[name] = true, true
+			names = append(names, name)
+		}
+	}
+	for i, n := range u.Numbers {
+		if name := NumCap(i).Name(); n >= 0 && !defined[name] {
+			ti.Numbers[i], defined[name] = n, true
+			names = append(names, name)
+		}
+	}
+	for i, s := range u.Strings {
+		if name := StrCap(i).Name(); u.hasStr(StrCap(i)) && !defined[name] {
+			ti.setStr(StrCap(i), s)
+			defined[name] = true
+			names = append(names, name)
+		}
+	}
+	for name, b := range u.ExtBools {
+		if b && !defined[name] {
+			ti.ExtBools[name], defined[name] = true, true
+			names = append(names, name)
+		}
+	}
+	for name, n := range u.ExtNumbers {
+		if !defined[name] {
+			ti.ExtNumbers[name], defined[name] = n, true
+			names = append(names, name)
+		}
+	}
+	for name, s := range u.ExtStrings {
+		if !defined[name] {
+			ti.ExtStrings[name], defined[name] = s, true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// WriteSource writes ti in terminfo source form, like infocmp -x; standard and
+// extended capabilities are grouped by type, and sorted by name.
+func (ti *Terminfo) WriteSource(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	if len(ti.Names) > 0 {
+		buf.WriteString(strings.Join(ti.Names, "|"))
+	} else {
+		buf.WriteString(ti.Name)
+	}
+	buf.WriteString(",\n")
+	bools, nums, strs := ti.sourceFields()
+	for _, fields := range [][]string{bools, nums, strs} {
+		writeSourceFields(&buf, fields)
+	}
+	return buf.WriteTo(w)
+}
+
+// maxSourceWidth is the column at which WriteSource wraps capability lines,
+// not counting the leading tab.
+const maxSourceWidth = 60
+
+func writeSourceFields(buf *bytes.Buffer, fields []string) {
+	width := 0
+	for _, field := range fields {
+		if width > 0 && width+len(field)+2 > maxSourceWidth {
+			buf.WriteString("\n")
+			width = 0
+		}
+		if width == 0 {
+			buf.WriteByte('\t')
+		} else {
+			buf.WriteByte(' ')
+			width++
+		}
+		buf.WriteString(field)
+		buf.WriteByte(',')
+		width += len(field) + 1
+	}
+	if width > 0 {
+		buf.WriteString("\n")
+	}
+}
+
+// sourceFields returns all defined or cancelled capabilities formatted as
+// source fields, grouped by type, and sorted by name.
+func (ti *Terminfo) sourceFields() (bools, nums, strs []string) {
+	for i, b := range ti.Bools {
+		if name := BoolCap(i).Name(); b {
+			bools = append(bools, name)
+		} else if ti.Cancelled[name] {
+			bools = append(bools, name+"@")
+		}
+	}
+	for name, b := range ti.ExtBools {
+		if b {
+			bools = append(bools, name)
+		}
+	}
+	for i, n := range ti.Numbers {
+		if name := NumCap(i).Name(); n >= 0 {
+			nums = append(nums, fmt.Sprintf("%v#%v", name, sourceNumber(n)))
+		} else if ti.Cancelled[name] {
+			nums = append(nums, name+"@")
+		}
+	}
+	for name, n := range ti.ExtNumbers {
+		nums = append(nums, fmt.Sprintf("%v#%v", name, sourceNumber(n)))
+	}
+	for i, s := range ti.Strings {
+		if name := StrCap(i).Name(); ti.hasStr(StrCap(i)) {
+			strs = append(strs, name+"="+EscapeSource(s))
+		} else if ti.Cancelled[name] {
+			strs = append(strs, name+"@")
+		}
+	}
+	for name, s := range ti.ExtStrings {
+		strs = append(strs, name+"="+EscapeSource(s))
+	}
+	for _, name := range ti.cancelledExt() {
+		strs = append(strs, name+"@")
+	}
+	sort.Slice(bools, func(i, j int) bool { return sourceFieldName(bools[i]) < sourceFieldName(bools[j]) })
+	sort.Slice(nums, func(i, j int) bool { return sourceFieldName(nums[i]) < sourceFieldName(nums[j]) })
+	sort.Slice(strs, func(i, j int) bool { return sourceFieldName(strs[i]) < sourceFieldName(strs[j]) })
+	return bools, nums, strs
+}
+
+func sourceFieldName(field string) string {
+	if i := strings.IndexAny(field, "#=@"); i >= 0 {
+		return field[:i]
+	}
+	return field
+}
+
+// sourceNumber formats numbers like infocmp does: powers of two above 255 are
+// written in hex, e.g. colors#0x100.
+func sourceNumber(n int) string {
+	if n > 255 && n&(n-1) == 0 {
+		return fmt.Sprintf("%#x", n)
+	}
+	return strconv.Itoa(n)
+}
+
+// EscapeSource encodes a capability string value using the escapes described
+// in terminfo(5), so that it may be written in a source field; it is the
+// inverse of the decoding done by ReadSource.
+func EscapeSource(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == 0x1B:
+			b.WriteString(`\E`)
+		case c == 0x7F:
+			b.WriteString(`^?`)
+		case c == '\\', c == '^', c == ',', c == ':':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == ' ' && (i == 0 || i == len(s)-1):
+			// leading and trailing spaces would be lost to field splitting
+			b.WriteString(`\s`)
+		case c < 0x20:
+			b.WriteByte('^')
+			b.WriteByte(c | 0x40)
+		case c >= 0x80:
+			fmt.Fprintf(&b, `\%03o`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,223 @@
+package terminfo_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi/terminfo"
+)
+
+func assertSameTerminfo(t *testing.T, expected, actual *terminfo.Terminfo) {
+	assert.Equal(t, expected.Name, actual.Name, "Name")
+	assert.Equal(t, expected.Names, actual.Names, "Names")
+	assert.Equal(t, expected.Keys, actual.Keys, "Keys")
+	assert.Equal(t, expected.Funcs, actual.Funcs, "Funcs")
+	assert.Equal(t, expected.Bools, actual.Bools, "Bools")
+	assert.Equal(t, expected.Numbers, actual.Numbers, "Numbers")
+	assert.Equal(t, expected.Strings, actual.Strings, "Strings")
+	if len(expected.EmptyStrings)+len(actual.EmptyStrings) > 0 {
+		assert.Equal(t, expected.EmptyStrings, actual.EmptyStrings, "EmptyStrings")
+	}
+	if len(expected.Cancelled)+len(actual.Cancelled) > 0 {
+		assert.Equal(t, expected.Cancelled, actual.Cancelled, "Cancelled")
+	}
+	// nil and empty extended maps are equivalent
+	if len(expected.ExtBools)+len(actual.ExtBools) > 0 {
+		assert.Equal(t, expected.ExtBools, actual.ExtBools, "ExtBools")
+	}
+	if len(expected.ExtNumbers)+len(actual.ExtNumbers) > 0 {
+		assert.Equal(t, expected.ExtNumbers, actual.ExtNumbers, "ExtNumbers")
+	}
+	if len(expected.ExtStrings)+len(actual.ExtStrings) > 0 {
+		assert.Equal(t, expected.ExtStrings, actual.ExtStrings, "ExtStrings")
+	}
+}
+
+func TestLoadSource(t *testing.T) {
+	// testdata/terminfo.src was written by infocmp -x from the compiled
+	// testdata entries
+	for _, term := range []string{"xterm", "xterm-256color", "tmux-256color"} {
+		t.Run(term, func(t *testing.T) {
+			ti, err := terminfo.LoadSource("testdata/terminfo.src", term)
+			require.NoError(t, err)
+			assertSameTerminfo(t, readTestdata(t, term), ti)
+		})
+	}
+
+	ti, err := terminfo.LoadSource("testdata/terminfo.src", "")
+	require.NoError(t, err)
+	assert.Equal(t, "xterm", ti.Name)
+
+	_, err = terminfo.LoadSource("testdata/terminfo.src", "nonesuch")
+	assert.Error(t, err)
+}
+
+func TestLocate_source(t *testing.T) {
+	t.Setenv("TERMINFO", "testdata/terminfo.src")
+
+	ti, origin, err := terminfo.Locate("tmux-256color")
+	require.NoError(t, err)
+	assert.Equal(t, terminfo.Origin{Path: "testdata/terminfo.src"}, origin)
+	assertSameTerminfo(t, readTestdata(t, "tmux-256color"), ti)
+
+	ti, origin, err = terminfo.Locate("linux")
+	require.NoError(t, err, "expected fallback to builtins")
+	assert.Equal(t, "linux", ti.Name)
+	assert.Equal(t, terminfo.Origin{Builtin: "linux"}, origin)
+}
+
+func TestLocate_sourceOrigin(t *testing.T) {
+	t.Setenv("TERMINFO", "testdata/uses.src")
+	ti, origin, err := terminfo.Locate("mine")
+	require.NoError(t, err)
+	assert.Equal(t, "testdata/uses.src", origin.Path)
+
+	n, _ := ti.Num(terminfo.NumInitTabs)
+	assert.Equal(t, 4, n, "expected it from the first use= to win")
+
+	local := terminfo.Origin{Path: "testdata/uses.src", Use: "mine-base"}
+	root := terminfo.Origin{Path: "testdata/uses.src", Use: "mine-root"}
+	builtin := terminfo.Origin{Builtin: "linux", Use: "linux"}
+	for name, expected := range map[string]terminfo.Origin{
+		"bce": local,
+		"it":  root,
+		"kf1": root,
+		"am":  builtin,
+		"cup": builtin,
+	} {
+		assert.Equal(t, expected, origin.Caps[name], "expected %v origin", name)
+	}
+	_, def := origin.Caps["cols"]
+	assert.False(t, def, "expected no origin for the entry's own cols")
+	assert.Equal(t, "use=mine-root in file testdata/uses.src", root.String())
+
+	_, origin, err = terminfo.LocateSource("testdata/uses.src", "mine-base")
+	require.NoError(t, err)
+	assert.Equal(t, terminfo.Origin{
+		Path: "testdata/uses.src",
+		Caps: map[string]terminfo.Origin{"it": root, "kf1": root},
+	}, origin)
+}
+
+func TestLocate_sourceUse(t *testing.T) {
+	t.Setenv("TERMINFO", "testdata/dangling.src")
+	_, _, err := terminfo.Locate("other")
+	if assert.Error(t, err, "expected dangling use= to fail") {
+		assert.Contains(t, err.Error(), "use=missing-base")
+	}
+
+	t.Setenv("TERMINFO", "")
+	t.Setenv("TERMINFO_DIRS", "testdata/dangling.src:testdata/cycle.src")
+	_, _, err = terminfo.Locate("cyclic")
+	if assert.Error(t, err, "expected use= cycle across files to fail") {
+		assert.Contains(t, err.Error(), "use= cycle")
+	}
+}
+
+func TestReadSource_use(t *testing.T) {
+	lookup := func(name string) (*terminfo.Terminfo, error) {
+		if name == "xterm" {
+			return readTestdata(t, "xterm"), nil
+		}
+		return nil, errors.New("no such terminal")
+	}
+
+	tis, err := terminfo.ReadSource(strings.NewReader(`
+# a comment
+mine|my custom terminal,
+	Tc, colors#0x100, setaf=\E[38;5;%p1%dm,
+	Smulx=\E[4:%p1%dm, bce@, Se@, use=base,
+
+base|a base entry,
+	U8#1, cvvis=\E[?25h, Smulx=nope, use=xterm,
+`), lookup)
+	require.NoError(t, err)
+	require.Len(t, tis, 2)
+	ti, base := tis[0], tis[1]
+
+	assert.Equal(t, "mine", ti.Name)
+	assert.Equal(t, []string{"mine", "my custom terminal"}, ti.Names)
+	assert.Equal(t, "base", base.Name)
+
+	// local definitions
+	assert.True(t, ti.BoolNamed("Tc"))
+	colors, _ := ti.NumNamed("colors")
+	assert.Equal(t, 256, colors)
+	setaf, _ := ti.Param("setaf", 200)
+	assert.Equal(t, "\x1b[38;5;200m", setaf)
+	smulx, _ := ti.StrNamed("Smulx")
+	assert.Equal(t, "\x1b[4:%p1%dm", smulx)
+
+	// inherited from base, and thru it from xterm
+	u8, _ := ti.NumNamed("U8")
+	assert.Equal(t, 1, u8)
+	cvvis, _ := ti.StrNamed("cvvis")
+	assert.Equal(t, "\x1b[?25h", cvvis)
+	cup, _ := ti.StrNamed("cup")
+	assert.Equal(t, "\x1b[%i%p1%d;%p2%dH", cup)
+	assert.True(t, ti.BoolNamed("am"))
+	assert.Equal(t, "\x1bOP", ti.Keys[terminfo.KeyF1])
+
+	// cancelled
+	assert.False(t, ti.BoolNamed("bce"))
+	assert.True(t, base.BoolNamed("bce"))
+	_, ok := ti.StrNamed("Se")
+	assert.False(t, ok)
+	_, ok = base.StrNamed("Se")
+	assert.True(t, ok)
+}
+
+func TestReadSource_errors(t *testing.T) {
+	lookup := func(name string) (*terminfo.Terminfo, error) {
+		return nil, errors.New("no such terminal")
+	}
+	for _, tc := range []struct {
+		name string
+		src  string
+	}{
+		{"orphan caps", "\tam, bce,\n"},
+		{"bad number", "foo|bar,\n\tcolors#x,\n"},
+		{"bad escape", "foo|bar,\n\tcup=\\E[H^"},
+		{"bad cancel", "foo|bar,\n\tam@x,\n"},
+		{"use cycle", "a,\n\tam, use=b,\nb,\n\tbce, use=a,\n"},
+		{"use missing", "a,\n\tam, use=nonesuch,\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := terminfo.ReadSource(strings.NewReader(tc.src), lookup)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTerminfo_WriteSource(t *testing.T) {
+	for _, term := range []string{"xterm", "xterm-256color", "tmux-256color"} {
+		t.Run(term, func(t *testing.T) {
+			ti := readTestdata(t, term)
+			var buf strings.Builder
+			_, err := ti.WriteSource(&buf)
+			require.NoError(t, err)
+			tis, err := terminfo.ReadSource(strings.NewReader(buf.String()), nil)
+			require.NoError(t, err)
+			require.Len(t, tis, 1)
+			assertSameTerminfo(t, ti, tis[0])
+		})
+	}
+
+	tis, err := terminfo.ReadSource(strings.NewReader(
+		"mine|my terminal,\n\tTc, am, U8#1, pairs#0x10000, cols#80, Ss=\\E[%p1%d q, bel=^G, smso=\\s\\,\\:\\^\\\\\\200,\n",
+	), nil)
+	require.NoError(t, err)
+	var buf strings.Builder
+	_, err = tis[0].WriteSource(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, ""+
+		"mine|my terminal,\n"+
+		"\tTc, am,\n"+
+		"\tU8#1, cols#80, pairs#0x10000,\n"+
+		"\tSs=\\E[%p1%d q, bel=^G, smso=\\s\\,\\:\\^\\\\\\200,\n",
+		buf.String())
+}
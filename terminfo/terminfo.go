@@ -2,6 +2,7 @@ package terminfo
 
 import (
 	"encoding/hex"
+	"fmt"
 	"os"
 	"os/user"
 	"path/filepath"
@@ -15,6 +16,7 @@ type compatEntry struct {
 
 var (
 	cache       = make(map[string]*Terminfo, 64)
+	locating    = make(map[string]bool)          // guards use= cycles across source files
 	builtins    = make(map[string]*Terminfo, 64) // TODO more coverage
 	compatTable = make([]compatEntry, 0, 64)
 )
@@ -74,10 +76,156 @@ const (
 )
 
 // Terminfo describes how to interact with a terminal.
+//
+// Keys and Funcs provide quick access to commonly used strings; the full set
+// of standard capabilities is available through Bools, Numbers, and Strings,
+// indexed by BoolCap, NumCap, and StrCap respectively. Absent numbers are -1,
+// while absent strings are empty; present but empty strings (e.g. Eterm's
+// "smkx=") are marked in EmptyStrings. Extended (user-defined) capabilities,
+// like "Tc", "Smulx", or "Ss", are keyed by name; only present ones are
+// included.
+//
+// Capabilities cancelled by the entry itself (written "name@" in source, e.g.
+// to drop one inherited by use=) are absent, but are also named in Cancelled,
+// so that they're written back as cancelled. Since their type isn't kept,
+// cancelled extended capabilities are written as strings, as tic assumes for
+// unknown cancelled names.
 type Terminfo struct {
 	Name  string
+	Names []string
 	Keys  [maxKeys]string
 	Funcs [maxFuncs]string
+
+	Bools   []bool
+	Numbers []int
+	Strings []string
+
+	EmptyStrings map[StrCap]bool
+	Cancelled    map[string]bool
+
+	ExtBools   map[string]bool
+	ExtNumbers map[string]int
+	ExtStrings map[string]string
+}
+
+// Bool returns the value of a boolean capability.
+func (info *Terminfo) Bool(c BoolCap) bool {
+	return int(c) < len(info.Bools) && info.Bools[c]
+}
+
+// Num returns the value of a numeric capability, and whether it's present.
+func (info *Terminfo) Num(c NumCap) (int, bool) {
+	if int(c) < len(info.Numbers) && info.Numbers[c] >= 0 {
+		return info.Numbers[c], true
+	}
+	return -1, false
+}
+
+// Str returns the value of a string capability, and whether it's present.
+func (info *Terminfo) Str(c StrCap) (string, bool) {
+	if info.hasStr(c) {
+		return info.Strings[c], true
+	}
+	return "", false
+}
+
+func (info *Terminfo) hasStr(c StrCap) bool {
+	return int(c) < len(info.Strings) && (info.Strings[c] != "" || info.EmptyStrings[c])
+}
+
+// setStr sets a standard string capability, marking it present if empty.
+func (info *Terminfo) setStr(c StrCap, s string) {
+	info.Strings[c] = s
+	if s != "" {
+		delete(info.EmptyStrings, c)
+	} else {
+		if info.EmptyStrings == nil {
+			info.EmptyStrings = make(map[StrCap]bool)
+		}
+		info.EmptyStrings[c] = true
+	}
+}
+
+// cancel marks the named capability cancelled, without changing its value.
+func (info *Terminfo) cancel(name string) {
+	if info.Cancelled == nil {
+		info.Cancelled = make(map[string]bool)
+	}
+	info.Cancelled[name] = true
+}
+
+// cancelledExt returns the names of any cancelled extended capabilities that
+// aren't otherwise present.
+func (info *Terminfo) cancelledExt() []string {
+	var names []string
+	for name := range info.Cancelled {
+		if isStandardCap(name) {
+			continue
+		}
+		_, isBool := info.ExtBools[name]
+		_, isNum := info.ExtNumbers[name]
+		_, isStr := info.ExtStrings[name]
+		if !isBool && !isNum && !isStr {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func isStandardCap(name string) bool {
+	_, isBool := boolCapsByName[name]
+	_, isNum := numCapsByName[name]
+	_, isStr := strCapsByName[name]
+	return isBool || isNum || isStr
+}
+
+// BoolNamed returns the value of a standard or extended boolean capability
+// given its terminfo name, e.g. "am" or "Tc".
+func (info *Terminfo) BoolNamed(name string) bool {
+	if c, def := boolCapsByName[name]; def {
+		return info.Bool(c)
+	}
+	return info.ExtBools[name]
+}
+
+// NumNamed returns the value of a standard or extended numeric capability
+// given its terminfo name, e.g. "colors" or "U8", and whether it's present.
+func (info *Terminfo) NumNamed(name string) (int, bool) {
+	if c, def := numCapsByName[name]; def {
+		return info.Num(c)
+	}
+	if n, def := info.ExtNumbers[name]; def {
+		return n, true
+	}
+	return -1, false
+}
+
+// StrNamed returns the value of a standard or extended string capability
+// given its terminfo name, e.g. "cup" or "Ss", and whether it's present.
+func (info *Terminfo) StrNamed(name string) (string, bool) {
+	if c, def := strCapsByName[name]; def {
+		return info.Str(c)
+	}
+	s, def := info.ExtStrings[name]
+	return s, def
+}
+
+var (
+	boolCapsByName = make(map[string]BoolCap, numBoolCaps)
+	numCapsByName  = make(map[string]NumCap, numNumCaps)
+	strCapsByName  = make(map[string]StrCap, numStrCaps)
+)
+
+func init() {
+	for i, names := range boolCapNames {
+		boolCapsByName[names.name] = BoolCap(i)
+	}
+	for i, names := range numCapNames {
+		numCapsByName[names.name] = NumCap(i)
+	}
+	for i, names := range strCapNames {
+		strCapsByName[names.name] = StrCap(i)
+	}
 }
 
 const (
@@ -86,7 +234,9 @@ const (
 )
 
 // SearchPath returns candidate file paths to try to load from; this follows
-// the behaviour described in terminfo(5) as distributed by ncurses.
+// the behaviour described in terminfo(5) as distributed by ncurses. Unlike
+// ncurses, a candidate may also name a terminfo source file rather than a
+// database directory (e.g. TERMINFO=custom.src); see LoadSource.
 func SearchPath() []string {
 	if terminfo := os.Getenv("TERMINFO"); terminfo != "" {
 		// if TERMINFO is set, no other directory should be searched
@@ -139,8 +289,69 @@ func Load(term string) (*Terminfo, error) {
 	if ti, def := cache[term]; def {
 		return ti, nil
 	}
+	ti, _, err := Locate(term)
+	return ti, err
+}
+
+// Origin describes where Locate found a Terminfo entry.
+//
+// Entries read from a terminfo source file may inherit capabilities by use=,
+// from other entries in the same file, or from anywhere that Locate finds
+// them (including the builtins); Caps records where each such capability was
+// defined, with Use naming the entry it was inherited from. Capabilities
+// without an entry in Caps were defined by the entry itself. Compiled entries
+// have any use= resolved when compiled, so have no Caps.
+type Origin struct {
+	Path    string // file read from the terminfo database, if any
+	Builtin string // name of the builtin entry used, if no file was found
+	Partial string // compat partial name that matched the builtin, if any
+	Use     string // entry named by use=, for inherited capabilities
+
+	Caps map[string]Origin
+}
+
+func (o Origin) String() string {
+	var s string
+	switch {
+	case o.Path != "":
+		s = fmt.Sprintf("file %v", o.Path)
+	case o.Partial != "":
+		s = fmt.Sprintf("builtin %v (compat partial match %q)", o.Builtin, o.Partial)
+	case o.Builtin != "":
+		s = fmt.Sprintf("builtin %v", o.Builtin)
+	default:
+		s = "unknown"
+	}
+	if o.Use != "" {
+		s = fmt.Sprintf("use=%v in %v", o.Use, s)
+	}
+	return s
+}
+
+// Locate finds and loads Terminfo like Load, but bypasses the cache (while
+// still updating it), and also reports where the entry was found.
+func Locate(term string) (*Terminfo, Origin, error) {
+	if term == "" {
+		return LocateBuiltin(term)
+	}
+	if locating[term] {
+		return nil, Origin{}, fmt.Errorf("use= cycle through %q", term)
+	}
+	locating[term] = true
+	defer delete(locating, term)
 	paths := SearchPath()
 	for _, path := range paths {
+		if fi, err := os.Stat(path); err == nil && fi.Mode().IsRegular() {
+			// a source file, rather than a database directory
+			ti, origin, err := loadSource(path, term)
+			if ti == nil && err == nil {
+				continue
+			}
+			if err == nil {
+				cache[term] = ti
+			}
+			return ti, origin, err
+		}
 		for _, fp := range []string{
 			filepath.Join(path, term[0:1], term),                            // the typical *nix path
 			filepath.Join(path, hex.EncodeToString([]byte(term[:1])), term), // darwin specific dirs structure
@@ -156,11 +367,11 @@ func Load(term string) (*Terminfo, error) {
 				if err == nil {
 					cache[term] = &ti
 				}
-				return &ti, err
+				return &ti, Origin{Path: fp}, err
 			}
 		}
 	}
-	return GetBuiltin(term)
+	return LocateBuiltin(term)
 }
 
 // FuncMap builds and returns a string-string map of the control sequence
@@ -0,0 +1,50 @@
+/*
+ * tiparm evaluates parameterized strings with ncurses tiparm(3), to record
+ * (and fuzz against) its results; build it with:
+ *
+ *	cc -o tiparm tiparm.c -ltinfo
+ *
+ * Each input line holds a hex encoded string (of at most 4095 bytes), followed
+ * by up to 9 decimal numeric parameters, separated by spaces; each output line
+ * is the hex encoded result, or NULL if tiparm failed.
+ *
+ * NOTE ncurses retains static variables (%P[A-Z]) between calls, so use a
+ * fresh process for each string that sets them.
+ */
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+#include <curses.h>
+#include <term.h>
+
+static void unhex(const char *h, char *out) {
+	unsigned v;
+	for (; h[0] && h[1] && h[0] != ' ' && h[0] != '\n'; h += 2) {
+		sscanf(h, "%2x", &v);
+		*out++ = (char)v;
+	}
+	*out = 0;
+}
+
+int main(void) {
+	char line[16384], fmt[4096];
+	while (fgets(line, sizeof line, stdin)) {
+		long a[9] = {0};
+		char *sp = strchr(line, ' ');
+		unhex(line, fmt);
+		for (int i = 0; i < 9 && sp; i++) {
+			a[i] = strtol(sp + 1, NULL, 10);
+			sp = strchr(sp + 1, ' ');
+		}
+		char *out = tiparm(fmt, a[0], a[1], a[2], a[3], a[4], a[5], a[6], a[7], a[8]);
+		if (!out) {
+			printf("NULL\n");
+		} else {
+			for (unsigned char *p = (unsigned char *)out; *p; p++)
+				printf("%02x", *p);
+			printf("\n");
+		}
+		fflush(stdout);
+	}
+	return 0;
+}
@@ -0,0 +1,204 @@
+package terminfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// WriteTo writes compiled terminfo data, in the format described by term(5),
+// to the given io.Writer, returning the number of bytes written and any write
+// error. The legacy format is written, unless some number doesn't fit in 16
+// bits, in which case the ncurses 32-bit format is used. Any extended
+// capabilities are written in an extended section, sorted by name.
+func (ti *Terminfo) WriteTo(w io.Writer) (int64, error) {
+	data, err := ti.marshal()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// terminfoWriter encodes successive sections of compiled terminfo data.
+type terminfoWriter struct {
+	bytes.Buffer
+	numSize int
+}
+
+func (tw *terminfoWriter) align() {
+	if tw.Len()%2 != 0 {
+		tw.WriteByte(0)
+	}
+}
+
+func (tw *terminfoWriter) shorts(vs ...int) {
+	for _, v := range vs {
+		var b [2]byte
+		binary.LittleEndian.PutUint16(b[:], uint16(int16(v)))
+		tw.Write(b[:])
+	}
+}
+
+func (tw *terminfoWriter) numbers(vs []int) {
+	if tw.numSize == 2 {
+		tw.shorts(vs...)
+		return
+	}
+	for _, v := range vs {
+		var b [4]byte
+		binary.LittleEndian.PutUint32(b[:], uint32(int32(v)))
+		tw.Write(b[:])
+	}
+}
+
+// stringTable collects NUL terminated strings, and their offsets.
+type stringTable struct {
+	bytes.Buffer
+	offs []int
+}
+
+func (st *stringTable) add(s string) {
+	st.offs = append(st.offs, st.Len())
+	st.WriteString(s)
+	st.WriteByte(0)
+}
+
+func (st *stringTable) absent() { st.offs = append(st.offs, -1) }
+
+func (st *stringTable) cancelled() { st.offs = append(st.offs, capCancelled) }
+
+func (ti *Terminfo) marshal() ([]byte, error) {
+	names := strings.Join(ti.Names, "|")
+	if names == "" {
+		names = ti.Name
+	}
+
+	bools := make([]byte, len(ti.Bools))
+	for i, b := range ti.Bools {
+		if b {
+			bools[i] = 1
+		} else if ti.Cancelled[BoolCap(i).Name()] {
+			bools[i] = 0xFE // capCancelled, as a signed byte
+		}
+	}
+	for len(bools) > 0 && bools[len(bools)-1] == 0 {
+		bools = bools[:len(bools)-1]
+	}
+	nums := make([]int, len(ti.Numbers))
+	for i, n := range ti.Numbers {
+		if n < 0 && ti.Cancelled[NumCap(i).Name()] {
+			n = capCancelled
+		}
+		nums[i] = n
+	}
+	for len(nums) > 0 && nums[len(nums)-1] == capAbsent {
+		nums = nums[:len(nums)-1]
+	}
+	var strs stringTable
+	n := len(ti.Strings)
+	for n > 0 && !ti.hasStr(StrCap(n-1)) && !ti.Cancelled[StrCap(n-1).Name()] {
+		n--
+	}
+	for i, s := range ti.Strings[:n] {
+		if ti.hasStr(StrCap(i)) {
+			strs.add(s)
+		} else if ti.Cancelled[StrCap(i).Name()] {
+			strs.cancelled()
+		} else {
+			strs.absent()
+		}
+	}
+
+	var ext struct {
+		bools, nums, strs []string
+	}
+	for name, b := range ti.ExtBools {
+		if b {
+			ext.bools = append(ext.bools, name)
+		}
+	}
+	for name := range ti.ExtNumbers {
+		ext.nums = append(ext.nums, name)
+	}
+	for name := range ti.ExtStrings {
+		ext.strs = append(ext.strs, name)
+	}
+	for _, name := range ti.cancelledExt() {
+		ext.strs = append(ext.strs, name)
+	}
+	sort.Strings(ext.bools)
+	sort.Strings(ext.nums)
+	sort.Strings(ext.strs)
+	extNums := make([]int, len(ext.nums))
+	for i, name := range ext.nums {
+		extNums[i] = ti.ExtNumbers[name]
+	}
+
+	tw := terminfoWriter{numSize: 2}
+	magic := magicLegacy
+	for _, ns := range [][]int{nums, extNums} {
+		for _, n := range ns {
+			if n > math.MaxInt16 {
+				tw.numSize, magic = 4, magic32bit
+			}
+		}
+	}
+
+	sizes := []int{len(names) + 1, len(bools), len(nums), len(strs.offs), strs.Len()}
+	for _, size := range sizes {
+		if size > math.MaxInt16 {
+			return nil, fmt.Errorf("terminfo entry %q too large", ti.Name)
+		}
+	}
+	tw.shorts(magic)
+	tw.shorts(sizes...)
+	tw.WriteString(names)
+	tw.WriteByte(0)
+	tw.Write(bools)
+	tw.align()
+	tw.numbers(nums)
+	tw.shorts(strs.offs...)
+	tw.Write(strs.Bytes())
+
+	if len(ext.bools)+len(ext.nums)+len(ext.strs) == 0 {
+		return tw.Bytes(), nil
+	}
+
+	// string values come first in the extended table, followed by names,
+	// whose offsets are relative to the end of the values
+	var extStrs, extNames stringTable
+	for _, name := range ext.strs {
+		if s, def := ti.ExtStrings[name]; def {
+			extStrs.add(s)
+		} else {
+			extStrs.cancelled()
+		}
+	}
+	for _, names := range [][]string{ext.bools, ext.nums, ext.strs} {
+		for _, name := range names {
+			extNames.add(name)
+		}
+	}
+	extBools := bytes.Repeat([]byte{1}, len(ext.bools))
+	size := extStrs.Len() + extNames.Len()
+	if size > math.MaxInt16 {
+		return nil, fmt.Errorf("terminfo entry %q too large", ti.Name)
+	}
+
+	tw.align()
+	tw.shorts(len(ext.bools), len(ext.nums), len(ext.strs),
+		len(extStrs.offs)+len(extNames.offs), size)
+	tw.Write(extBools)
+	tw.align()
+	tw.numbers(extNums)
+	tw.shorts(extStrs.offs...)
+	tw.shorts(extNames.offs...)
+	tw.Write(extStrs.Bytes())
+	tw.Write(extNames.Bytes())
+	return tw.Bytes(), nil
+}
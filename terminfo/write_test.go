@@ -0,0 +1,128 @@
+package terminfo_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi/terminfo"
+)
+
+func TestTerminfo_WriteTo(t *testing.T) {
+	for _, tc := range []struct {
+		term  string
+		magic []byte
+	}{
+		{"xterm", []byte{0x1a, 0x01}},
+		{"xterm-256color", []byte{0x1e, 0x02}},
+		{"tmux-256color", []byte{0x1e, 0x02}},
+	} {
+		t.Run(tc.term, func(t *testing.T) {
+			ti := readTestdata(t, tc.term)
+
+			var buf bytes.Buffer
+			n, err := ti.WriteTo(&buf)
+			require.NoError(t, err)
+			assert.Equal(t, int64(buf.Len()), n)
+			assert.Equal(t, tc.magic, buf.Bytes()[:2], "expected magic number")
+
+			var back terminfo.Terminfo
+			require.NoError(t, back.ReadFrom(bytes.NewReader(buf.Bytes())))
+			assertSameTerminfo(t, ti, &back)
+		})
+	}
+}
+
+func TestTerminfo_emptyStrings(t *testing.T) {
+	tis, err := terminfo.ReadSource(strings.NewReader(`
+empty|present but empty strings,
+	smkx=, rmkx@, Xe=, use=base,
+base|a base entry,
+	enacs=, rmkx=\E>, cnorm=\E[?25h,
+`), nil)
+	require.NoError(t, err)
+	ti := tis[0]
+
+	assertEmpty := func(t *testing.T, ti *terminfo.Terminfo) {
+		s, ok := ti.Str(terminfo.StrKeypadXmit)
+		assert.True(t, ok, "expected empty smkx to be present")
+		assert.Equal(t, "", s)
+		_, ok = ti.Str(terminfo.StrEnaACS)
+		assert.True(t, ok, "expected inherited empty enacs to be present")
+		_, ok = ti.Str(terminfo.StrKeypadLocal)
+		assert.False(t, ok, "expected cancelled rmkx to be absent")
+		_, ok = ti.StrNamed("Xe")
+		assert.True(t, ok, "expected empty extended Xe to be present")
+		s, ok = ti.StrNamed("cnorm")
+		assert.True(t, ok)
+		assert.Equal(t, "\x1b[?25h", s)
+	}
+	assertEmpty(t, ti)
+
+	var buf bytes.Buffer
+	_, err = ti.WriteTo(&buf)
+	require.NoError(t, err)
+	var back terminfo.Terminfo
+	require.NoError(t, back.ReadFrom(bytes.NewReader(buf.Bytes())))
+	assertEmpty(t, &back)
+	assertSameTerminfo(t, ti, &back)
+
+	buf.Reset()
+	_, err = back.WriteSource(&buf)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), " smkx=,")
+	assert.Contains(t, buf.String(), " enacs=,")
+	assert.Contains(t, buf.String(), " rmkx@,")
+
+	eterm, err := terminfo.GetBuiltin("Eterm")
+	require.NoError(t, err)
+	_, ok := eterm.Str(terminfo.StrKeypadXmit)
+	assert.True(t, ok, "expected builtin Eterm smkx to be present")
+}
+
+func TestTerminfo_cancelled(t *testing.T) {
+	tis, err := terminfo.ReadSource(strings.NewReader(`
+cancels|cancelled capabilities,
+	am@, cols@, rmkx@, Xe@, use=base,
+base|a base entry,
+	am, bce, cols#80, lines@, rmkx=\E>, Xe=\E[x,
+`), nil)
+	require.NoError(t, err)
+	ti := tis[0]
+
+	assertCancelled := func(t *testing.T, ti *terminfo.Terminfo) {
+		assert.Equal(t, map[string]bool{
+			"am": true, "cols": true, "rmkx": true, "Xe": true,
+		}, ti.Cancelled, "expected only the entry's own cancellations")
+		assert.False(t, ti.Bool(terminfo.BoolAutoRightMargin), "expected cancelled am")
+		assert.True(t, ti.Bool(terminfo.BoolBackColorErase), "expected inherited bce")
+		_, ok := ti.Num(terminfo.NumColumns)
+		assert.False(t, ok, "expected cancelled cols to be absent")
+		_, ok = ti.Str(terminfo.StrKeypadLocal)
+		assert.False(t, ok, "expected cancelled rmkx to be absent")
+		_, ok = ti.StrNamed("Xe")
+		assert.False(t, ok, "expected cancelled Xe to be absent")
+	}
+	assertCancelled(t, ti)
+
+	var buf bytes.Buffer
+	_, err = ti.WriteTo(&buf)
+	require.NoError(t, err)
+	var back terminfo.Terminfo
+	require.NoError(t, back.ReadFrom(bytes.NewReader(buf.Bytes())))
+	assertCancelled(t, &back)
+	assertSameTerminfo(t, ti, &back)
+
+	buf.Reset()
+	_, err = back.WriteSource(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, ""+
+		"cancels|cancelled capabilities,\n"+
+		"\tam@, bce,\n"+
+		"\tcols@,\n"+
+		"\tXe@, rmkx@,\n",
+		buf.String())
+}
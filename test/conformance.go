@@ -0,0 +1,179 @@
+package anansitest
+
+import (
+	"fmt"
+	"image"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jcorbin/anansi"
+	"github.com/jcorbin/anansi/ansi"
+)
+
+// ConformanceCase is a terminal output byte stream, along with the screen
+// state expected after processing it through an anansi.VirtualScreen.
+type ConformanceCase struct {
+	Name string
+
+	// Size is the screen size; defaults to 80x24.
+	Size image.Point
+
+	// Input is the byte stream to process; if Fixture is set, it names a file
+	// under the testdata/conformance directory to read the stream from
+	// instead, such as output recorded from a real program.
+	Input   string
+	Fixture string
+
+	// Lines are the expected screen rows, each trimmed of trailing blank
+	// cells; any rows not given are expected to be blank.
+	Lines []string
+
+	// Cursor is the expected cursor position, if valid.
+	Cursor ansi.Point
+
+	// Todo, if set, explains why the case is known not to conform yet; such
+	// cases are skipped if they fail, and fail if they unexpectedly pass.
+	Todo string
+}
+
+// ConformanceResult is the outcome of running a ConformanceCase.
+type ConformanceResult struct {
+	Screen anansi.VirtualScreen
+
+	// Unimplemented counts every escape sequence or control rune that the
+	// screen didn't support; see anansi.Screen.Emulate.
+	Unimplemented map[string]int
+}
+
+// Run processes the case input through a new VirtualScreen.
+func (cc ConformanceCase) Run() (res ConformanceResult, err error) {
+	input := []byte(cc.Input)
+	if cc.Fixture != "" {
+		input, err = ioutil.ReadFile(filepath.Join("testdata", "conformance", cc.Fixture))
+		if err != nil {
+			return res, err
+		}
+	}
+	size := cc.Size
+	if size == image.ZP {
+		size = image.Pt(80, 24)
+	}
+	res.Screen.Resize(size)
+	res.Unimplemented = make(map[string]int)
+	anansi.Process(&res, input)
+	return res, nil
+}
+
+// ProcessANSI emulates the given escape value or rune, counting it if
+// unimplemented.
+func (res *ConformanceResult) ProcessANSI(e ansi.Escape, a []byte) {
+	if !res.Screen.Emulate(e, a) {
+		res.Unimplemented[unimplementedName(e, a)]++
+	}
+}
+
+// unimplementedName names an unsupported sequence for reporting; mode
+// sequences include their arguments, since support varies by mode.
+func unimplementedName(e ansi.Escape, a []byte) string {
+	switch e {
+	case ansi.SM, ansi.RM:
+		return fmt.Sprintf("%v %s", e, a)
+	}
+	return e.String()
+}
+
+// ScreenLines returns the screen's rows, trimmed of trailing blank cells,
+// with any trailing blank rows dropped.
+func ScreenLines(sc anansi.Screen) []string {
+	rs, _ := GridRowData(sc.Grid)
+	lines := make([]string, 0, len(rs))
+	for _, row := range rs {
+		var sb strings.Builder
+		for _, r := range row {
+			if r == 0 {
+				r = ' '
+			}
+			sb.WriteRune(r)
+		}
+		lines = append(lines, strings.TrimRight(sb.String(), " "))
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Check fails the test if the result screen lines or cursor differ from those
+// expected by the case; any unimplemented sequences are only logged, so that a
+// case may pass despite ignoring irrelevant ones.
+func (cc ConformanceCase) Check(t testing.TB, res ConformanceResult) {
+	t.Helper()
+	if unimpl := res.UnimplementedNames(); len(unimpl) > 0 {
+		t.Logf("unimplemented: %v", strings.Join(unimpl, ", "))
+	}
+	var failures []string
+	expected := cc.Lines
+	for len(expected) > 0 && strings.TrimRight(expected[len(expected)-1], " ") == "" {
+		expected = expected[:len(expected)-1]
+	}
+	actual := ScreenLines(res.Screen.Screen)
+	if !equalLines(expected, actual) {
+		failures = append(failures, fmt.Sprintf(
+			"screen lines differ:\nexpected:\n%s\nactual:\n%s",
+			quoteLines(expected), quoteLines(actual)))
+	}
+	if cc.Cursor.Valid() && res.Screen.Cursor.Point != cc.Cursor {
+		failures = append(failures, fmt.Sprintf(
+			"expected cursor at %v, got %v", cc.Cursor, res.Screen.Cursor.Point))
+	}
+	switch {
+	case cc.Todo == "":
+		for _, failure := range failures {
+			t.Error(failure)
+		}
+	case len(failures) == 0:
+		t.Errorf("case passes, but is marked TODO: %v", cc.Todo)
+	default:
+		for _, failure := range failures {
+			t.Log(failure)
+		}
+		t.Skipf("TODO: %v", cc.Todo)
+	}
+}
+
+// UnimplementedNames returns the names of unimplemented sequences, sorted,
+// with any repeat count appended.
+func (res ConformanceResult) UnimplementedNames() []string {
+	names := make([]string, 0, len(res.Unimplemented))
+	for name, n := range res.Unimplemented {
+		if n > 1 {
+			name = fmt.Sprintf("%s (x%d)", name, n)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if strings.TrimRight(a[i], " ") != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func quoteLines(lines []string) string {
+	var sb strings.Builder
+	for i, line := range lines {
+		fmt.Fprintf(&sb, "%3d %q\n", i+1, line)
+	}
+	return sb.String()
+}
@@ -0,0 +1,362 @@
+package anansitest_test
+
+import (
+	"image"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi/ansi"
+	. "github.com/jcorbin/anansi/test"
+)
+
+// esctestEDPrepare and esctestELPrepare set up the screen as esctest's ED and
+// EL tests do: "a", "bcd", and "e" on alternate rows with the cursor on the
+// "c", and "abcdefghij" with the cursor on the "e".
+const (
+	esctestEDPrepare = "\x1b[1;1Ha\x1b[3;1Hbcd\x1b[5;1He\x1b[3;2H"
+	esctestELPrepare = "\x1b[1;1Habcdefghij\x1b[1;5H"
+)
+
+// conformanceCases begin with cases ported from esctest, followed by others
+// written after its style, and output recorded from real programs; see
+// testdata/conformance/README.md.
+var conformanceCases = []ConformanceCase{
+	// ported from esctest; see testdata/conformance/README.md
+	{
+		Name:   "esctest CUP_DefaultParams",
+		Input:  "\x1b[3;6H\x1b[H",
+		Cursor: ansi.Pt(1, 1),
+	},
+	{
+		Name:   "esctest CUP_RowOnly",
+		Input:  "\x1b[3;6H\x1b[2H",
+		Cursor: ansi.Pt(1, 2),
+	},
+	{
+		Name:   "esctest CUP_ColumnOnly",
+		Input:  "\x1b[3;6H\x1b[;2H",
+		Cursor: ansi.Pt(2, 1),
+	},
+	{
+		Name:   "esctest CUP_ZeroIsTreatedAsOne",
+		Input:  "\x1b[3;6H\x1b[0;0H",
+		Cursor: ansi.Pt(1, 1),
+	},
+	{
+		Name:   "esctest CUP_OutOfBoundsParams",
+		Input:  "\x1b[34;90H",
+		Cursor: ansi.Pt(80, 24),
+	},
+	{
+		Name:   "esctest ED_Default",
+		Input:  esctestEDPrepare + "\x1b[J",
+		Lines:  []string{"a", "", "b"},
+		Cursor: ansi.Pt(2, 3),
+	},
+	{
+		Name:   "esctest ED_0",
+		Input:  esctestEDPrepare + "\x1b[0J",
+		Lines:  []string{"a", "", "b"},
+		Cursor: ansi.Pt(2, 3),
+	},
+	{
+		Name:   "esctest ED_1",
+		Input:  esctestEDPrepare + "\x1b[1J",
+		Lines:  []string{"", "", "  d", "", "e"},
+		Cursor: ansi.Pt(2, 3),
+	},
+	{
+		Name:   "esctest ED_2",
+		Input:  esctestEDPrepare + "\x1b[2J",
+		Cursor: ansi.Pt(2, 3),
+	},
+	{
+		Name:   "esctest EL_Default",
+		Input:  esctestELPrepare + "\x1b[K",
+		Lines:  []string{"abcd"},
+		Cursor: ansi.Pt(5, 1),
+	},
+	{
+		Name:   "esctest EL_0",
+		Input:  esctestELPrepare + "\x1b[0K",
+		Lines:  []string{"abcd"},
+		Cursor: ansi.Pt(5, 1),
+	},
+	{
+		Name:   "esctest EL_1",
+		Input:  esctestELPrepare + "\x1b[1K",
+		Lines:  []string{"     fghij"},
+		Cursor: ansi.Pt(5, 1),
+	},
+	{
+		Name:   "esctest EL_2",
+		Input:  esctestELPrepare + "\x1b[2K",
+		Cursor: ansi.Pt(5, 1),
+	},
+	{
+		Name:   "esctest ICH_DefaultParam",
+		Input:  "\x1b[1;1Habcdefg\x1b[1;2H\x1b[@",
+		Lines:  []string{"a bcdefg"},
+		Cursor: ansi.Pt(2, 1),
+		Todo:   "insert character",
+	},
+	{
+		Name:   "esctest ICH_ExplicitParam",
+		Input:  "\x1b[1;1Habcdefg\x1b[1;2H\x1b[2@",
+		Lines:  []string{"a  bcdefg"},
+		Cursor: ansi.Pt(2, 1),
+		Todo:   "insert character",
+	},
+	{
+		Name:   "esctest ICH_ScrollOffRightEdge",
+		Input:  "\x1b[1;74Habcdefg\x1b[1;75H\x1b[@",
+		Lines:  []string{strings.Repeat(" ", 73) + "a bcdef"},
+		Cursor: ansi.Pt(75, 1),
+		Todo:   "insert character",
+	},
+	{
+		Name:   "esctest DCH_DefaultParam",
+		Input:  "\x1b[1;1Habcd\x1b[1;2H\x1b[P",
+		Lines:  []string{"acd"},
+		Cursor: ansi.Pt(2, 1),
+		Todo:   "delete character",
+	},
+	{
+		Name:   "esctest DCH_ExplicitParam",
+		Input:  "\x1b[1;1Habcd\x1b[1;2H\x1b[2P",
+		Lines:  []string{"ad"},
+		Cursor: ansi.Pt(2, 1),
+		Todo:   "delete character",
+	},
+	{
+		Name:   "esctest ECH_DefaultParam",
+		Input:  "\x1b[1;1Habc\x1b[1;1H\x1b[X",
+		Lines:  []string{" bc"},
+		Cursor: ansi.Pt(1, 1),
+		Todo:   "erase character",
+	},
+	{
+		Name:   "esctest ECH_ExplicitParam",
+		Input:  "\x1b[1;1Habc\x1b[1;1H\x1b[2X",
+		Lines:  []string{"  c"},
+		Cursor: ansi.Pt(1, 1),
+		Todo:   "erase character",
+	},
+
+	// wrapping
+	{
+		Name:   "autowrap",
+		Size:   image.Pt(5, 3),
+		Input:  "abcdefg",
+		Lines:  []string{"abcde", "fg"},
+		Cursor: ansi.Pt(3, 2),
+	},
+	{
+		Name:   "last column CR",
+		Size:   image.Pt(5, 3),
+		Input:  "abcde\rX",
+		Lines:  []string{"Xbcde"},
+		Cursor: ansi.Pt(2, 1),
+	},
+	{
+		Name:   "DECAWM reset",
+		Size:   image.Pt(5, 3),
+		Input:  "\x1b[?7labcdefg",
+		Lines:  []string{"abcdg"},
+		Cursor: ansi.Pt(5, 1),
+	},
+
+	// scrolling
+	{
+		Name:   "LF scrolls",
+		Size:   image.Pt(5, 3),
+		Input:  "1\r\n2\r\n3\r\n4",
+		Lines:  []string{"2", "3", "4"},
+		Cursor: ansi.Pt(2, 3),
+	},
+	{
+		Name:   "DECSTBM",
+		Size:   image.Pt(5, 4),
+		Input:  "1\r\n2\r\n3\r\n4\x1b[2;3r\x1b[3;1H\nX",
+		Lines:  []string{"1", "3", "X", "4"},
+		Cursor: ansi.Pt(2, 3),
+	},
+	{
+		Name:   "RI",
+		Size:   image.Pt(5, 3),
+		Input:  "a\x1b[H\x1bMb",
+		Lines:  []string{"b", "a"},
+		Cursor: ansi.Pt(2, 1),
+	},
+	{
+		Name:   "DECOM",
+		Size:   image.Pt(5, 4),
+		Input:  "\x1b[2;3r\x1b[?6h\x1b[1;1HX\x1b[9;1HY",
+		Lines:  []string{"", "X", "Y"},
+		Cursor: ansi.Pt(2, 3),
+	},
+
+	// tab stops
+	{
+		Name:   "HT",
+		Input:  "\tX",
+		Lines:  []string{"        X"},
+		Cursor: ansi.Pt(10, 1),
+	},
+	{
+		Name:   "TBC 3",
+		Size:   image.Pt(20, 2),
+		Input:  "\x1b[3g\tX",
+		Lines:  []string{"                   X"},
+		Cursor: ansi.Pt(20, 1),
+	},
+	{
+		Name:   "HTS",
+		Input:  "\x1b[3g\x1b[1;5H\x1bH\r\tX",
+		Lines:  []string{"    X"},
+		Cursor: ansi.Pt(6, 1),
+	},
+	{
+		Name:   "CHT and CBT",
+		Input:  "\x1b[2IX\x1b[2ZY",
+		Lines:  []string{"        Y       X"},
+		Cursor: ansi.Pt(10, 1),
+	},
+
+	// cursor save and restore, charsets, and buffers
+	{
+		Name:   "DECSC and DECRC",
+		Input:  "\x1b[2;3H\x1b7\x1b[HA\x1b8B",
+		Lines:  []string{"A", "  B"},
+		Cursor: ansi.Pt(4, 2),
+	},
+	{
+		Name:   "DEC special graphics",
+		Input:  "\x1b(0lqk\x1b(Bq",
+		Lines:  []string{"┌─┐q"},
+		Cursor: ansi.Pt(5, 1),
+	},
+	{
+		Name:   "alternate screen",
+		Input:  "main\x1b[?1049halt\x1b[?1049l",
+		Lines:  []string{"main"},
+		Cursor: ansi.Pt(5, 1),
+	},
+
+	// known gaps
+	{
+		Name:   "CHA",
+		Input:  "abc\x1b[2GX",
+		Lines:  []string{"aXc"},
+		Cursor: ansi.Pt(3, 1),
+		Todo:   "cursor horizontal absolute",
+	},
+	{
+		Name:   "VPA",
+		Input:  "ab\x1b[3dX",
+		Lines:  []string{"ab", "", "  X"},
+		Cursor: ansi.Pt(4, 3),
+		Todo:   "vertical position absolute",
+	},
+	{
+		Name:   "IL",
+		Size:   image.Pt(5, 3),
+		Input:  "1\r\n2\r\n3\x1b[2H\x1b[L",
+		Lines:  []string{"1", "", "2"},
+		Cursor: ansi.Pt(1, 2),
+		Todo:   "insert line",
+	},
+	{
+		Name:   "DL",
+		Size:   image.Pt(5, 3),
+		Input:  "1\r\n2\r\n3\x1b[1H\x1b[M",
+		Lines:  []string{"2", "3"},
+		Cursor: ansi.Pt(1, 1),
+		Todo:   "delete line",
+	},
+
+	// recorded programs
+	{
+		Name:    "vim",
+		Size:    image.Pt(40, 10),
+		Fixture: "vim.out",
+		Lines: []string{
+			"line 1 of the sample file",
+			"line 2 of the sample file",
+			"line 3 of the sample file",
+			"line 4 of the sample file",
+			"line 5 of the sample file",
+			"line 6 of the sample file",
+			"line 7 of the sample file",
+			"line 8 of the sample file",
+			"line 9 of the sample file",
+			`"/tmp/sample.txt" 20L, 531B`,
+		},
+		Cursor: ansi.Pt(1, 1),
+	},
+	{
+		Name:    "less",
+		Size:    image.Pt(40, 10),
+		Fixture: "less.out",
+		Lines: []string{
+			"line 1 of the sample file",
+			"line 2 of the sample file",
+			"line 3 of the sample file",
+			"line 4 of the sample file",
+			"line 5 of the sample file",
+			"line 6 of the sample file",
+			"line 7 of the sample file",
+			"line 8 of the sample file",
+			"line 9 of the sample file",
+			"/tmp/sample.txt",
+		},
+		Cursor: ansi.Pt(16, 10),
+	},
+	{
+		Name:    "top",
+		Size:    image.Pt(40, 10),
+		Fixture: "top.out",
+		Lines: []string{
+			"top - 22:54:20 up  1:38,  0 user,  load",
+			"Tasks:  63 total,   1 running,  60 sleep",
+			"%Cpu(s):  0.0 us,  0.0 sy,  0.0 ni,100.0",
+			"MiB Mem :   6003.3 total,   3517.9 free,",
+			"MiB Swap:      0.0 total,      0.0 free,",
+			"",
+			"  PID USER      PR  NI    VIRT    RES",
+			"    1 root      20   0   19032   7960",
+			"    2 root      20   0       0      0",
+			"    3 root      20   0       0      0",
+		},
+		Cursor: ansi.Pt(39, 10),
+		Todo: "EL with a pending wrap: tmux keeps the last column, which " +
+			"VirtualScreen erases, as the cursor is still on it for a VT100",
+	},
+}
+
+func TestConformance(t *testing.T) {
+	unimpl := make(map[string][]string)
+	for _, cc := range conformanceCases {
+		cc := cc
+		t.Run(cc.Name, func(t *testing.T) {
+			res, err := cc.Run()
+			require.NoError(t, err)
+			for name := range res.Unimplemented {
+				unimpl[name] = append(unimpl[name], cc.Name)
+			}
+			cc.Check(t, res)
+		})
+	}
+
+	names := make([]string, 0, len(unimpl))
+	for name := range unimpl {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		t.Logf("unimplemented %v in: %v", name, strings.Join(unimpl[name], ", "))
+	}
+}
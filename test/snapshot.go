@@ -0,0 +1,403 @@
+package anansitest
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jcorbin/anansi"
+	"github.com/jcorbin/anansi/ansi"
+)
+
+// update is prefixed, so as not to collide with any flag of the same name
+// defined by the package under test.
+var update = flag.Bool("anansitest.update", false, "update golden snapshot files")
+
+// Snapshot is a human-readable serialization of grid content and cursor
+// state, suitable for golden files. For example, a 6x2 grid with some bold
+// and inverted text, and a visible cursor:
+//
+//	size 6x2
+//	cursor 4,2 visible
+//	|hello |
+//	|world |
+//	attrs
+//	|aaaaa |
+//	|  bbb |
+//	a 1 # bold
+//	b 7 # negative
+//
+// Rows are delimited by '|', with blank (zero) cells written as spaces; space
+// runes read back as zero. Any non-zero cell attributes follow the rows as a
+// map of keys, which are then defined as SGR arguments; any trailing comment
+// is ignored. The cursor line is omitted for a zero cursor, and gets an "attr"
+// suffix for any cursor attribute.
+type Snapshot struct {
+	Grid   anansi.Grid
+	Cursor anansi.Cursor
+}
+
+// GridSnapshot returns a snapshot of the given grid; its bounds are
+// normalized to originate at 1,1.
+func GridSnapshot(g anansi.Grid) Snapshot {
+	rs, as := GridRowData(g)
+	var snap Snapshot
+	snap.Grid.Resize(g.Bounds().Size())
+	for y := range rs {
+		i := y * snap.Grid.Stride
+		copy(snap.Grid.Rune[i:], rs[y])
+		copy(snap.Grid.Attr[i:], as[y])
+	}
+	return snap
+}
+
+// ScreenSnapshot returns a snapshot of the given screen's grid and cursor.
+func ScreenSnapshot(sc anansi.Screen) Snapshot {
+	snap := GridSnapshot(sc.Grid)
+	snap.Cursor.Point = sc.Cursor.Point
+	snap.Cursor.Attr = sc.Cursor.Attr
+	snap.Cursor.Visible = sc.Cursor.Visible
+	return snap
+}
+
+// String returns the serialized snapshot, or a description of why it can't
+// be serialized.
+func (snap Snapshot) String() string {
+	b, err := snap.MarshalText()
+	if err != nil {
+		return fmt.Sprintf("<unserializable snapshot: %v>", err)
+	}
+	return string(b)
+}
+
+// MarshalText serializes the snapshot. Grids containing control runes can't
+// be serialized, since ParseSnapshot would reject them.
+func (snap Snapshot) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	size := snap.Grid.Bounds().Size()
+	fmt.Fprintf(&buf, "size %dx%d\n", size.X, size.Y)
+	if cur := snap.Cursor; cur.Point != (ansi.Point{}) || cur.Visible || cur.Attr != 0 {
+		vis := "hidden"
+		if cur.Visible {
+			vis = "visible"
+		}
+		fmt.Fprintf(&buf, "cursor %d,%d %s", cur.X, cur.Y, vis)
+		if cur.Attr != 0 {
+			fmt.Fprintf(&buf, " attr %s", sgrArgs(cur.Attr))
+		}
+		buf.WriteByte('\n')
+	}
+
+	rs, as := GridRowData(snap.Grid)
+	for y, row := range rs {
+		buf.WriteByte('|')
+		for _, r := range row {
+			if r == 0 {
+				r = ' '
+			} else if unicode.IsControl(r) {
+				return nil, fmt.Errorf("snapshot row %d has control rune %q", y+1, r)
+			}
+			buf.WriteRune(r)
+		}
+		buf.WriteString("|\n")
+	}
+
+	var (
+		keys  = make(map[ansi.SGRAttr]rune)
+		attrs []ansi.SGRAttr
+	)
+	for _, row := range as {
+		for _, a := range row {
+			if _, seen := keys[a]; a != 0 && !seen {
+				key, ok := attrKey(len(attrs))
+				if !ok {
+					return nil, errors.New("too many distinct attributes to snapshot")
+				}
+				keys[a] = key
+				attrs = append(attrs, a)
+			}
+		}
+	}
+	if len(attrs) == 0 {
+		return buf.Bytes(), nil
+	}
+	buf.WriteString("attrs\n")
+	for _, row := range as {
+		buf.WriteByte('|')
+		for _, a := range row {
+			key := ' '
+			if a != 0 {
+				key = keys[a]
+			}
+			buf.WriteRune(key)
+		}
+		buf.WriteString("|\n")
+	}
+	for _, a := range attrs {
+		fmt.Fprintf(&buf, "%c %s # %v\n", keys[a], sgrArgs(a), a)
+	}
+	return buf.Bytes(), nil
+}
+
+const attrKeys = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+func attrKey(i int) (rune, bool) {
+	if i < len(attrKeys) {
+		return rune(attrKeys[i]), true
+	}
+	return 0, false
+}
+
+// sgrArgs returns the SGR control sequence arguments that set attr.
+func sgrArgs(attr ansi.SGRAttr) string {
+	s := attr.ControlString()
+	return s[2 : len(s)-1] // trim CSI and final byte
+}
+
+// ParseSnapshot parses a snapshot serialized by Snapshot.MarshalText.
+func ParseSnapshot(s string) (snap Snapshot, err error) {
+	err = snap.UnmarshalText([]byte(s))
+	return snap, err
+}
+
+// UnmarshalText parses a snapshot serialized by MarshalText.
+func (snap *Snapshot) UnmarshalText(text []byte) error {
+	var (
+		sc       = bufio.NewScanner(bytes.NewReader(text))
+		lineNo   int
+		rows     [][]rune
+		keyRows  [][]rune
+		inAttrs  bool
+		legend   = make(map[rune]ansi.SGRAttr)
+		haveSize bool
+		width    int
+		height   int
+	)
+	*snap = Snapshot{}
+	fail := func(format string, args ...interface{}) error {
+		return fmt.Errorf("snapshot line %d: %s", lineNo, fmt.Sprintf(format, args...))
+	}
+	for sc.Scan() {
+		lineNo++
+		line := sc.Text()
+		switch {
+		case line == "":
+
+		case strings.HasPrefix(line, "|"):
+			if len(line) < 2 || !strings.HasSuffix(line, "|") {
+				return fail("unterminated row")
+			}
+			row := []rune(line[1 : len(line)-1])
+			if inAttrs {
+				keyRows = append(keyRows, row)
+			} else {
+				rows = append(rows, row)
+			}
+
+		case strings.HasPrefix(line, "size "):
+			if _, err := fmt.Sscanf(line, "size %dx%d", &width, &height); err != nil {
+				return fail("invalid size: %v", err)
+			}
+			haveSize = true
+
+		case strings.HasPrefix(line, "cursor "):
+			if err := parseSnapshotCursor(&snap.Cursor, strings.Fields(line)[1:]); err != nil {
+				return fail("%v", err)
+			}
+
+		case line == "attrs":
+			inAttrs = true
+
+		case inAttrs:
+			if i := strings.Index(line, " #"); i >= 0 {
+				line = line[:i]
+			}
+			key, n := utf8.DecodeRuneInString(line)
+			if key == ' ' || line[n:] == "" || line[n] != ' ' {
+				return fail("invalid attr definition %q", line)
+			}
+			attr, _, err := ansi.DecodeSGR([]byte(strings.TrimSpace(line[n:])))
+			if err != nil {
+				return fail("invalid attr definition: %v", err)
+			}
+			legend[key] = attr
+
+		default:
+			return fail("unexpected %q", line)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+
+	if !haveSize {
+		height = len(rows)
+		if height > 0 {
+			width = len(rows[0])
+		}
+	}
+	if len(rows) != height {
+		return fmt.Errorf("snapshot has %d rows, expected %d", len(rows), height)
+	}
+	if keyRows != nil && len(keyRows) != height {
+		return fmt.Errorf("snapshot has %d attr rows, expected %d", len(keyRows), height)
+	}
+	snap.Grid.Resize(image.Pt(width, height))
+	for y, row := range rows {
+		if len(row) != width {
+			return fmt.Errorf("snapshot row %d has width %d, expected %d", y+1, len(row), width)
+		}
+		for x, r := range row {
+			if unicode.IsControl(r) {
+				return fmt.Errorf("snapshot row %d has control rune %q", y+1, r)
+			}
+			if r == ' ' {
+				r = 0
+			}
+			snap.Grid.Rune[y*width+x] = r
+		}
+	}
+	for y, row := range keyRows {
+		if len(row) != width {
+			return fmt.Errorf("snapshot attr row %d has width %d, expected %d", y+1, len(row), width)
+		}
+		for x, key := range row {
+			if key == ' ' {
+				continue
+			}
+			attr, defined := legend[key]
+			if !defined {
+				return fmt.Errorf("snapshot attr row %d has undefined key %q", y+1, key)
+			}
+			snap.Grid.Attr[y*width+x] = attr
+		}
+	}
+	return nil
+}
+
+func parseSnapshotCursor(cur *anansi.Cursor, fields []string) error {
+	if len(fields) < 2 {
+		return errors.New("invalid cursor, expected position and visibility")
+	}
+	if _, err := fmt.Sscanf(fields[0], "%d,%d", &cur.X, &cur.Y); err != nil {
+		return fmt.Errorf("invalid cursor position: %v", err)
+	}
+	switch fields[1] {
+	case "visible":
+		cur.Visible = true
+	case "hidden":
+	default:
+		return fmt.Errorf("invalid cursor visibility %q", fields[1])
+	}
+	switch {
+	case len(fields) == 2:
+	case len(fields) == 4 && fields[2] == "attr":
+		attr, _, err := ansi.DecodeSGR([]byte(fields[3]))
+		if err != nil {
+			return fmt.Errorf("invalid cursor attr: %v", err)
+		}
+		cur.Attr = attr
+	default:
+		return fmt.Errorf("unexpected cursor fields %q", fields[2:])
+	}
+	return nil
+}
+
+// maxDiffCells limits how many cell differences Diff reports.
+const maxDiffCells = 20
+
+// Diff returns a cell-level report of differences between the snapshot and
+// another; returns nil if they are equal. Blank and space cells compare equal,
+// since they serialize the same.
+func (snap Snapshot) Diff(other Snapshot) (report []string) {
+	as, bs := snap.Grid.Bounds().Size(), other.Grid.Bounds().Size()
+	if as != bs {
+		report = append(report, fmt.Sprintf("size %dx%d != %dx%d", as.X, as.Y, bs.X, bs.Y))
+	}
+	if ac, bc := snap.Cursor, other.Cursor; ac.Point != bc.Point || ac.Visible != bc.Visible || ac.Attr != bc.Attr {
+		report = append(report, fmt.Sprintf("cursor %v != %v", ac, bc))
+	}
+	if as != bs {
+		return report
+	}
+	ars, aas := GridRowData(snap.Grid)
+	brs, bas := GridRowData(other.Grid)
+	n := 0
+	for y := range ars {
+		for x := range ars[y] {
+			ar, br := blankRune(ars[y][x]), blankRune(brs[y][x])
+			aa, ba := aas[y][x], bas[y][x]
+			if ar == br && aa == ba {
+				continue
+			}
+			if n++; n > maxDiffCells {
+				continue
+			}
+			report = append(report, fmt.Sprintf("cell %d,%d: %s != %s",
+				x+1, y+1, describeCell(ar, aa), describeCell(br, ba)))
+		}
+	}
+	if n > maxDiffCells {
+		report = append(report, fmt.Sprintf("... and %d more cells", n-maxDiffCells))
+	}
+	return report
+}
+
+func blankRune(r rune) rune {
+	if r == 0 {
+		return ' '
+	}
+	return r
+}
+
+func describeCell(r rune, a ansi.SGRAttr) string {
+	if a == 0 {
+		return strconv.QuoteRune(r)
+	}
+	return fmt.Sprintf("%q [%v]", r, a)
+}
+
+// CheckGolden compares the snapshot to the named golden file under the
+// testdata directory, failing the test with a diff report if they differ, or
+// if the file doesn't exist. When tests are run with the -anansitest.update
+// flag, the golden file is (re)written instead.
+func CheckGolden(t testing.TB, name string, snap Snapshot) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("unable to create golden directory: %v", err)
+		}
+		b, err := snap.MarshalText()
+		if err != nil {
+			t.Fatalf("unable to serialize snapshot: %v", err)
+		}
+		if err := ioutil.WriteFile(path, b, 0644); err != nil {
+			t.Fatalf("unable to update golden file: %v", err)
+		}
+		return
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read golden file (run with -anansitest.update to create it): %v", err)
+	}
+	expected, err := ParseSnapshot(string(b))
+	if err != nil {
+		t.Fatalf("unable to parse golden file %v: %v", path, err)
+	}
+	if report := expected.Diff(snap); len(report) > 0 {
+		t.Errorf("snapshot differs from %v (expected != actual):\n  %s\nactual:\n%v",
+			path, strings.Join(report, "\n  "), snap)
+	}
+}
@@ -0,0 +1,129 @@
+package anansitest_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi"
+	"github.com/jcorbin/anansi/ansi"
+	. "github.com/jcorbin/anansi/test"
+)
+
+func TestSnapshot(t *testing.T) {
+	var vs anansi.VirtualScreen
+	vs.Resize(image.Pt(6, 3))
+	vs.WriteString("\x1b[1mhello\x1b[0m\r\nwo\x1b[7;31mrl\x1b[0md\r\n\x1b[1m\x1b[?25h")
+
+	snap := ScreenSnapshot(vs.Screen)
+	assert.Equal(t, ""+
+		"size 6x3\n"+
+		"cursor 1,3 visible attr 1\n"+
+		"|hello |\n"+
+		"|world |\n"+
+		"|      |\n"+
+		"attrs\n"+
+		"|aaaaa |\n"+
+		"|  bb  |\n"+
+		"|      |\n"+
+		"a 1 # bold\n"+
+		"b 7;31 # negative fg:red\n",
+		snap.String())
+
+	parsed, err := ParseSnapshot(snap.String())
+	require.NoError(t, err)
+	assert.Nil(t, snap.Diff(parsed))
+	assert.True(t, parsed.Grid.Eq(vs.Grid, 0), "expected parsed grid to equal the original")
+	assert.Equal(t, vs.Cursor.Point, parsed.Cursor.Point)
+	assert.Equal(t, vs.Cursor.Attr, parsed.Cursor.Attr)
+	assert.True(t, parsed.Cursor.Visible, "expected parsed cursor to be visible")
+
+	// grid snapshots omit the cursor, and any absent attrs block
+	g := ParseGridLines([]string{"ab", "cd"})
+	assert.Equal(t, "size 2x2\n|ab|\n|cd|\n", GridSnapshot(g).String())
+	parsed, err = ParseSnapshot("|ab|\n|cd|\n")
+	require.NoError(t, err)
+	assert.True(t, parsed.Grid.Eq(g, 0), "expected size to be inferred")
+
+	// snapshots with more distinct attributes than keys can't be serialized
+	var many anansi.Grid
+	many.Resize(image.Pt(63, 1)) // one more than there are keys
+	for i := range many.Attr {
+		many.Rune[i], many.Attr[i] = 'x', ansi.RGB(uint8(i), 0, 0).FG()
+	}
+	_, err = GridSnapshot(many).MarshalText()
+	assert.EqualError(t, err, "too many distinct attributes to snapshot")
+	assert.Equal(t, "<unserializable snapshot: too many distinct attributes to snapshot>",
+		GridSnapshot(many).String())
+
+	// nor can those containing control runes, which ParseSnapshot rejects
+	var ctl anansi.Grid
+	ctl.Resize(image.Pt(2, 2))
+	ctl.Rune[0], ctl.Rune[3] = 'a', '\t'
+	_, err = GridSnapshot(ctl).MarshalText()
+	assert.EqualError(t, err, `snapshot row 2 has control rune '\t'`)
+}
+
+func TestSnapshot_diff(t *testing.T) {
+	a, err := ParseSnapshot("" +
+		"cursor 2,1 visible\n" +
+		"|abc|\n" +
+		"|def|\n" +
+		"attrs\n" +
+		"|a  |\n" +
+		"|   |\n" +
+		"a 1\n")
+	require.NoError(t, err)
+
+	b := a
+	b.Grid = ParseGridLines([]string{"\x1b[1ma\x1b[0mbc", "dEf"})
+	b.Grid.Rune[2] = 0
+	assert.Equal(t, []string{
+		`cell 3,1: 'c' != ' '`,
+		`cell 2,2: 'e' != 'E'`,
+	}, a.Diff(b))
+
+	b.Cursor.Point = ansi.Pt(1, 1)
+	b.Grid = ParseGridLines([]string{"abc", "def"})
+	assert.Equal(t, []string{
+		`cursor @(2,1) a: v:true != @(1,1) a: v:true`,
+		`cell 1,1: 'a' [bold] != 'a'`,
+	}, a.Diff(b))
+
+	b.Grid = ParseGridLines([]string{"abc"})
+	assert.Equal(t, []string{
+		`size 3x2 != 3x1`,
+		`cursor @(2,1) a: v:true != @(1,1) a: v:true`,
+	}, a.Diff(b))
+}
+
+func TestSnapshot_errors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+		err  string
+	}{
+		{"unterminated", "|ab\n", "snapshot line 1: unterminated row"},
+		{"ragged", "|ab|\n|c|\n", "snapshot row 2 has width 1, expected 2"},
+		{"size", "size 2x2\n|ab|\n", "snapshot has 1 rows, expected 2"},
+		{"undefined key", "|ab|\nattrs\n|x |\n", `snapshot attr row 1 has undefined key 'x'`},
+		{"bad cursor", "cursor 1,1 maybe\n", `snapshot line 1: invalid cursor visibility "maybe"`},
+		{"junk", "hello\n", `snapshot line 1: unexpected "hello"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseSnapshot(tc.in)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestCheckGolden(t *testing.T) {
+	res, err := ConformanceCase{
+		Size:    image.Pt(40, 10),
+		Fixture: "vim.out",
+	}.Run()
+	require.NoError(t, err)
+	CheckGolden(t, "conformance/vim", ScreenSnapshot(res.Screen.Screen))
+}
@@ -0,0 +1,49 @@
+package anansi
+
+import (
+	"github.com/jcorbin/anansi/ansi"
+)
+
+// Title supports setting the terminal window title as a Term Context.
+//
+// Every Enter pushes the user's prior title onto the terminal's title stack
+// (XTWINOPS 22) and then sets Title; every Exit pops the prior title back
+// (XTWINOPS 23). This makes it safe to use under Term.RunWithout and
+// Term.Suspend. Terminals that don't implement a title stack ignore the push
+// and pop, leaving Title in place after exit.
+type Title struct {
+	Title string
+
+	term *Term
+	buf  Buffer
+}
+
+// Set changes Title, updating the terminal's window title if active. Any
+// control characters in title are dropped by ansi.SetWindowTitle.
+func (tt *Title) Set(title string) error {
+	tt.Title = title
+	if tt.term == nil {
+		return nil
+	}
+	tt.buf.Reset()
+	tt.buf.WriteSeq(ansi.SetWindowTitle(title))
+	return tt.term.Flush(&tt.buf)
+}
+
+// Enter saves the prior title, and sets Title.
+func (tt *Title) Enter(term *Term) error {
+	tt.term = term
+	tt.buf.Reset()
+	tt.buf.WriteSeq(ansi.PushTitle(), ansi.SetWindowTitle(tt.Title))
+	return term.Flush(&tt.buf)
+}
+
+// Exit restores the prior title.
+func (tt *Title) Exit(term *Term) error {
+	tt.term = nil
+	tt.buf.Reset()
+	tt.buf.WriteSeq(ansi.PopTitle())
+	return term.Flush(&tt.buf)
+}
+
+var _ Context = &Title{}
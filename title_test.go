@@ -0,0 +1,39 @@
+package anansi_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi"
+)
+
+func TestTitle(t *testing.T) {
+	outR, outW, err := blockingPipe()
+	require.NoError(t, err)
+	defer outR.Close()
+
+	term := anansi.NewTerm(nil, outW)
+	title := anansi.Title{Title: "hello"}
+	require.NoError(t, title.Enter(term))
+	require.NoError(t, title.Set("wor\x1b\ald")) // controls are dropped
+	require.NoError(t, title.Exit(term))
+	require.NoError(t, title.Set("again")) // not written while inactive
+	require.NoError(t, title.Enter(term))
+	require.NoError(t, title.Exit(term))
+	outW.Close()
+
+	var out bytes.Buffer
+	_, err = io.Copy(&out, outR)
+	require.NoError(t, err)
+	assert.Equal(t, ""+
+		"\x1b[22;0t\x1b]2;hello\x1b\\"+
+		"\x1b]2;world\x1b\\"+
+		"\x1b[23;0t"+
+		"\x1b[22;0t\x1b]2;again\x1b\\"+
+		"\x1b[23;0t",
+		out.String())
+}
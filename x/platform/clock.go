@@ -0,0 +1,183 @@
+package platform
+
+import (
+	"time"
+
+	"github.com/jcorbin/anansi"
+)
+
+// Clock provides time to a Platform: its Ticker waits on the clock between
+// frames, and the times that it returns become the Platform.Time that
+// Telemetry collects, and that clients see as Context.Time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// Start starts ticking every period d, replacing any prior ticking.
+	Start(d time.Duration)
+
+	// Stop stops any ticking.
+	Stop()
+
+	// Wait blocks until the next tick, returning its time; returns zero time
+	// if the clock isn't ticking, has no more ticks, or if done is closed
+	// first.
+	Wait(done <-chan struct{}) time.Time
+}
+
+// WithClock sets the platform's clock, which defaults to a RealClock, or to a
+// FixedStepClock when headless (see Headless.WithClock).
+func WithClock(clock Clock) Option {
+	return optionFunc(func(p *Platform) error {
+		p.ticker.clock = clock
+		return nil
+	})
+}
+
+// RealClock is a Clock that ticks in wall time.
+type RealClock struct {
+	t *time.Ticker
+}
+
+// Now returns time.Now().
+func (rc *RealClock) Now() time.Time { return time.Now() }
+
+// Start starts a new time.Ticker.
+func (rc *RealClock) Start(d time.Duration) {
+	rc.Stop()
+	rc.t = time.NewTicker(d)
+}
+
+// Stop stops any running time.Ticker.
+func (rc *RealClock) Stop() {
+	if rc.t != nil {
+		rc.t.Stop()
+		rc.t = nil
+	}
+}
+
+// Wait blocks for the next time.Ticker time.
+func (rc *RealClock) Wait(done <-chan struct{}) time.Time {
+	if rc.t == nil {
+		return time.Time{}
+	}
+	select {
+	case t := <-rc.t.C:
+		return t
+	case <-done:
+		return time.Time{}
+	}
+}
+
+// FixedStepClock is a deterministic Clock whose time only advances, by a
+// fixed Step, every time that it's waited on; waiting never blocks.
+type FixedStepClock struct {
+	// T is the current time; a zero T starts at the Unix epoch.
+	T time.Time
+
+	// Step is how far T advances on every tick; defaults to the tick period.
+	Step time.Duration
+
+	period  time.Duration
+	ticking bool
+}
+
+// Now returns the current time, T.
+func (fc *FixedStepClock) Now() time.Time {
+	if fc.T.IsZero() {
+		fc.T = time.Unix(0, 0).UTC()
+	}
+	return fc.T
+}
+
+// Start starts ticking; d is only used if Step is zero.
+func (fc *FixedStepClock) Start(d time.Duration) {
+	fc.period = d
+	fc.ticking = true
+}
+
+// Stop stops ticking.
+func (fc *FixedStepClock) Stop() { fc.ticking = false }
+
+// Wait advances T by Step, and returns it.
+func (fc *FixedStepClock) Wait(done <-chan struct{}) time.Time {
+	if !fc.ticking || isClosed(done) {
+		return time.Time{}
+	}
+	step := fc.Step
+	if step == 0 {
+		step = fc.period
+	}
+	fc.T = fc.Now().Add(step)
+	return fc.T
+}
+
+// ReplayClock is a deterministic Clock that ticks through the timestamps of
+// recorded input frames, skipping untimed (message) frames; it runs out of
+// ticks after the last frame. Waiting doesn't block, unless paced by another
+// clock.
+type ReplayClock struct {
+	Frames anansi.InputReplay
+
+	// Pace, if not nil, is waited on before every tick; e.g. a RealClock to
+	// replay at (about) the recorded frame rate.
+	Pace Clock
+
+	i       int
+	ticking bool
+}
+
+// Now returns the timestamp of the current frame, or zero time if there are
+// no more frames.
+func (rc *ReplayClock) Now() time.Time {
+	for ; rc.i < len(rc.Frames); rc.i++ {
+		if t := rc.Frames[rc.i].T; !t.IsZero() {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Start starts ticking, and any Pace clock.
+func (rc *ReplayClock) Start(d time.Duration) {
+	if rc.Pace != nil {
+		rc.Pace.Start(d)
+	}
+	rc.ticking = true
+}
+
+// Stop stops ticking, and any Pace clock.
+func (rc *ReplayClock) Stop() {
+	if rc.Pace != nil {
+		rc.Pace.Stop()
+	}
+	rc.ticking = false
+}
+
+// Wait advances to the next timed frame, returning its timestamp.
+func (rc *ReplayClock) Wait(done <-chan struct{}) time.Time {
+	if !rc.ticking {
+		return time.Time{}
+	}
+	if rc.Pace != nil {
+		if rc.Pace.Wait(done).IsZero() {
+			return time.Time{}
+		}
+	} else if isClosed(done) {
+		return time.Time{}
+	}
+	if rc.Now().IsZero() {
+		return time.Time{}
+	}
+	rc.i++
+	return rc.Now()
+}
+
+func isClosed(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,159 @@
+package platform_test
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi"
+	"github.com/jcorbin/anansi/ansi"
+	anansitest "github.com/jcorbin/anansi/test"
+	. "github.com/jcorbin/anansi/x/platform"
+)
+
+var epoch = time.Unix(0, 0).UTC()
+
+func TestFixedStepClock(t *testing.T) {
+	fc := &FixedStepClock{}
+	assert.Equal(t, epoch, fc.Now())
+	assert.True(t, fc.Wait(nil).IsZero(), "expected no ticks before Start")
+
+	fc.Start(10 * time.Millisecond)
+	assert.Equal(t, epoch.Add(10*time.Millisecond), fc.Wait(nil))
+	assert.Equal(t, epoch.Add(20*time.Millisecond), fc.Wait(nil))
+	assert.Equal(t, epoch.Add(20*time.Millisecond), fc.Now())
+
+	fc.Step = time.Second
+	assert.Equal(t, epoch.Add(1020*time.Millisecond), fc.Wait(nil))
+
+	done := make(chan struct{})
+	close(done)
+	assert.True(t, fc.Wait(done).IsZero(), "expected no ticks once done")
+	fc.Stop()
+	assert.True(t, fc.Wait(nil).IsZero(), "expected no ticks after Stop")
+}
+
+func TestReplayClock(t *testing.T) {
+	rc := &ReplayClock{Frames: anansi.InputReplay{
+		{M: []byte("resize:10,2")},
+		{T: epoch.Add(time.Second)},
+		{T: epoch.Add(2 * time.Second)},
+		{M: []byte("hello")},
+		{T: epoch.Add(5 * time.Second)},
+	}}
+	assert.Equal(t, epoch.Add(time.Second), rc.Now())
+	rc.Start(time.Millisecond)
+	assert.Equal(t, epoch.Add(2*time.Second), rc.Wait(nil))
+	assert.Equal(t, epoch.Add(5*time.Second), rc.Wait(nil))
+	assert.True(t, rc.Wait(nil).IsZero(), "expected no ticks after the last frame")
+	assert.True(t, rc.Now().IsZero(), "expected no time after the last frame")
+
+	// paced by another clock
+	pace := &FixedStepClock{}
+	rc = &ReplayClock{Frames: anansi.InputReplay{{T: epoch}, {T: epoch.Add(time.Second)}}, Pace: pace}
+	assert.True(t, rc.Wait(nil).IsZero(), "expected no ticks before Start")
+	rc.Start(time.Millisecond)
+	assert.Equal(t, epoch.Add(time.Second), rc.Wait(nil))
+	assert.Equal(t, epoch.Add(time.Millisecond), pace.Now())
+	rc.Stop()
+	assert.True(t, pace.Wait(nil).IsZero(), "expected pace clock to be stopped")
+}
+
+func TestRealClock(t *testing.T) {
+	var rc RealClock
+	assert.True(t, rc.Wait(nil).IsZero(), "expected no ticks before Start")
+	before := time.Now()
+	rc.Start(time.Millisecond)
+	defer rc.Stop()
+	assert.False(t, rc.Wait(nil).Before(before), "expected a tick after start")
+	done := make(chan struct{})
+	close(done)
+	rc.Stop()
+	assert.True(t, rc.Wait(done).IsZero(), "expected no ticks after Stop")
+}
+
+// timeLogger is a test client that prints frame times, as seen by the client
+// and by telemetry, along with any runes typed.
+type timeLogger struct {
+	start time.Time
+	typed string
+}
+
+func (tl *timeLogger) Update(ctx *Context) error {
+	if tl.start.IsZero() {
+		tl.start = ctx.Time
+	}
+	for eid, kind := range ctx.Input.Type {
+		if kind == EventRune {
+			tl.typed += string(ctx.Input.Rune(eid))
+		}
+	}
+	ctx.Input.Clear()
+	ctx.Output.To(ansi.Pt(1, 1))
+	fmt.Fprintf(ctx.Output, "at %v fps %.2f\r\n", ctx.Time.Sub(tl.start), ctx.FPS())
+	fmt.Fprintf(ctx.Output, "typed %q", tl.typed)
+	return nil
+}
+
+func TestHeadless_withClock(t *testing.T) {
+	var tl timeLogger
+	h := NewHeadless(image.Pt(30, 2), &tl).WithClock(&FixedStepClock{Step: 250 * time.Millisecond})
+	defer h.Close()
+	h.Period = time.Hour // unused by any clock but the default
+	require.NoError(t, h.Frames(3))
+	lines := anansitest.ScreenLines(h.Screen.Screen)
+	require.NotEmpty(t, lines)
+	assert.True(t, strings.HasPrefix(lines[0], "at 500ms "), "expected time from clock, got %q", lines[0])
+
+	// the frame already waited for still runs after the clock stops
+	require.NoError(t, h.Close())
+	require.NoError(t, h.Frame())
+	assert.True(t, IsClockStopped(h.Frame()), "expected clock stopped error")
+}
+
+func TestHeadless_replay(t *testing.T) {
+	frames := anansi.InputReplay{
+		{T: epoch.Add(10 * time.Millisecond), B: []byte("a")},
+		{T: epoch.Add(25 * time.Millisecond)},
+		{M: []byte("noise")},
+		{T: epoch.Add(60 * time.Millisecond), B: []byte("bc")},
+		{T: epoch.Add(70 * time.Millisecond)},
+	}
+
+	replay := func() (snaps []string) {
+		h := NewHeadless(image.Pt(30, 2), &timeLogger{})
+		defer h.Close()
+		h.Replay(frames)
+		for {
+			err := h.Frame()
+			if IsReplayDone(err) {
+				return snaps
+			}
+			require.NoError(t, err)
+			snaps = append(snaps, anansitest.ScreenSnapshot(h.Screen.Screen).String())
+		}
+	}
+
+	snaps := replay()
+	require.Len(t, snaps, 4)
+	assert.Equal(t, []string{
+		`at 0s fps 0.00`,
+		`typed "a"`,
+	}, anansitest.ScreenLines(mustParseSnapshot(t, snaps[0])))
+	assert.Equal(t, []string{
+		`at 60ms fps 3.25`,
+		`typed "abc"`,
+	}, anansitest.ScreenLines(mustParseSnapshot(t, snaps[3])))
+	assert.Equal(t, snaps, replay(), "expected identical frames on replay")
+}
+
+func mustParseSnapshot(t *testing.T, s string) anansi.Screen {
+	snap, err := anansitest.ParseSnapshot(s)
+	require.NoError(t, err)
+	return anansi.Screen{Grid: snap.Grid}
+}
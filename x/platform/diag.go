@@ -218,7 +218,11 @@ func (tel *Telemetry) update(p *Platform) error {
 		if tel.LogTiming && timingFrame {
 			tel.coll.timing = tel.Timing.ds
 		}
-		if stalls := p.term.Stalls(consumeStalls); stalls != nil {
+		var stalls []time.Duration
+		if p.term != nil { // no stalls when running headless
+			stalls = p.term.Stalls(consumeStalls)
+		}
+		if stalls != nil {
 			if tel.LogStallData {
 				tel.coll.stalls = stalls
 			}
@@ -0,0 +1,173 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"time"
+
+	"github.com/jcorbin/anansi"
+	"github.com/jcorbin/anansi/ansi"
+)
+
+var errClockStopped = errors.New("clock stopped")
+
+// IsClockStopped returns true if the error was due to a headless platform's
+// clock stopping, e.g. a ReplayClock running out of frames.
+func IsClockStopped(err error) bool {
+	return err == errClockStopped
+}
+
+// Headless runs a Client without a terminal, e.g. for testing under an
+// ordinary `go test`. Each Frame runs the client against scripted input, and
+// then flushes its output into an in-memory Screen, as it would to a real
+// terminal; the platform Time then advances by Period, or to the next tick of
+// any clock set by WithClock.
+type Headless struct {
+	*Platform
+
+	// Screen is an emulated terminal screen, updated after each frame.
+	Screen anansi.VirtualScreen
+
+	// Period is how far Time advances after each frame, unless WithClock (or
+	// Replay) has set another clock; defaults to the platform's default frame
+	// rate.
+	Period time.Duration
+
+	step   *FixedStepClock // the default clock, stepping by Period
+	input  []byte
+	resize image.Point
+}
+
+// NewHeadless creates a headless platform for the given client, with screens
+// of the given size. Its Time starts at the Unix epoch, and may be changed
+// before the first Frame.
+func NewHeadless(size image.Point, client Client) *Headless {
+	h := &Headless{
+		Platform: &Platform{client: client},
+		Period:   time.Second / defaultFrameRate,
+		step:     &FixedStepClock{},
+	}
+	h.ticker.d = h.Period
+	h.initTelemetry()
+	h.WithClock(h.step)
+	h.screen.Resize(size)
+	h.Screen.Resize(size)
+	h.LastSize = size
+	return h
+}
+
+// WithClock sets the headless platform's clock, as the WithClock option does
+// for New, taking Time from it; returns h for chaining, e.g.:
+//
+//	h := NewHeadless(size, client).WithClock(&FixedStepClock{Step: time.Millisecond})
+func (h *Headless) WithClock(clock Clock) *Headless {
+	h.ticker.clock = clock
+	_ = h.ticker.Enter(nil)
+	h.Time = h.ticker.Now()
+	return h
+}
+
+// Close stops the headless platform's clock; always returns nil error.
+func (h *Headless) Close() error {
+	return h.ticker.Exit(nil)
+}
+
+// Replay replays recorded input frames, as Ctrl-R does after recording
+// under a terminal, but on a ReplayClock over the same frames: frame times,
+// and so client output, are the same on every replay. Once all frames have
+// been replayed, Frame returns an error satisfying IsReplayDone.
+func (h *Headless) Replay(frames anansi.InputReplay) {
+	h.replay = &replay{
+		input: frames,
+		cur:   frames,
+		size:  h.screen.Bounds().Size(),
+	}
+	h.WithClock(&ReplayClock{Frames: frames})
+}
+
+// Input queues raw input bytes, such as keys typed, for the next Frame.
+func (h *Headless) Input(s string) {
+	h.input = append(h.input, s...)
+}
+
+// Keys queues escape sequences, such as ansi.CUU for an arrow key, for the
+// next Frame.
+func (h *Headless) Keys(keys ...ansi.Escape) {
+	for _, key := range keys {
+		h.input = key.AppendTo(h.input)
+	}
+}
+
+// Mouse queues a mouse event for the next Frame, using the SGR extended
+// reporting encoding that the platform requests from real terminals.
+func (h *Headless) Mouse(state ansi.MouseState, pt ansi.Point) {
+	final := 'M'
+	if state.IsRelease() {
+		final = 'm'
+	}
+	h.Input(fmt.Sprintf("\x1b[<%d;%d;%d%c", int(state&^ansi.MouseRelease), pt.X, pt.Y, final))
+}
+
+// Paste queues pasted text for the next Frame, surrounded by bracketed paste
+// markers.
+func (h *Headless) Paste(s string) {
+	h.Input("\x1b[200~" + s + "\x1b[201~")
+}
+
+// Resize changes the screen size at the start of the next Frame, as if the
+// terminal were resized.
+func (h *Headless) Resize(size image.Point) {
+	h.resize = size
+}
+
+// Frame runs one frame, as Platform.Run would: any queued input replaces the
+// prior frame's events, the client updates under the HUD, and output is
+// flushed to Screen. Then Time advances by Period, or to the next tick of any
+// other clock. Returns any client error, or an error satisfying
+// IsClockStopped if the clock stopped ticking (or IsReplayDone, if replaying).
+func (h *Headless) Frame() error {
+	p := h.Platform
+	if p.Time.IsZero() {
+		if p.replay != nil {
+			return errReplayDone
+		}
+		return errClockStopped
+	}
+	if err := p.Telemetry.update(p); err != nil {
+		return err
+	}
+
+	ctx := p.Context()
+	if h.resize != image.ZP {
+		p.screen.Invalidate()
+		p.screen.Resize(h.resize)
+		h.Screen.Resize(h.resize)
+		h.resize = image.ZP
+	}
+	if len(h.input) > 0 {
+		p.events.Clear()
+		p.events.DecodeBytes(h.input)
+		h.input = h.input[:0]
+	}
+
+	if ctx.Update(); ctx.Err == nil {
+		_, ctx.Err = p.screen.WriteTo(&h.Screen)
+	}
+	if p.ticker.clock == h.step {
+		// either may have been changed since the last frame
+		h.step.T, h.step.Step = p.Time, h.Period
+	}
+	p.Time = p.ticker.Wait()
+	return ctx.Err
+}
+
+// Frames runs n frames, stopping early on error.
+func (h *Headless) Frames(n int) error {
+	for i := 0; i < n; i++ {
+		if err := h.Frame(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
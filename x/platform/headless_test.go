@@ -0,0 +1,112 @@
+package platform_test
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi/ansi"
+	anansitest "github.com/jcorbin/anansi/test"
+	. "github.com/jcorbin/anansi/x/platform"
+)
+
+// eventLogger is a test client that prints a summary of each frame.
+type eventLogger struct {
+	frames int
+	start  time.Time
+	typed  []rune
+	moves  image.Point
+	paste  string
+	last   string
+}
+
+func (el *eventLogger) Update(ctx *Context) error {
+	if el.frames == 0 {
+		el.start = ctx.Time
+	}
+	el.frames++
+
+	pasting := false
+	for eid, kind := range ctx.Input.Type {
+		switch kind {
+		case EventRune:
+			if pasting {
+				el.paste += string(ctx.Input.Rune(eid))
+			} else {
+				el.typed = append(el.typed, ctx.Input.Rune(eid))
+			}
+		case EventEscape:
+			switch esc := ctx.Input.Escape(eid); {
+			case esc.ID == ansi.CSI('~') && string(esc.Arg) == "200":
+				pasting, el.paste = true, ""
+			case esc.ID == ansi.CSI('~') && string(esc.Arg) == "201":
+				pasting = false
+			default:
+				if d, isMove := ansi.DecodeCursorCardinal(esc.ID, esc.Arg); isMove {
+					el.moves = el.moves.Add(d)
+				}
+			}
+		case EventMouse:
+			el.last = ctx.Input.Mouse(eid).String()
+		}
+	}
+	ctx.Input.Clear()
+
+	ctx.Output.To(ansi.Pt(1, 1))
+	fmt.Fprintf(ctx.Output, "frame %d at %v\r\n", el.frames, ctx.Time.Sub(el.start))
+	fmt.Fprintf(ctx.Output, "typed %q moves %v\r\n", string(el.typed), el.moves)
+	fmt.Fprintf(ctx.Output, "paste %q\r\n", el.paste)
+	fmt.Fprintf(ctx.Output, "mouse %v\r\n", el.last)
+	fmt.Fprintf(ctx.Output, "size %v", ctx.Output.Bounds().Size())
+	return nil
+}
+
+func TestHeadless(t *testing.T) {
+	var el eventLogger
+	h := NewHeadless(image.Pt(40, 6), &el)
+	h.Period = 100 * time.Millisecond
+	defer h.Close()
+
+	lines := func() string {
+		return strings.Join(anansitest.ScreenLines(h.Screen.Screen), "\n")
+	}
+
+	require.NoError(t, h.Frame())
+	assert.Equal(t, strings.Join([]string{
+		`frame 1 at 0s`,
+		`typed "" moves (0,0)`,
+		`paste ""`,
+		`mouse`,
+		`size (40,6)`,
+	}, "\n"), lines())
+
+	h.Input("hi")
+	h.Keys(ansi.CUU, ansi.CUF)
+	h.Mouse(ansi.MouseButton1, ansi.Pt(3, 4))
+	h.Paste("pasted")
+	require.NoError(t, h.Frames(3))
+	assert.Equal(t, strings.Join([]string{
+		`frame 4 at 300ms`,
+		`typed "hi" moves (1,-1)`,
+		`paste "pasted"`,
+		`mouse left@(3,4)`,
+		`size (40,6)`,
+	}, "\n"), lines())
+
+	h.Resize(image.Pt(30, 5))
+	h.Mouse(ansi.MouseButton1|ansi.MouseRelease, ansi.Pt(1, 1))
+	require.NoError(t, h.Frame())
+	assert.Equal(t, image.Pt(30, 5), h.Screen.Bounds().Size())
+	assert.Equal(t, strings.Join([]string{
+		`frame 5 at 400ms`,
+		`typed "hi" moves (1,-1)`,
+		`paste "pasted"`,
+		`mouse left-release@(1,1)`,
+		`size (30,5)`,
+	}, "\n"), lines())
+}
@@ -42,6 +42,7 @@ func New(in, out *os.File, opts ...Option) (*Platform, error) {
 		&p.stop,
 		&p.resize,
 		&p.screen,
+		&p.Clipboard,
 		&p.Config,
 		&p.ticker,
 		&p.bg,
@@ -52,22 +53,23 @@ func New(in, out *os.File, opts ...Option) (*Platform, error) {
 		return nil, err
 	}
 
+	if err := p.term.LoadTerminfo(""); err != nil {
+		log.Printf("unable to load terminfo, using ANSI fallbacks: %v", err)
+	}
+
 	_ = p.term.SetRaw(true)
+	p.term.AddAlternateScreen()
 	p.term.AddMode(
-		ansi.ModeAlternateScreen,
 		ansi.ModeMouseSgrExt,   // TODO detection?
 		ansi.ModeMouseBtnEvent, // TODO options?
 		ansi.ModeMouseAnyEvent, // TODO options?
 	)
-	p.term.AddModeSeq(ansi.SoftReset, ansi.SGRReset) // TODO options?
+	p.term.AddModeSeq(ansi.SoftReset) // TODO options?
+	sgr0 := p.term.Caps().SGR0
+	p.term.AddModeString(sgr0, sgr0)
 
 	p.ticker.d = time.Second / defaultFrameRate
-
-	timingPeriod := defaultFrameRate / 4
-	p.FPSEstimate.data = make([]float64, defaultFrameRate)
-	p.Timing.ts = make([]time.Time, timingPeriod)
-	p.Timing.ds = make([]time.Duration, timingPeriod)
-	p.Telemetry.coll.rusage.data = make([]rusageEntry, defaultFrameRate*10)
+	p.initTelemetry()
 	p.bg.workers = append(p.bg.workers, &p.Telemetry.coll, &Logs)
 
 	if !flag.Parsed() && !hasConfig(opts) {
@@ -95,6 +97,15 @@ func New(in, out *os.File, opts ...Option) (*Platform, error) {
 	return p, nil
 }
 
+// initTelemetry allocates telemetry collection buffers.
+func (p *Platform) initTelemetry() {
+	timingPeriod := defaultFrameRate / 4
+	p.FPSEstimate.data = make([]float64, defaultFrameRate)
+	p.Timing.ts = make([]time.Time, timingPeriod)
+	p.Timing.ds = make([]time.Duration, timingPeriod)
+	p.Telemetry.coll.rusage.data = make([]rusageEntry, defaultFrameRate*10)
+}
+
 // Platform is a high level abstraction for implementing frame-oriented
 // interactive fullscreen terminal programs.
 type Platform struct {
@@ -113,11 +124,15 @@ type Platform struct {
 	bg        BackgroundWorkers
 
 	State
-	Time   time.Time // internal time (rewinds during replay)
+	Time   time.Time // internal time from the Clock (rewinds during replay)
 	screen anansi.TermScreen
 
 	Telemetry
 
+	// Clipboard provides access to the terminal's selection buffers; see
+	// Context.ReadClipboard for reading under a client Update.
+	Clipboard anansi.Clipboard
+
 	client Client
 
 	HUD HUD
@@ -187,7 +202,7 @@ func (p *Platform) Run(client Client) (err error) {
 		log.Printf("run done: %v", err)
 	}()
 
-	for p.Time = time.Now(); !p.Time.IsZero(); p.Time = p.ticker.Wait() {
+	for p.Time = p.ticker.Now(); !p.Time.IsZero(); p.Time = p.ticker.Wait() {
 		// update performance data
 		if err := p.Telemetry.update(p); err != nil {
 			return err
@@ -273,7 +288,7 @@ func (ctx *Context) Update() {
 		// replay erased itself
 	}
 
-	if ctx.Redraw {
+	if ctx.Redraw && ctx.term != nil {
 		ctx.Err = errOr(ctx.Err, ctx.Output.SizeToTerm(ctx.term))
 	}
 
@@ -295,6 +310,17 @@ func (ctx *Context) runClient() error {
 	return err
 }
 
+// ReadClipboard queries the terminal's clipboard, blocking until it replies
+// (or Clipboard.Timeout elapses). Any other input that arrives while waiting
+// is added to the Input event queue.
+func (ctx *Context) ReadClipboard() ([]byte, error) {
+	data, err := ctx.Clipboard.Get()
+	if ctx.term != nil {
+		ctx.Input.DecodeInput(&ctx.term.Input)
+	}
+	return data, err
+}
+
 // Suspend restores terminal context to pre-platform-run settings, suspends the
 // current process, and then restores platform terminal context once resumed;
 // returns any error preventing any of that.
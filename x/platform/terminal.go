@@ -0,0 +1,150 @@
+package platform
+
+import (
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/jcorbin/anansi"
+	"github.com/jcorbin/anansi/ansi"
+	"github.com/jcorbin/anansi/pty"
+)
+
+// TerminalView embeds a child process terminal within a rectangle of the
+// platform screen.
+type TerminalView struct {
+	Term    *pty.Terminal
+	Box     ansi.Rectangle
+	Focused bool
+
+	buf []byte
+}
+
+// Update resizes the terminal to fit Box, forwards any input to it while
+// focused, and draws its screen into Box; the child's cursor is only shown
+// while focused.
+func (tv *TerminalView) Update(ctx *Context) error {
+	if tv.Term == nil || tv.Box.Empty() {
+		return nil
+	}
+
+	tv.Term.Lock()
+	size := tv.Term.Screen.Bounds().Size()
+	tv.Term.Unlock()
+	if size != tv.Box.Size() {
+		if err := tv.Term.Resize(tv.Box.Size()); err != nil {
+			return err
+		}
+	}
+
+	// NOTE the child's input is written after unlocking, since the reader
+	// goroutine may need the lock to drain output that the child is blocked
+	// writing before it reads more input.
+	tv.Term.Lock()
+	tv.buf = tv.buf[:0]
+	if tv.Focused {
+		for eid := range ctx.Input.Type {
+			tv.handleEvent(ctx, eid)
+		}
+	}
+	anansi.DrawGrid(ctx.Output.Grid.SubRect(tv.Box), tv.Term.Screen.Grid)
+	if cur := tv.Term.Screen.Cursor; tv.Focused && tv.Term.Mode(ansi.ShowCursor) && cur.Point.Valid() {
+		ctx.Output.UserCursor.Visible = true
+		ctx.Output.UserCursor.Point = tv.Box.Min.Add(cur.Point.Diff(ansi.Pt(1, 1)))
+	}
+	tv.Term.Unlock()
+
+	if len(tv.buf) > 0 {
+		if _, err := tv.Term.Write(tv.buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tv *TerminalView) handleEvent(ctx *Context, eid int) {
+	switch ctx.Input.Type[eid] {
+	case EventRune:
+		var tmp [4]byte
+		tv.buf = append(tv.buf, tmp[:utf8.EncodeRune(tmp[:], ctx.Input.Rune(eid))]...)
+
+	case EventEscape:
+		esc := ctx.Input.Escape(eid)
+		switch {
+		case isPasteMarker(esc) && !tv.Term.Mode(ansi.ModeBracketedPaste):
+			// child doesn't want paste brackets, but still gets the content
+
+		case isCursorKey(esc) && tv.Term.Mode(ansi.ModeCursorKeys):
+			c, _ := esc.ID.CSI()
+			tv.buf = append(tv.buf, '\x1b', 'O', c)
+
+		default:
+			tv.buf = esc.ID.AppendWith(tv.buf, esc.Arg...)
+		}
+
+	case EventMouse:
+		m := ctx.Input.Mouse(eid)
+		if !m.Point.In(tv.Box) {
+			return
+		}
+		if tv.wantsMouse(m.State) {
+			tv.appendMouse(m.State, ansi.Pt(1, 1).Add(m.Point.Diff(tv.Box.Min)))
+		}
+
+	default:
+		return
+	}
+	ctx.Input.Type[eid] = EventNone
+}
+
+func isPasteMarker(esc Escape) bool {
+	return esc.ID == ansi.CSI('~') && (string(esc.Arg) == "200" || string(esc.Arg) == "201")
+}
+
+func isCursorKey(esc Escape) bool {
+	switch esc.ID {
+	case ansi.CUU, ansi.CUD, ansi.CUF, ansi.CUB:
+		return len(esc.Arg) == 0
+	}
+	return false
+}
+
+// wantsMouse returns true if the child has enabled a mouse reporting mode
+// that covers the given mouse state.
+func (tv *TerminalView) wantsMouse(state ansi.MouseState) bool {
+	switch {
+	case tv.Term.Mode(ansi.ModeMouseAnyEvent):
+		return true
+	case state.IsMotion():
+		return state.IsDrag() && tv.Term.Mode(ansi.ModeMouseBtnEvent)
+	default:
+		return tv.Term.Mode(ansi.ModeMouseBtnEvent) ||
+			tv.Term.Mode(ansi.ModeMouseVt200)
+	}
+}
+
+// appendMouse encodes a mouse report for the child, using the SGR extended
+// encoding if enabled by the child, or the legacy X10 byte encoding otherwise
+// (dropping any report beyond its range).
+func (tv *TerminalView) appendMouse(state ansi.MouseState, pt ansi.Point) {
+	if tv.Term.Mode(ansi.ModeMouseSgrExt) {
+		final := byte('M')
+		if state.IsRelease() {
+			final = 'm'
+		}
+		tv.buf = append(tv.buf, '\x1b', '[', '<')
+		tv.buf = strconv.AppendInt(tv.buf, int64(state&^ansi.MouseRelease), 10)
+		tv.buf = append(tv.buf, ';')
+		tv.buf = strconv.AppendInt(tv.buf, int64(pt.X), 10)
+		tv.buf = append(tv.buf, ';')
+		tv.buf = strconv.AppendInt(tv.buf, int64(pt.Y), 10)
+		tv.buf = append(tv.buf, final)
+		return
+	}
+	if pt.X > 0xff-32 || pt.Y > 0xff-32 {
+		return
+	}
+	if state.IsRelease() {
+		state = state&^ansi.MouseRelease | ansi.MouseNoButton
+	}
+	tv.buf = append(tv.buf, '\x1b', '[', 'M', byte(32+state), byte(32+pt.X), byte(32+pt.Y))
+}
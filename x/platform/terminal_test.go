@@ -0,0 +1,63 @@
+package platform_test
+
+import (
+	"image"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/jcorbin/anansi/ansi"
+	"github.com/jcorbin/anansi/pty"
+	. "github.com/jcorbin/anansi/x/platform"
+)
+
+func TestTerminalView(t *testing.T) {
+	cmd := exec.Command("sh", "-c", `stty -echo; printf '\033[?1000;1006hready\r\n'; exec cat -v`)
+	term, err := pty.StartTerminal(cmd, image.Pt(30, 4))
+	if err != nil {
+		t.Skipf("unable to start terminal: %v", err)
+	}
+	defer term.Close()
+
+	tv := TerminalView{Term: term, Box: ansi.Rect(5, 3, 35, 7), Focused: true}
+	p := NewTest(image.Pt(40, 10), ClientFunc(tv.Update))
+
+	screenLine := func(y int) string {
+		term.Lock()
+		defer term.Unlock()
+		var sb strings.Builder
+		for x := 1; x <= 30; x++ {
+			i, _ := term.Screen.CellOffset(ansi.Pt(x, y))
+			if r := term.Screen.Rune[i]; r != 0 {
+				sb.WriteRune(r)
+			}
+		}
+		return sb.String()
+	}
+	waitLine := func(y int, expected string) {
+		deadline := time.After(5 * time.Second)
+		for screenLine(y) != expected {
+			select {
+			case <-term.Updated:
+			case <-deadline:
+				require.Equal(t, expected, screenLine(y), "timed out waiting for line %v", y)
+			}
+		}
+	}
+
+	update := func(in string) {
+		ctx := p.Context()
+		ctx.Input.Clear()
+		ctx.Input.DecodeBytes([]byte(in))
+		ctx.Update()
+		require.NoError(t, ctx.Err)
+		require.True(t, ctx.Input.Empty(), "expected all input to be forwarded")
+	}
+
+	waitLine(1, "ready")
+	update("\x1b[<0;6;4M" + "hi\x1b[A\r")
+	waitLine(2, "^[[<0;2;2Mhi^[[A")
+}
@@ -6,32 +6,34 @@ import (
 	"github.com/jcorbin/anansi"
 )
 
-// Ticker implements a contextual time.Ticker, start/stopping it during
+// Ticker implements a contextual ticker, start/stopping its Clock during
 // terminal enter/exit.
 type Ticker struct {
-	d time.Duration
-	t *time.Ticker
-	c chan struct{}
+	d     time.Duration
+	clock Clock
+	c     chan struct{}
 	// TODO useful to indirect t.C so that Enter can provide an immediate initial tick?
 }
 
-// Enter starts a new ticker, after stopping any prior one for good measure;
-// always returns nil error.
+// Enter starts the ticker's clock, after stopping any prior ticking for good
+// measure; uses a RealClock if none has been set. Always returns nil error.
 func (ct *Ticker) Enter(term *anansi.Term) error {
 	_ = ct.Exit(term)
 	if ct.d == 0 {
 		ct.d = time.Second / defaultFrameRate
 	}
-	ct.t = time.NewTicker(ct.d)
+	if ct.clock == nil {
+		ct.clock = &RealClock{}
+	}
+	ct.clock.Start(ct.d)
 	ct.c = make(chan struct{})
 	return nil
 }
 
-// Exit stops any running ticker; always returns nil error.
+// Exit stops any running clock; always returns nil error.
 func (ct *Ticker) Exit(term *anansi.Term) error {
-	if ct.t != nil {
-		ct.t.Stop()
-		ct.t = nil
+	if ct.clock != nil {
+		ct.clock.Stop()
 	}
 	if ct.c != nil {
 		close(ct.c)
@@ -40,17 +42,19 @@ func (ct *Ticker) Exit(term *anansi.Term) error {
 	return nil
 }
 
+// Now returns the clock's current time, or the wall time if there's no clock.
+func (ct *Ticker) Now() time.Time {
+	if ct.clock == nil {
+		return time.Now()
+	}
+	return ct.clock.Now()
+}
+
 // Wait blocks for the next ticker time, returning zero time if the Ticker
 // isn't active, or is Exit-ed first.
 func (ct *Ticker) Wait() time.Time {
-	var tc <-chan time.Time
-	if ct.t != nil {
-		tc = ct.t.C
-	}
-	select {
-	case t := <-tc:
-		return t
-	case <-ct.c:
+	if ct.clock == nil || ct.c == nil {
+		return time.Time{}
 	}
-	return time.Time{}
+	return ct.clock.Wait(ct.c)
 }